@@ -5,6 +5,8 @@ import (
 	"net"
 
 	"guiltmachine/internal/auth"
+	cacheDomain "guiltmachine/internal/cache/domain"
+	"guiltmachine/internal/services"
 	grpchandlers "guiltmachine/internal/transport/grpc"
 
 	"google.golang.org/grpc"
@@ -28,17 +30,24 @@ func StartGRPCServer(register func(*grpc.Server)) {
 	}
 }
 
-// StartGRPCServerWithAuth starts the gRPC server with JWT authentication interceptor
-func StartGRPCServerWithAuth(jwtManager *auth.JWTManager, register func(*grpc.Server)) {
+// StartGRPCServerWithAuth starts the gRPC server with JWT authentication and
+// resource-ownership interceptors
+func StartGRPCServerWithAuth(jwtManager *auth.JWTManager, sessions *cacheDomain.SessionCache, authz *services.Authorizer, register func(*grpc.Server)) {
 	l, err := net.Listen("tcp", ":9090")
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	// Create server with auth interceptors
+	// Create server with auth interceptors; authentication must run before policy
 	s := grpc.NewServer(
-		grpc.UnaryInterceptor(grpchandlers.AuthInterceptor(jwtManager)),
-		grpc.StreamInterceptor(grpchandlers.AuthStreamInterceptor(jwtManager)),
+		grpc.ChainUnaryInterceptor(
+			grpchandlers.AuthInterceptor(jwtManager, sessions),
+			grpchandlers.PolicyInterceptor(authz),
+		),
+		grpc.ChainStreamInterceptor(
+			grpchandlers.AuthStreamInterceptor(jwtManager, sessions),
+			grpchandlers.PolicyStreamInterceptor(authz),
+		),
 	)
 	register(s)
 	reflection.Register(s)
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"guiltmachine/internal/auth"
+)
+
+// StartJWKSServer serves the public JWT verification keys so other services
+// can validate our tokens without sharing a secret
+func StartJWKSServer(addr string, keys *auth.KeySet) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		doc, err := keys.JWKS()
+		if err != nil {
+			http.Error(w, "failed to render jwks", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		// short cache so verifiers pick up rotated keys quickly
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_, _ = w.Write(doc)
+	})
+
+	log.Printf("JWKS server listening on %s", addr)
+
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("failed to serve JWKS: %v", err)
+	}
+}
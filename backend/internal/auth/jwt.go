@@ -5,72 +5,211 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
 	ErrInvalidToken = errors.New("invalid token")
 	ErrExpiredToken = errors.New("token has expired")
+	ErrRevokedToken = errors.New("token has been revoked")
 )
 
-// JWTManager handles JWT token creation and verification
+const defaultRefreshTTL = 7 * 24 * time.Hour
+
+// TokenType distinguishes short-lived access tokens from refresh tokens
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// Claims is the decoded content of a verified token
+type Claims struct {
+	UserID    string
+	SessionID string
+	TokenID   string
+	Type      TokenType
+	ExpiresAt time.Time
+}
+
+// TokenPair is an access token together with the refresh token that renews it
+type TokenPair struct {
+	AccessToken      string
+	RefreshToken     string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+}
+
+// JWTManager handles JWT token creation and verification.
+// Tokens are signed with the active key of a KeySet, or with a shared HMAC
+// secret when the manager was created from one.
 type JWTManager struct {
-	secret []byte
-	ttl    time.Duration
+	secret     []byte
+	keys       *KeySet
+	ttl        time.Duration
+	refreshTTL time.Duration
 }
 
 // NewJWTManager creates a new JWTManager with the given secret and TTL
 func NewJWTManager(secret string, ttl time.Duration) *JWTManager {
+	return NewJWTManagerWithRefresh(secret, ttl, defaultRefreshTTL)
+}
+
+// NewJWTManagerWithRefresh creates a JWTManager with separate access and refresh TTLs
+func NewJWTManagerWithRefresh(secret string, accessTTL, refreshTTL time.Duration) *JWTManager {
+	return &JWTManager{
+		secret:     []byte(secret),
+		ttl:        accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// NewJWTManagerWithKeys creates a JWTManager that signs with asymmetric keys
+func NewJWTManagerWithKeys(keys *KeySet, accessTTL, refreshTTL time.Duration) *JWTManager {
 	return &JWTManager{
-		secret: []byte(secret),
-		ttl:    ttl,
+		keys:       keys,
+		ttl:        accessTTL,
+		refreshTTL: refreshTTL,
 	}
 }
 
-// Issue creates a new JWT token for the given user and session IDs
+// Issue creates a new access token for the given user and session IDs
 func (m *JWTManager) Issue(userID, sessionID string) (string, error) {
+	token, _, err := m.sign(userID, sessionID, TokenTypeAccess, m.ttl)
+	return token, err
+}
+
+// IssuePair creates an access token and a refresh token for the same session
+func (m *JWTManager) IssuePair(userID, sessionID string) (TokenPair, error) {
+	access, accessExp, err := m.sign(userID, sessionID, TokenTypeAccess, m.ttl)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, refreshExp, err := m.sign(userID, sessionID, TokenTypeRefresh, m.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		AccessExpiresAt:  accessExp,
+		RefreshExpiresAt: refreshExp,
+	}, nil
+}
+
+func (m *JWTManager) sign(userID, sessionID string, typ TokenType, ttl time.Duration) (string, time.Time, error) {
 	now := time.Now()
+	exp := now.Add(ttl)
 	claims := jwt.MapClaims{
 		"sub": userID,
 		"sid": sessionID,
+		"jti": uuid.NewString(),
+		"typ": string(typ),
 		"iat": now.Unix(),
-		"exp": now.Add(m.ttl).Unix(),
+		"exp": exp.Unix(),
+	}
+
+	var signed string
+	var err error
+	if m.keys != nil {
+		key := m.keys.Active()
+		if key == nil {
+			return "", time.Time{}, ErrNoSigningKey
+		}
+		tkn := jwt.NewWithClaims(key.Method, claims)
+		tkn.Header["kid"] = key.ID
+		signed, err = tkn.SignedString(key.Private)
+	} else {
+		tkn := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signed, err = tkn.SignedString(m.secret)
 	}
-	tkn := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return tkn.SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, exp, nil
 }
 
-// Verify validates a JWT token and returns the user ID and session ID
+// Verify validates an access token and returns the user ID and session ID
 func (m *JWTManager) Verify(token string) (string, string, error) {
+	claims, err := m.Parse(token)
+	if err != nil {
+		return "", "", err
+	}
+	if claims.Type != TokenTypeAccess {
+		return "", "", ErrInvalidToken
+	}
+	return claims.UserID, claims.SessionID, nil
+}
+
+// VerifyRefresh validates a refresh token and returns its claims
+func (m *JWTManager) VerifyRefresh(token string) (Claims, error) {
+	claims, err := m.Parse(token)
+	if err != nil {
+		return Claims{}, err
+	}
+	if claims.Type != TokenTypeRefresh || claims.TokenID == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// Parse validates the signature and expiry of any token issued by this manager
+func (m *JWTManager) Parse(token string) (Claims, error) {
 	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if m.keys != nil {
+			return m.keys.verificationKey(t)
+		}
 		// Validate signing method
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidToken
 		}
 		return m.secret, nil
 	})
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return Claims{}, ErrExpiredToken
+	}
 	if err != nil {
-		return "", "", err
+		return Claims{}, err
 	}
 	if !parsed.Valid {
-		return "", "", ErrInvalidToken
+		return Claims{}, ErrInvalidToken
 	}
 
-	claims, ok := parsed.Claims.(jwt.MapClaims)
+	mc, ok := parsed.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", "", ErrInvalidToken
+		return Claims{}, ErrInvalidToken
 	}
 
-	userID, _ := claims["sub"].(string)
-	sessID, _ := claims["sid"].(string)
+	claims := Claims{Type: TokenTypeAccess}
+	claims.UserID, _ = mc["sub"].(string)
+	claims.SessionID, _ = mc["sid"].(string)
+	claims.TokenID, _ = mc["jti"].(string)
+	if typ, ok := mc["typ"].(string); ok {
+		claims.Type = TokenType(typ)
+	}
+	if exp, err := mc.GetExpirationTime(); err == nil && exp != nil {
+		claims.ExpiresAt = exp.Time
+	}
 
-	if userID == "" || sessID == "" {
-		return "", "", ErrInvalidToken
+	if claims.UserID == "" || claims.SessionID == "" {
+		return Claims{}, ErrInvalidToken
 	}
 
-	return userID, sessID, nil
+	return claims, nil
+}
+
+// Keys returns the asymmetric key set, or nil for HMAC managers
+func (m *JWTManager) Keys() *KeySet {
+	return m.keys
 }
 
-// TTL returns the token time-to-live duration
+// TTL returns the access token time-to-live duration
 func (m *JWTManager) TTL() time.Duration {
 	return m.ttl
 }
+
+// RefreshTTL returns the refresh token time-to-live duration
+func (m *JWTManager) RefreshTTL() time.Duration {
+	return m.refreshTTL
+}
@@ -0,0 +1,383 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no active signing key")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+const (
+	privateKeySuffix = ".pem"
+	publicKeySuffix  = ".pub.pem"
+
+	// kidLayout makes key IDs sort chronologically
+	kidLayout = "20060102T150405.000Z"
+
+	// unknownKeyReload is how often a token with an unknown kid may trigger
+	// a reload, so forged kids cannot keep the set re-reading the directory
+	unknownKeyReload = 5 * time.Second
+)
+
+// SigningKey is one entry of a KeySet. Private is nil for keys that are only
+// kept around to verify tokens issued before a rotation.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+	// Created is the time in a rotated key's ID, or the file's modification
+	// time for keys provisioned by hand
+	Created time.Time
+}
+
+// KeySet holds the asymmetric keys used to sign and verify JWTs.
+// Keys live in a directory as <kid>.pem (private) or <kid>.pub.pem (public only);
+// the newest private key is the active signing key. Rotated keys are named by
+// their UTC creation time; keys provisioned by hand date from their file.
+type KeySet struct {
+	dir string
+
+	mu     sync.RWMutex
+	keys   map[string]*SigningKey
+	active string
+
+	missMu       sync.Mutex
+	lastMissLoad time.Time
+}
+
+// LoadKeySet loads every key in dir, generating an Ed25519 key if none can sign
+func LoadKeySet(dir string) (*KeySet, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ks := &KeySet{dir: dir}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	if ks.Active() == nil {
+		if _, err := ks.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// Reload re-reads the key directory, picking up keys rotated by other processes
+func (ks *KeySet) Reload() error {
+	entries, err := os.ReadDir(ks.dir)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]*SigningKey{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, privateKeySuffix) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(ks.dir, name))
+		if err != nil {
+			return err
+		}
+		var key *SigningKey
+		if strings.HasSuffix(name, publicKeySuffix) {
+			key, err = parsePublicKey(strings.TrimSuffix(name, publicKeySuffix), b)
+		} else {
+			key, err = parsePrivateKey(strings.TrimSuffix(name, privateKeySuffix), b)
+		}
+		if err != nil {
+			return fmt.Errorf("load key %s: %w", name, err)
+		}
+		if key.Created, err = keyCreated(key.ID, e); err != nil {
+			return fmt.Errorf("load key %s: %w", name, err)
+		}
+		// a private key supersedes its public-only twin
+		if existing, ok := keys[key.ID]; ok && existing.Private != nil {
+			continue
+		}
+		keys[key.ID] = key
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys = keys
+	ks.active = newestSigningKey(keys)
+	return nil
+}
+
+// Rotate generates a new Ed25519 key, persists it and makes it the active key
+func (ks *KeySet) Rotate() (*SigningKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	created := time.Now().UTC()
+	id := created.Format(kidLayout)
+	for ks.keys[id] != nil {
+		created = created.Add(time.Millisecond)
+		id = created.Format(kidLayout)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(ks.dir, id+privateKeySuffix)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: priv, Public: pub, Created: created}
+	if ks.keys == nil {
+		ks.keys = map[string]*SigningKey{}
+	}
+	ks.keys[id] = key
+	ks.active = id
+	return key, nil
+}
+
+// Prune deletes keys created before cutoff. The active key is always kept.
+// Keys whose ID is not a rotation timestamp were provisioned by hand and are left alone.
+func (ks *KeySet) Prune(cutoff time.Time) ([]string, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	var removed []string
+	for id := range ks.keys {
+		if id == ks.active {
+			continue
+		}
+		created, err := time.Parse(kidLayout, id)
+		if err != nil || !created.Before(cutoff) {
+			continue
+		}
+		for _, suffix := range []string{publicKeySuffix, privateKeySuffix} {
+			if err := os.Remove(filepath.Join(ks.dir, id+suffix)); err != nil && !os.IsNotExist(err) {
+				return removed, err
+			}
+		}
+		delete(ks.keys, id)
+		removed = append(removed, id)
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// Active returns the current signing key, or nil if none is loaded
+func (ks *KeySet) Active() *SigningKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.keys[ks.active]
+}
+
+// Key returns the key with the given ID
+func (ks *KeySet) Key(id string) (*SigningKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok := ks.keys[id]
+	return key, ok
+}
+
+// ActiveCreatedAt reports when the active key was generated, if it was rotated in
+func (ks *KeySet) ActiveCreatedAt() (time.Time, bool) {
+	key := ks.Active()
+	if key == nil {
+		return time.Time{}, false
+	}
+	created, err := time.Parse(kidLayout, key.ID)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
+
+// jwk is a single JSON Web Key as defined by RFC 7517
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS renders every verification key as a JSON Web Key Set document
+func (ks *KeySet) JWKS() ([]byte, error) {
+	ks.mu.RLock()
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	doc := struct {
+		Keys []jwk `json:"keys"`
+	}{Keys: make([]jwk, 0, len(ids))}
+
+	for _, id := range ids {
+		key := ks.keys[id]
+		k := jwk{Kid: id, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.Public.(type) {
+		case ed25519.PublicKey:
+			k.Kty = "OKP"
+			k.Crv = "Ed25519"
+			k.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			k.Kty = "RSA"
+			k.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			k.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		default:
+			continue
+		}
+		doc.Keys = append(doc.Keys, k)
+	}
+	ks.mu.RUnlock()
+
+	return json.Marshal(doc)
+}
+
+// verificationKey is the jwt.Keyfunc for tokens signed by this set
+func (ks *KeySet) verificationKey(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrInvalidToken
+	}
+	key, ok := ks.Key(kid)
+	if !ok && ks.reloadForMiss() {
+		key, ok = ks.Key(kid)
+	}
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	// Reject algorithm substitution, e.g. HS256 signed with a public key
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	return key.Public, nil
+}
+
+// reloadForMiss reloads the key directory for a token signed by a key this
+// process has not seen, e.g. one rotated in by another replica, at most once
+// per unknownKeyReload. It reports whether it reloaded.
+func (ks *KeySet) reloadForMiss() bool {
+	ks.missMu.Lock()
+	defer ks.missMu.Unlock()
+	if time.Since(ks.lastMissLoad) < unknownKeyReload {
+		return false
+	}
+	ks.lastMissLoad = time.Now()
+	if err := ks.Reload(); err != nil {
+		log.Printf("jwt key reload for unknown kid failed: %v", err)
+		return false
+	}
+	return true
+}
+
+// keyCreated dates a key by its ID, falling back to the modification time of
+// its file for IDs that are not rotation timestamps
+func keyCreated(id string, file os.DirEntry) (time.Time, error) {
+	if created, err := time.Parse(kidLayout, id); err == nil {
+		return created, nil
+	}
+	info, err := file.Info()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime().UTC(), nil
+}
+
+// newestSigningKey is the private key created last, the greater kid on a tie
+func newestSigningKey(keys map[string]*SigningKey) string {
+	var active *SigningKey
+	for _, key := range keys {
+		if key.Private == nil {
+			continue
+		}
+		if active == nil || key.Created.After(active.Created) || (key.Created.Equal(active.Created) && key.ID > active.ID) {
+			active = key
+		}
+	}
+	if active == nil {
+		return ""
+	}
+	return active.ID
+}
+
+func parsePrivateKey(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch priv := parsed.(type) {
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: priv, Public: priv.Public()}, nil
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Private: priv, Public: &priv.PublicKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+}
+
+func parsePublicKey(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch pub := parsed.(type) {
+	case ed25519.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Public: pub}, nil
+	case *rsa.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Public: pub}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", parsed)
+	}
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrWeakPassword       = errors.New("password must be at least 8 characters")
+)
+
+// dummyHash is compared against when no user matches, so failed lookups
+// take roughly as long as failed password checks
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("guiltmachine-dummy-password"), bcrypt.DefaultCost)
+
+// HashPassword hashes a plaintext password with bcrypt
+func HashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword compares a plaintext password against a stored bcrypt hash
+func CheckPassword(hash, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// RejectPassword burns the same work as CheckPassword and always fails
+func RejectPassword(password string) error {
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+	return ErrInvalidCredentials
+}
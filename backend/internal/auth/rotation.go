@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// KeyRotator periodically rotates the active signing key and prunes keys
+// that can no longer have live tokens
+type KeyRotator struct {
+	keys      *KeySet
+	interval  time.Duration
+	retention time.Duration
+	check     time.Duration
+}
+
+// NewKeyRotator rotates keys older than interval and removes keys older than
+// retention. retention must exceed the longest token TTL, otherwise tokens
+// signed shortly before a rotation stop verifying early.
+func NewKeyRotator(keys *KeySet, interval, retention time.Duration) *KeyRotator {
+	check := interval / 10
+	if check > time.Hour {
+		check = time.Hour
+	}
+	if check < time.Second {
+		check = time.Second
+	}
+	return &KeyRotator{keys: keys, interval: interval, retention: retention, check: check}
+}
+
+// Run blocks until ctx is done, rotating and pruning on schedule
+func (r *KeyRotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.check)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RotateIfDue(time.Now()); err != nil {
+			log.Printf("jwt key rotation failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateIfDue reloads keys from disk, rotates if the active key is older than
+// the interval, and prunes expired keys
+func (r *KeyRotator) RotateIfDue(now time.Time) error {
+	// pick up keys rotated by other replicas sharing the directory
+	if err := r.keys.Reload(); err != nil {
+		return err
+	}
+
+	created, ok := r.keys.ActiveCreatedAt()
+	if r.keys.Active() == nil || (ok && now.Sub(created) >= r.interval) {
+		key, err := r.keys.Rotate()
+		if err != nil {
+			return err
+		}
+		log.Printf("jwt signing key rotated: kid=%s", key.ID)
+	}
+
+	removed, err := r.keys.Prune(now.Add(-r.retention))
+	if err != nil {
+		return err
+	}
+	for _, id := range removed {
+		log.Printf("jwt signing key pruned: kid=%s", id)
+	}
+	return nil
+}
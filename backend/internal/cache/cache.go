@@ -10,6 +10,8 @@ type Cache interface {
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
 	Get(ctx context.Context, key string) ([]byte, error)
 	Del(ctx context.Context, key string) error
+	// SetNX stores value only if key does not exist and reports whether it did
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
 
 	// Counter
 	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
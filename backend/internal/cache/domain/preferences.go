@@ -3,36 +3,84 @@ package domain
 import (
 	"context"
 	"encoding/json"
+	"log"
+	"strings"
 	"time"
 
 	basecache "guiltmachine/internal/cache"
 	"guiltmachine/internal/cache/redis"
 )
 
-// Example preferences structure; adapt to your actual model
+// preferencesRecordVersion changes whenever PreferencesRecord does; records
+// of another version are treated as missing
+const preferencesRecordVersion = 2
+
+// PreferencesRecord is a user's stored preferences. Found is false for users
+// without any, so those are cached too.
 type PreferencesRecord struct {
-	HumorIntensity int    `json:"humor_intensity"`
-	Persona        string `json:"persona"`
-	Timezone       string `json:"timezone"`
+	Version          int             `json:"v"`
+	Found            bool            `json:"found"`
+	ID               string          `json:"id,omitempty"`
+	Theme            *string         `json:"theme,omitempty"`
+	Notifications    bool            `json:"notifications"`
+	Metadata         json.RawMessage `json:"metadata,omitempty"`
+	HumorIntensity   int             `json:"humor_intensity"`
+	Persona          string          `json:"persona"`
+	Timezone         string          `json:"timezone"`
+	Language         string          `json:"language,omitempty"`
+	WorkStartMinute  *int32          `json:"work_start_minute,omitempty"`
+	WorkEndMinute    *int32          `json:"work_end_minute,omitempty"`
+	QuietStartMinute *int32          `json:"quiet_start_minute,omitempty"`
+	QuietEndMinute   *int32          `json:"quiet_end_minute,omitempty"`
+	NudgeChannels    []string        `json:"nudge_channels,omitempty"`
+	CreatedAt        time.Time       `json:"created_at,omitempty"`
+	UpdatedAt        time.Time       `json:"updated_at,omitempty"`
 }
 
 var preferencesTTL = 12 * time.Hour // Optional cache TTL
 
 type PreferencesCache struct {
-	cache basecache.Cache
+	cache         basecache.Cache
+	invalidations basecache.Invalidations
 }
 
+// NewPreferencesCache stores records in c. Processes that keep local copies
+// should pass a cache.TieredCache listening to the same invalidations, so
+// that changes made by other processes are seen immediately.
 func NewPreferencesCache(c basecache.Cache) *PreferencesCache {
 	return &PreferencesCache{cache: c}
 }
 
+// SetInvalidations announces every changed record through inv, so other
+// processes stop using what they loaded before the change
+func (p *PreferencesCache) SetInvalidations(inv basecache.Invalidations) {
+	p.invalidations = inv
+}
+
 func (p *PreferencesCache) SetPreferences(ctx context.Context, userID string, prefs PreferencesRecord) error {
+	prefs.Version = preferencesRecordVersion
 	b, err := json.Marshal(prefs)
 	if err != nil {
 		return err
 	}
 	key := redis.KeyPreferences(userID)
-	return p.cache.Set(ctx, key, b, preferencesTTL)
+	if err := p.cache.Set(ctx, key, b, preferencesTTL); err != nil {
+		return err
+	}
+	return p.publish(ctx, key)
+}
+
+// FillPreferences stores prefs unless a record is cached already, so a read
+// that raced with a write cannot replace the newer record. It reports
+// whether prefs was stored.
+func (p *PreferencesCache) FillPreferences(ctx context.Context, userID string, prefs PreferencesRecord) (bool, error) {
+	prefs.Version = preferencesRecordVersion
+	b, err := json.Marshal(prefs)
+	if err != nil {
+		return false, err
+	}
+	key := redis.KeyPreferences(userID)
+	return p.cache.SetNX(ctx, key, b, preferencesTTL)
 }
 
 func (p *PreferencesCache) GetPreferences(ctx context.Context, userID string) (PreferencesRecord, bool, error) {
@@ -48,10 +96,58 @@ func (p *PreferencesCache) GetPreferences(ctx context.Context, userID string) (P
 	if err := json.Unmarshal(b, &prefs); err != nil {
 		return PreferencesRecord{}, false, err
 	}
+	if prefs.Version != preferencesRecordVersion {
+		// drop it so FillPreferences can store the current version
+		_ = p.cache.Del(ctx, key)
+		return PreferencesRecord{}, false, nil
+	}
 	return prefs, true, nil
 }
 
 func (p *PreferencesCache) InvalidatePreferences(ctx context.Context, userID string) error {
 	key := redis.KeyPreferences(userID)
-	return p.cache.Del(ctx, key)
+	if err := p.cache.Del(ctx, key); err != nil {
+		return err
+	}
+	return p.publish(ctx, key)
+}
+
+// EvictPreferences drops the cached record of userID without announcing a
+// change, for records that may have been filled from a stale read
+func (p *PreferencesCache) EvictPreferences(ctx context.Context, userID string) error {
+	return p.cache.Del(ctx, redis.KeyPreferences(userID))
+}
+
+// ListenInvalidations calls changed with the user ID of every record changed
+// in any process, this one included, until ctx is done. A broken
+// subscription is retried; changes made in the meantime are missed.
+func (p *PreferencesCache) ListenInvalidations(ctx context.Context, changed func(userID string)) {
+	if p.invalidations == nil {
+		return
+	}
+	prefix := redis.KeyPreferences("")
+	for ctx.Err() == nil {
+		keys, err := p.invalidations.SubscribeInvalidations(ctx)
+		if err != nil {
+			log.Printf("subscribe to preferences invalidations: %v", err)
+		} else {
+			for key := range keys {
+				if userID, ok := strings.CutPrefix(key, prefix); ok {
+					changed(userID)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (p *PreferencesCache) publish(ctx context.Context, key string) error {
+	if p.invalidations == nil {
+		return nil
+	}
+	return p.invalidations.PublishInvalidation(ctx, key)
 }
@@ -55,3 +55,33 @@ func (s *SessionCache) DeleteSession(ctx context.Context, token string) error {
 	key := redis.KeySession(token)
 	return s.cache.Del(ctx, key)
 }
+
+// RevokeSession marks every token issued for the session as revoked.
+// ttl should cover the longest-lived token that may still be presented.
+func (s *SessionCache) RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error {
+	key := redis.KeyRevokedSession(sessionID)
+	return s.cache.Set(ctx, key, []byte("1"), ttl)
+}
+
+func (s *SessionCache) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	key := redis.KeyRevokedSession(sessionID)
+	_, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if err == basecache.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// MarkRefreshTokenUsed records a refresh token as spent. It reports whether
+// this was the first use, so a replayed token can be detected atomically.
+func (s *SessionCache) MarkRefreshTokenUsed(ctx context.Context, tokenID string, ttl time.Duration) (bool, error) {
+	key := redis.KeyRefreshTokenUsed(tokenID)
+	n, err := s.cache.Incr(ctx, key, ttl)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -4,4 +4,7 @@ import "errors"
 
 var (
 	ErrNotFound = errors.New("cache: key not found")
+	// ErrWrongType is returned for a list or set operation on a key holding
+	// another kind of value, or Incr on a value that is not an integer
+	ErrWrongType = errors.New("cache: wrong kind of value for key")
 )
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// Invalidations tells other processes that a key changed, so they drop any
+// copy they keep in memory. Delivery is best effort, like pub/sub: processes
+// that are not subscribed at publish time miss the message.
+type Invalidations interface {
+	PublishInvalidation(ctx context.Context, key string) error
+	// SubscribeInvalidations streams invalidated keys until ctx is done or
+	// the subscription breaks, then closes the channel
+	SubscribeInvalidations(ctx context.Context) (<-chan string, error)
+}
+
+// memoryInvalidationsBuffer is how many keys a slow subscriber may fall
+// behind before further keys for it are dropped
+const memoryInvalidationsBuffer = 64
+
+// MemoryInvalidations delivers invalidations within one process, for the
+// single-binary mode and tests
+type MemoryInvalidations struct {
+	mu   sync.Mutex
+	subs map[chan string]struct{}
+}
+
+func NewMemoryInvalidations() *MemoryInvalidations {
+	return &MemoryInvalidations{subs: map[chan string]struct{}{}}
+}
+
+func (m *MemoryInvalidations) PublishInvalidation(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for ch := range m.subs {
+		select {
+		case ch <- key:
+		default:
+		}
+	}
+	return nil
+}
+
+func (m *MemoryInvalidations) SubscribeInvalidations(ctx context.Context) (<-chan string, error) {
+	ch := make(chan string, memoryInvalidationsBuffer)
+
+	m.mu.Lock()
+	m.subs[ch] = struct{}{}
+	m.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.mu.Lock()
+		delete(m.subs, ch)
+		m.mu.Unlock()
+		close(ch)
+	}()
+	return ch, nil
+}
@@ -0,0 +1,237 @@
+// Package memory keeps cache.Cache data in process memory, for tests and
+// single-node mode. It follows the semantics of the Redis implementation.
+package memory
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"guiltmachine/internal/cache"
+)
+
+var _ cache.Cache = (*MemoryCache)(nil)
+
+// sweepInterval is how often expired keys are removed in bulk; until then
+// they are only hidden from reads
+const sweepInterval = time.Minute
+
+type kind int
+
+const (
+	kindValue kind = iota
+	kindList
+	kindSet
+)
+
+type entry struct {
+	kind    kind
+	value   []byte
+	list    [][]byte
+	set     map[string]struct{}
+	expires time.Time // zero means no expiry
+}
+
+type MemoryCache struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return NewMemoryCacheWithClock(time.Now)
+}
+
+// NewMemoryCacheWithClock lets tests control expiry
+func NewMemoryCacheWithClock(now func() time.Time) *MemoryCache {
+	return &MemoryCache{entries: map[string]*entry{}, now: now, lastSweep: now()}
+}
+
+// Set stores value, replacing any kind of value under key. A ttl of zero
+// keeps it until deleted.
+func (m *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	e := &entry{kind: kindValue, value: clone(value)}
+	if ttl > 0 {
+		e.expires = m.now().Add(ttl)
+	}
+	m.entries[key] = e
+	return nil
+}
+
+func (m *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.live(key)
+	if e == nil {
+		return nil, cache.ErrNotFound
+	}
+	if e.kind != kindValue {
+		return nil, cache.ErrWrongType
+	}
+	return clone(e.value), nil
+}
+
+// SetNX stores value like Set unless a live value of any kind is under key
+func (m *MemoryCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	if m.live(key) != nil {
+		return false, nil
+	}
+	e := &entry{kind: kindValue, value: clone(value)}
+	m.expire(e, ttl)
+	m.entries[key] = e
+	return true, nil
+}
+
+func (m *MemoryCache) Del(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// Flush drops every key
+func (m *MemoryCache) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = map[string]*entry{}
+	return nil
+}
+
+// Incr adds one to the integer under key, starting from zero. A ttl above
+// zero (re)sets the expiry, zero keeps the current one.
+func (m *MemoryCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	e := m.live(key)
+	if e == nil {
+		e = &entry{kind: kindValue}
+		m.entries[key] = e
+	}
+	if e.kind != kindValue {
+		return 0, cache.ErrWrongType
+	}
+	var n int64
+	if len(e.value) > 0 {
+		var err error
+		if n, err = strconv.ParseInt(string(e.value), 10, 64); err != nil {
+			return 0, cache.ErrWrongType
+		}
+	}
+	n++
+	e.value = []byte(strconv.FormatInt(n, 10))
+	m.expire(e, ttl)
+	return n, nil
+}
+
+// Push appends value to the list under key and returns its new length
+func (m *MemoryCache) Push(ctx context.Context, key string, value []byte, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	e := m.live(key)
+	if e == nil {
+		e = &entry{kind: kindList}
+		m.entries[key] = e
+	}
+	if e.kind != kindList {
+		return 0, cache.ErrWrongType
+	}
+	e.list = append(e.list, clone(value))
+	m.expire(e, ttl)
+	return int64(len(e.list)), nil
+}
+
+// Range returns the whole list under key, empty if there is none
+func (m *MemoryCache) Range(ctx context.Context, key string) ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.live(key)
+	if e == nil {
+		return [][]byte{}, nil
+	}
+	if e.kind != kindList {
+		return nil, cache.ErrWrongType
+	}
+	out := make([][]byte, len(e.list))
+	for i, v := range e.list {
+		out[i] = clone(v)
+	}
+	return out, nil
+}
+
+func (m *MemoryCache) Sadd(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep()
+	e := m.live(key)
+	if e == nil {
+		e = &entry{kind: kindSet, set: map[string]struct{}{}}
+		m.entries[key] = e
+	}
+	if e.kind != kindSet {
+		return cache.ErrWrongType
+	}
+	e.set[string(value)] = struct{}{}
+	m.expire(e, ttl)
+	return nil
+}
+
+// Scard returns the size of the set under key, zero if there is none
+func (m *MemoryCache) Scard(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.live(key)
+	if e == nil {
+		return 0, nil
+	}
+	if e.kind != kindSet {
+		return 0, cache.ErrWrongType
+	}
+	return int64(len(e.set)), nil
+}
+
+// live returns the unexpired entry under key; callers hold the lock
+func (m *MemoryCache) live(key string) *entry {
+	e, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	if !e.expires.IsZero() && !m.now().Before(e.expires) {
+		delete(m.entries, key)
+		return nil
+	}
+	return e
+}
+
+func (m *MemoryCache) expire(e *entry, ttl time.Duration) {
+	if ttl > 0 {
+		e.expires = m.now().Add(ttl)
+	}
+}
+
+// sweep drops expired entries once per sweepInterval; callers hold the lock
+func (m *MemoryCache) sweep() {
+	now := m.now()
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, e := range m.entries {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func clone(b []byte) []byte {
+	return append([]byte{}, b...)
+}
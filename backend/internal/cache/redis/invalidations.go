@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+
+	libredis "github.com/redis/go-redis/v9"
+
+	"guiltmachine/internal/cache"
+)
+
+var _ cache.Invalidations = (*RedisInvalidations)(nil)
+
+// RedisInvalidations broadcasts invalidated keys over one pub/sub channel
+type RedisInvalidations struct {
+	client  *libredis.Client
+	channel string
+}
+
+func NewRedisInvalidations(client *libredis.Client, channel string) *RedisInvalidations {
+	return &RedisInvalidations{client: client, channel: channel}
+}
+
+func (r *RedisInvalidations) PublishInvalidation(ctx context.Context, key string) error {
+	return r.client.Publish(ctx, r.channel, key).Err()
+}
+
+func (r *RedisInvalidations) SubscribeInvalidations(ctx context.Context) (<-chan string, error) {
+	sub := r.client.Subscribe(ctx, r.channel)
+	// wait for the confirmation so no invalidation published after we return is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, err
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
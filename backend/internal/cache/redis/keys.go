@@ -21,3 +21,14 @@ func KeySet(name string) string {
 func KeyCounter(name string) string {
 	return fmt.Sprintf("CTR:%s", name)
 }
+
+func KeyRevokedSession(sessionID string) string {
+	return fmt.Sprintf("RVK:SES:%s", sessionID)
+}
+
+func KeyRefreshTokenUsed(tokenID string) string {
+	return fmt.Sprintf("RVK:JTI:%s", tokenID)
+}
+
+// ChannelPreferencesInvalidations carries the keys of changed preferences
+const ChannelPreferencesInvalidations = "INV:PREF"
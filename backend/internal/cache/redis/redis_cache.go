@@ -37,6 +37,10 @@ func (r *RedisCache) Del(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+func (r *RedisCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 func (r *RedisCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
 	val, err := r.client.Incr(ctx, key).Result()
 	if err != nil {
@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+var _ Cache = (*TieredCache)(nil)
+
+// TieredCache keeps hot values of a shared cache (L2, e.g. Redis) in a
+// process-local one (L1) for up to l1TTL. Counters, lists and sets go to L2
+// only. Writers announce their changes, e.g. PreferencesCache.SetInvalidations;
+// while ListenInvalidations runs those evict the L1 copies, otherwise other
+// processes may serve stale values for up to l1TTL.
+type TieredCache struct {
+	l1    LocalCache
+	l2    Cache
+	l1TTL time.Duration
+
+	// mu orders L1 fills after L2 reads against evictions of the same key
+	mu sync.Mutex
+	// reads tracks the keys being read from L2 to fill L1
+	reads map[string]*tieredRead
+}
+
+// LocalCache is a process-local Cache that can drop every key at once
+type LocalCache interface {
+	Cache
+	Flush(ctx context.Context) error
+}
+
+// tieredRead counts the evictions of a key while reads of it are in flight
+type tieredRead struct {
+	readers    int
+	generation uint64
+}
+
+func NewTieredCache(l1 LocalCache, l2 Cache, l1TTL time.Duration) *TieredCache {
+	return &TieredCache{l1: l1, l2: l2, l1TTL: l1TTL, reads: map[string]*tieredRead{}}
+}
+
+// ListenInvalidations evicts L1 copies of the keys announced on inv until ctx
+// is done. A broken subscription is retried, and L1 is flushed once it is
+// back since announcements in between are lost.
+func (t *TieredCache) ListenInvalidations(ctx context.Context, inv Invalidations) {
+	for subscribed := false; ctx.Err() == nil; {
+		keys, err := inv.SubscribeInvalidations(ctx)
+		if err != nil {
+			log.Printf("cache: subscribe to invalidations: %v", err)
+		} else {
+			if subscribed {
+				t.flush(ctx)
+			}
+			subscribed = true
+			for key := range keys {
+				t.evict(ctx, key)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (t *TieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := t.l2.Set(ctx, key, value, ttl)
+	// a read of the old value must not fill L1 after this
+	t.evict(ctx, key)
+	if err != nil {
+		return err
+	}
+	return t.l1.Set(ctx, key, value, t.localTTL(ttl))
+}
+
+// Get serves L1 copies and fills L1 on a miss, unless the key was evicted
+// while L2 was read
+func (t *TieredCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if v, err := t.l1.Get(ctx, key); err == nil {
+		return v, nil
+	}
+	generation := t.beginRead(key)
+	v, err := t.l2.Get(ctx, key)
+	t.endRead(ctx, key, generation, v, err == nil)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (t *TieredCache) Del(ctx context.Context, key string) error {
+	t.evict(ctx, key)
+	return t.l2.Del(ctx, key)
+}
+
+// SetNX only checks L2; the L1 copy of a key that was absent there is stale
+// anyway, so it is replaced or dropped
+func (t *TieredCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	ok, err := t.l2.SetNX(ctx, key, value, ttl)
+	t.evict(ctx, key)
+	if err != nil || !ok {
+		return ok, err
+	}
+	_ = t.l1.Set(ctx, key, value, t.localTTL(ttl))
+	return true, nil
+}
+
+func (t *TieredCache) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return t.l2.Incr(ctx, key, ttl)
+}
+
+func (t *TieredCache) Push(ctx context.Context, key string, value []byte, ttl time.Duration) (int64, error) {
+	return t.l2.Push(ctx, key, value, ttl)
+}
+
+func (t *TieredCache) Range(ctx context.Context, key string) ([][]byte, error) {
+	return t.l2.Range(ctx, key)
+}
+
+func (t *TieredCache) Sadd(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return t.l2.Sadd(ctx, key, value, ttl)
+}
+
+func (t *TieredCache) Scard(ctx context.Context, key string) (int64, error) {
+	return t.l2.Scard(ctx, key)
+}
+
+// beginRead registers a read of key from L2 and returns its generation
+func (t *TieredCache) beginRead(key string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r := t.reads[key]
+	if r == nil {
+		r = &tieredRead{}
+		t.reads[key] = r
+	}
+	r.readers++
+	return r.generation
+}
+
+// endRead fills L1 with what was read, if found, unless key was evicted
+// since beginRead returned generation
+func (t *TieredCache) endRead(ctx context.Context, key string, generation uint64, value []byte, found bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r := t.reads[key]
+	if found && r.generation == generation {
+		_ = t.l1.Set(ctx, key, value, t.l1TTL)
+	}
+	if r.readers--; r.readers == 0 {
+		delete(t.reads, key)
+	}
+}
+
+// evict drops the L1 copy of key and makes reads in flight skip their fill
+func (t *TieredCache) evict(ctx context.Context, key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r := t.reads[key]; r != nil {
+		r.generation++
+	}
+	_ = t.l1.Del(ctx, key)
+}
+
+// flush drops every L1 copy and makes every read in flight skip its fill
+func (t *TieredCache) flush(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range t.reads {
+		r.generation++
+	}
+	if err := t.l1.Flush(ctx); err != nil {
+		log.Printf("cache: flush local copies: %v", err)
+	}
+}
+
+// localTTL keeps L1 copies no longer than the value lives in L2
+func (t *TieredCache) localTTL(ttl time.Duration) time.Duration {
+	if ttl > 0 && ttl < t.l1TTL {
+		return ttl
+	}
+	return t.l1TTL
+}
@@ -0,0 +1,37 @@
+// Package config builds the parts the api and worker binaries share from
+// environment variables, so both read them the same way.
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("invalid %s=%q, using %s", key, value, fallback)
+	}
+	return fallback
+}
+
+func getIntEnv(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		log.Printf("invalid %s=%q, using %d", key, value, fallback)
+	}
+	return fallback
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/services"
+)
+
+// NewOrchestrator wraps NewLLM with a circuit breaker and template roasts for
+// when the LLM is down. ML_TEMPLATES_FILE and ML_SCORING_MODEL_FILE replace
+// the built-in templates and scoring model.
+func NewOrchestrator() (*ml.HybridOrchestrator, error) {
+	templates := ml.DefaultTemplates()
+	if path := getEnv("ML_TEMPLATES_FILE", ""); path != "" {
+		var err error
+		if templates, err = ml.LoadTemplates(path); err != nil {
+			return nil, fmt.Errorf("load templates: %w", err)
+		}
+		log.Printf("using roast templates v%d from %s", templates.Version, path)
+	}
+	llm, err := NewLLM()
+	if err != nil {
+		return nil, err
+	}
+	breaker := ml.NewCircuitBreaker(
+		getIntEnv("ML_BREAKER_FAILURES", 5),
+		getDurationEnv("ML_BREAKER_COOLDOWN", 30*time.Second),
+	)
+	orchestrator := ml.NewHybridOrchestratorWithFallback(llm, templates, breaker)
+
+	if path := getEnv("ML_SCORING_MODEL_FILE", ""); path != "" {
+		model, err := ml.LoadScoringModel(path)
+		if err != nil {
+			return nil, fmt.Errorf("load scoring model: %w", err)
+		}
+		log.Printf("using scoring model %s from %s", model.Version, path)
+		orchestrator.SetScoringModel(model)
+	}
+	return orchestrator, nil
+}
+
+// NewHistoryConfig reads how much of the user's earlier entries goes into the
+// LLM context: HISTORY_ENTRIES (0 disables), HISTORY_ACROSS_SESSIONS and
+// HISTORY_MAX_CHARS
+func NewHistoryConfig() services.HistoryConfig {
+	return services.HistoryConfig{
+		Entries:        getIntEnv("HISTORY_ENTRIES", 5),
+		AcrossSessions: getEnv("HISTORY_ACROSS_SESSIONS", "false") == "true",
+		MaxChars:       getIntEnv("HISTORY_MAX_CHARS", 2000),
+	}
+}
+
+// NewLLM picks the roast generator from LLM_PROVIDER: "stub" (default) or
+// "openai" for any OpenAI-compatible server
+func NewLLM() (ml.LLM, error) {
+	switch provider := getEnv("LLM_PROVIDER", "stub"); provider {
+	case "stub":
+		return ml.NewInferenceStub(), nil
+	case "openai":
+		cfg := ml.DefaultOpenAIConfig()
+		cfg.BaseURL = getEnv("LLM_BASE_URL", cfg.BaseURL)
+		cfg.APIKey = getEnv("LLM_API_KEY", "")
+		cfg.Model = getEnv("LLM_MODEL", cfg.Model)
+		cfg.Timeout = getDurationEnv("LLM_TIMEOUT", cfg.Timeout)
+		cfg.MaxTokens = getIntEnv("LLM_MAX_TOKENS", cfg.MaxTokens)
+		log.Printf("using llm %s at %s", cfg.Model, cfg.BaseURL)
+		return ml.NewOpenAIClient(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM_PROVIDER %q (want stub or openai)", provider)
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import "guiltmachine/internal/services"
+
+// NewSnoozeConfig reads the snooze limits, falling back to
+// services.DefaultSnoozeConfig: SNOOZE_MAX (0 means no limit),
+// SNOOZE_MIN_DURATION, SNOOZE_MAX_DURATION and SNOOZE_ROAST_AFTER (0 disables
+// snooze roasts)
+func NewSnoozeConfig() services.SnoozeConfig {
+	cfg := services.DefaultSnoozeConfig
+	return services.SnoozeConfig{
+		MaxSnoozes:  int32(getIntEnv("SNOOZE_MAX", int(cfg.MaxSnoozes))),
+		MinDuration: getDurationEnv("SNOOZE_MIN_DURATION", cfg.MinDuration),
+		MaxDuration: getDurationEnv("SNOOZE_MAX_DURATION", cfg.MaxDuration),
+		RoastAfter:  int32(getIntEnv("SNOOZE_ROAST_AFTER", int(cfg.RoastAfter))),
+	}
+}
@@ -13,6 +13,26 @@ import (
 	"github.com/google/uuid"
 )
 
+const claimEntryForProcessing = `-- name: ClaimEntryForProcessing :execrows
+UPDATE guilt_entries
+SET status = 'processing', ml_job_key = $2, ml_attempts = ml_attempts + 1
+WHERE id = $1
+  AND (status IN ('pending', 'failed') OR (status = 'processing' AND ml_job_key = $2))
+`
+
+type ClaimEntryForProcessingParams struct {
+	ID       uuid.UUID
+	MlJobKey sql.NullString
+}
+
+func (q *Queries) ClaimEntryForProcessing(ctx context.Context, arg ClaimEntryForProcessingParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimEntryForProcessing, arg.ID, arg.MlJobKey)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO guilt_entries (
     session_id,
@@ -64,6 +84,26 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Creat
 	return i, err
 }
 
+const finishEntryProcessing = `-- name: FinishEntryProcessing :execrows
+UPDATE guilt_entries
+SET status = $3
+WHERE id = $1 AND status = 'processing' AND ml_job_key = $2
+`
+
+type FinishEntryProcessingParams struct {
+	ID       uuid.UUID
+	MlJobKey sql.NullString
+	Status   sql.NullString
+}
+
+func (q *Queries) FinishEntryProcessing(ctx context.Context, arg FinishEntryProcessingParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, finishEntryProcessing, arg.ID, arg.MlJobKey, arg.Status)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getEntry = `-- name: GetEntry :one
 SELECT
     id,
@@ -105,6 +145,75 @@ func (q *Queries) GetEntry(ctx context.Context, id uuid.UUID) (GetEntryRow, erro
 	return i, err
 }
 
+const listEntriesBefore = `-- name: ListEntriesBefore :many
+SELECT
+    e.id,
+    e.session_id,
+    e.entry_text,
+    e.guilt_level,
+    e.roast_text,
+    e.status,
+    e.created_at,
+    e.updated_at
+FROM guilt_entries e
+JOIN guilt_sessions s ON s.id = e.session_id
+JOIN guilt_entries cur ON cur.id = $1
+JOIN guilt_sessions cur_s ON cur_s.id = cur.session_id
+WHERE e.created_at < cur.created_at
+  AND (e.session_id = cur.session_id OR ($2::bool AND s.user_id = cur_s.user_id))
+ORDER BY e.created_at DESC
+LIMIT $3
+`
+
+type ListEntriesBeforeParams struct {
+	ID             uuid.UUID
+	AcrossSessions bool
+	Limit          int32
+}
+
+type ListEntriesBeforeRow struct {
+	ID         uuid.UUID
+	SessionID  uuid.UUID
+	EntryText  string
+	GuiltLevel sql.NullInt32
+	RoastText  sql.NullString
+	Status     sql.NullString
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (q *Queries) ListEntriesBefore(ctx context.Context, arg ListEntriesBeforeParams) ([]ListEntriesBeforeRow, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesBefore, arg.ID, arg.AcrossSessions, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListEntriesBeforeRow
+	for rows.Next() {
+		var i ListEntriesBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SessionID,
+			&i.EntryText,
+			&i.GuiltLevel,
+			&i.RoastText,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntriesBySession = `-- name: ListEntriesBySession :many
 SELECT
     id,
@@ -112,6 +221,7 @@ SELECT
     entry_text,
     guilt_level,
     roast_text,
+    status,
     created_at,
     updated_at
 FROM guilt_entries
@@ -125,6 +235,7 @@ type ListEntriesBySessionRow struct {
 	EntryText  string
 	GuiltLevel sql.NullInt32
 	RoastText  sql.NullString
+	Status     sql.NullString
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
@@ -144,6 +255,7 @@ func (q *Queries) ListEntriesBySession(ctx context.Context, sessionID uuid.UUID)
 			&i.EntryText,
 			&i.GuiltLevel,
 			&i.RoastText,
+			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
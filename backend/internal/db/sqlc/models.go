@@ -6,12 +6,34 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
+type ClusterModel struct {
+	Version    int32
+	Centroids  json.RawMessage
+	SampleSize int32
+	CreatedAt  time.Time
+}
+
+type FeatureSnapshot struct {
+	ID                   uuid.UUID
+	UserID               uuid.UUID
+	ProcrastinationScore float64
+	SnoozeRate           float64
+	CompletionLatencyAvg float64
+	BurstinessIndex      float64
+	ModeCluster          int32
+	ClusterVersion       int32
+	WindowStart          time.Time
+	WindowEnd            time.Time
+	CreatedAt            time.Time
+}
+
 type GuiltEntry struct {
 	ID         uuid.UUID
 	SessionID  uuid.UUID
@@ -21,6 +43,8 @@ type GuiltEntry struct {
 	UpdatedAt  time.Time
 	RoastText  sql.NullString
 	Status     sql.NullString
+	MlJobKey   sql.NullString
+	MlAttempts int32
 }
 
 type GuiltScore struct {
@@ -43,6 +67,58 @@ type GuiltSession struct {
 	UpdatedAt time.Time
 }
 
+type Job struct {
+	ID          int64
+	Queue       string
+	Payload     json.RawMessage
+	Status      string
+	Attempts    int32
+	LastError   sql.NullString
+	RunAt       time.Time
+	LockedBy    sql.NullString
+	LockedUntil sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Outbox struct {
+	ID           int64
+	Topic        string
+	Payload      json.RawMessage
+	Attempts     int32
+	LastError    sql.NullString
+	CreatedAt    time.Time
+	SentAt       sql.NullTime
+	DeadAt       sql.NullTime
+	ClaimedUntil sql.NullTime
+	EntryID      uuid.NullUUID
+}
+
+type Task struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	Title            string
+	Description      sql.NullString
+	Status           string
+	DueAt            sql.NullTime
+	Category         sql.NullString
+	Priority         int16
+	EstimatedMinutes sql.NullInt32
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	DeletedAt        sql.NullTime
+	SnoozeCount      int32
+}
+
+type TaskEvent struct {
+	ID           uuid.UUID
+	TaskID       uuid.UUID
+	UserID       uuid.UUID
+	EventType    string
+	EventPayload json.RawMessage
+	CreatedAt    time.Time
+}
+
 type User struct {
 	ID           uuid.UUID
 	Email        string
@@ -59,4 +135,13 @@ type UserPreference struct {
 	Metadata             pqtype.NullRawMessage
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
+	Persona              string
+	HumorIntensity       int32
+	Timezone             string
+	Language             string
+	WorkStartMinute      sql.NullInt32
+	WorkEndMinute        sql.NullInt32
+	QuietStartMinute     sql.NullInt32
+	QuietEndMinute       sql.NullInt32
+	NudgeChannels        []string
 }
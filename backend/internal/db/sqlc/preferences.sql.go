@@ -10,9 +10,21 @@ import (
 	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sqlc-dev/pqtype"
 )
 
+const ensureUserPreferences = `-- name: EnsureUserPreferences :exec
+INSERT INTO user_preferences (user_id)
+VALUES ($1)
+ON CONFLICT (user_id) DO NOTHING
+`
+
+func (q *Queries) EnsureUserPreferences(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, ensureUserPreferences, userID)
+	return err
+}
+
 const getPreferencesByUserID = `-- name: GetPreferencesByUserID :one
 SELECT
     id,
@@ -21,7 +33,16 @@ SELECT
     notifications_enabled,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels
 FROM user_preferences
 WHERE user_id = $1
 `
@@ -37,6 +58,62 @@ func (q *Queries) GetPreferencesByUserID(ctx context.Context, userID uuid.UUID)
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Persona,
+		&i.HumorIntensity,
+		&i.Timezone,
+		&i.Language,
+		&i.WorkStartMinute,
+		&i.WorkEndMinute,
+		&i.QuietStartMinute,
+		&i.QuietEndMinute,
+		pq.Array(&i.NudgeChannels),
+	)
+	return i, err
+}
+
+const getPreferencesByUserIDForUpdate = `-- name: GetPreferencesByUserIDForUpdate :one
+SELECT
+    id,
+    user_id,
+    theme,
+    notifications_enabled,
+    metadata,
+    created_at,
+    updated_at,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels
+FROM user_preferences
+WHERE user_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetPreferencesByUserIDForUpdate(ctx context.Context, userID uuid.UUID) (UserPreference, error) {
+	row := q.db.QueryRowContext(ctx, getPreferencesByUserIDForUpdate, userID)
+	var i UserPreference
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Theme,
+		&i.NotificationsEnabled,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Persona,
+		&i.HumorIntensity,
+		&i.Timezone,
+		&i.Language,
+		&i.WorkStartMinute,
+		&i.WorkEndMinute,
+		&i.QuietStartMinute,
+		&i.QuietEndMinute,
+		pq.Array(&i.NudgeChannels),
 	)
 	return i, err
 }
@@ -46,17 +123,44 @@ INSERT INTO user_preferences (
     user_id,
     theme,
     notifications_enabled,
-    metadata
+    metadata,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels
 ) VALUES (
     $1,
     $2,
     $3,
-    $4
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
 )
 ON CONFLICT (user_id) DO UPDATE SET
     theme = EXCLUDED.theme,
     notifications_enabled = EXCLUDED.notifications_enabled,
     metadata = EXCLUDED.metadata,
+    persona = EXCLUDED.persona,
+    humor_intensity = EXCLUDED.humor_intensity,
+    timezone = EXCLUDED.timezone,
+    language = EXCLUDED.language,
+    work_start_minute = EXCLUDED.work_start_minute,
+    work_end_minute = EXCLUDED.work_end_minute,
+    quiet_start_minute = EXCLUDED.quiet_start_minute,
+    quiet_end_minute = EXCLUDED.quiet_end_minute,
+    nudge_channels = EXCLUDED.nudge_channels,
     updated_at = NOW()
 RETURNING
     id,
@@ -65,7 +169,16 @@ RETURNING
     notifications_enabled,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels
 `
 
 type UpsertUserPreferencesParams struct {
@@ -73,6 +186,15 @@ type UpsertUserPreferencesParams struct {
 	Theme                sql.NullString
 	NotificationsEnabled bool
 	Metadata             pqtype.NullRawMessage
+	Persona              string
+	HumorIntensity       int32
+	Timezone             string
+	Language             string
+	WorkStartMinute      sql.NullInt32
+	WorkEndMinute        sql.NullInt32
+	QuietStartMinute     sql.NullInt32
+	QuietEndMinute       sql.NullInt32
+	NudgeChannels        []string
 }
 
 func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error) {
@@ -81,6 +203,15 @@ func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPrefe
 		arg.Theme,
 		arg.NotificationsEnabled,
 		arg.Metadata,
+		arg.Persona,
+		arg.HumorIntensity,
+		arg.Timezone,
+		arg.Language,
+		arg.WorkStartMinute,
+		arg.WorkEndMinute,
+		arg.QuietStartMinute,
+		arg.QuietEndMinute,
+		pq.Array(arg.NudgeChannels),
 	)
 	var i UserPreference
 	err := row.Scan(
@@ -91,6 +222,15 @@ func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPrefe
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Persona,
+		&i.HumorIntensity,
+		&i.Timezone,
+		&i.Language,
+		&i.WorkStartMinute,
+		&i.WorkEndMinute,
+		&i.QuietStartMinute,
+		&i.QuietEndMinute,
+		pq.Array(&i.NudgeChannels),
 	)
 	return i, err
 }
@@ -0,0 +1,15 @@
+-- name: CreateClusterModel :one
+INSERT INTO cluster_models (
+    centroids,
+    sample_size
+) VALUES (
+    $1,
+    $2
+)
+RETURNING version, centroids, sample_size, created_at;
+
+-- name: GetLatestClusterModel :one
+SELECT version, centroids, sample_size, created_at
+FROM cluster_models
+ORDER BY version DESC
+LIMIT 1;
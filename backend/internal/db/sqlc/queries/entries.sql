@@ -24,6 +24,7 @@ SELECT
     entry_text,
     guilt_level,
     roast_text,
+    status,
     created_at,
     updated_at
 FROM guilt_entries
@@ -47,4 +48,34 @@ SELECT
     created_at,
     updated_at
 FROM guilt_entries
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: ClaimEntryForProcessing :execrows
+UPDATE guilt_entries
+SET status = 'processing', ml_job_key = $2, ml_attempts = ml_attempts + 1
+WHERE id = $1
+  AND (status IN ('pending', 'failed') OR (status = 'processing' AND ml_job_key = $2));
+
+-- name: FinishEntryProcessing :execrows
+UPDATE guilt_entries
+SET status = $3
+WHERE id = $1 AND status = 'processing' AND ml_job_key = $2;
+
+-- name: ListEntriesBefore :many
+SELECT
+    e.id,
+    e.session_id,
+    e.entry_text,
+    e.guilt_level,
+    e.roast_text,
+    e.status,
+    e.created_at,
+    e.updated_at
+FROM guilt_entries e
+JOIN guilt_sessions s ON s.id = e.session_id
+JOIN guilt_entries cur ON cur.id = $1
+JOIN guilt_sessions cur_s ON cur_s.id = cur.session_id
+WHERE e.created_at < cur.created_at
+  AND (e.session_id = cur.session_id OR (sqlc.arg(across_sessions)::bool AND s.user_id = cur_s.user_id))
+ORDER BY e.created_at DESC
+LIMIT $3;
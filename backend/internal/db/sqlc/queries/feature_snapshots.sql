@@ -0,0 +1,106 @@
+-- name: CreateFeatureSnapshot :one
+INSERT INTO feature_snapshots (
+    user_id,
+    procrastination_score,
+    snooze_rate,
+    completion_latency_avg,
+    burstiness_index,
+    mode_cluster,
+    cluster_version,
+    window_start,
+    window_end
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+RETURNING
+    id,
+    user_id,
+    procrastination_score,
+    snooze_rate,
+    completion_latency_avg,
+    burstiness_index,
+    mode_cluster,
+    cluster_version,
+    window_start,
+    window_end,
+    created_at;
+
+-- name: GetLatestFeatureSnapshot :one
+SELECT
+    id,
+    user_id,
+    procrastination_score,
+    snooze_rate,
+    completion_latency_avg,
+    burstiness_index,
+    mode_cluster,
+    cluster_version,
+    window_start,
+    window_end,
+    created_at
+FROM feature_snapshots
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: ListFeatureSnapshotsByUser :many
+SELECT
+    id,
+    user_id,
+    procrastination_score,
+    snooze_rate,
+    completion_latency_avg,
+    burstiness_index,
+    mode_cluster,
+    cluster_version,
+    window_start,
+    window_end,
+    created_at
+FROM feature_snapshots
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListActiveUserIDs :many
+SELECT user_id FROM (
+    SELECT user_id FROM guilt_sessions WHERE start_time >= sqlc.arg(since)::timestamptz
+    UNION
+    SELECT s.user_id
+    FROM guilt_entries e
+    JOIN guilt_sessions s ON s.id = e.session_id
+    WHERE e.created_at >= sqlc.arg(since)::timestamptz
+    UNION
+    SELECT user_id FROM task_events WHERE created_at >= sqlc.arg(since)::timestamptz
+) active
+WHERE user_id > sqlc.arg(after)::uuid
+ORDER BY user_id
+LIMIT $1;
+
+-- name: ListSessionStartsByUser :many
+SELECT start_time
+FROM guilt_sessions
+WHERE user_id = $1
+  AND start_time >= sqlc.arg(since)::timestamptz
+  AND start_time < sqlc.arg(until)::timestamptz
+ORDER BY start_time;
+
+-- name: ListEntrySignalsByUser :many
+SELECT
+    e.created_at,
+    e.guilt_level,
+    sc.aggregate_score
+FROM guilt_entries e
+JOIN guilt_sessions s ON s.id = e.session_id
+LEFT JOIN guilt_scores sc ON sc.entry_id = e.id
+WHERE s.user_id = $1
+  AND e.created_at >= sqlc.arg(since)::timestamptz
+  AND e.created_at < sqlc.arg(until)::timestamptz
+ORDER BY e.created_at;
@@ -0,0 +1,62 @@
+-- name: InsertOutbox :one
+INSERT INTO outbox (
+    topic,
+    payload,
+    entry_id
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING
+    id,
+    topic,
+    payload,
+    attempts,
+    last_error,
+    created_at,
+    sent_at,
+    dead_at,
+    claimed_until,
+    entry_id;
+
+-- name: ClaimOutbox :many
+UPDATE outbox SET claimed_until = sqlc.arg(claimed_until)::timestamptz
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE sent_at IS NULL AND dead_at IS NULL
+      AND (claimed_until IS NULL OR claimed_until < sqlc.arg(now)::timestamptz)
+    ORDER BY id
+    LIMIT sqlc.arg(max_rows)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING
+    id,
+    topic,
+    payload,
+    attempts,
+    last_error,
+    created_at,
+    sent_at,
+    dead_at,
+    claimed_until,
+    entry_id;
+
+-- name: MarkOutboxSent :exec
+UPDATE outbox SET sent_at = NOW(), claimed_until = NULL WHERE id = $1;
+
+-- name: MarkOutboxFailed :exec
+UPDATE outbox SET attempts = attempts + 1, last_error = $2, claimed_until = NULL WHERE id = $1;
+
+-- name: MarkOutboxDead :exec
+UPDATE outbox SET attempts = attempts + 1, last_error = $2, dead_at = NOW(), claimed_until = NULL WHERE id = $1;
+
+-- name: ReleaseOutbox :exec
+UPDATE outbox SET claimed_until = NULL WHERE id = $1;
+
+-- name: FailOutboxEntry :exec
+UPDATE guilt_entries SET status = 'failed'
+WHERE id = (SELECT entry_id FROM outbox WHERE outbox.id = $1) AND status = 'pending';
+
+-- name: DeleteSentOutbox :execrows
+DELETE FROM outbox WHERE sent_at IS NOT NULL AND sent_at < $1;
@@ -3,17 +3,44 @@ INSERT INTO user_preferences (
     user_id,
     theme,
     notifications_enabled,
-    metadata
+    metadata,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels
 ) VALUES (
     $1,
     $2,
     $3,
-    $4
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
 )
 ON CONFLICT (user_id) DO UPDATE SET
     theme = EXCLUDED.theme,
     notifications_enabled = EXCLUDED.notifications_enabled,
     metadata = EXCLUDED.metadata,
+    persona = EXCLUDED.persona,
+    humor_intensity = EXCLUDED.humor_intensity,
+    timezone = EXCLUDED.timezone,
+    language = EXCLUDED.language,
+    work_start_minute = EXCLUDED.work_start_minute,
+    work_end_minute = EXCLUDED.work_end_minute,
+    quiet_start_minute = EXCLUDED.quiet_start_minute,
+    quiet_end_minute = EXCLUDED.quiet_end_minute,
+    nudge_channels = EXCLUDED.nudge_channels,
     updated_at = NOW()
 RETURNING
     id,
@@ -22,7 +49,16 @@ RETURNING
     notifications_enabled,
     metadata,
     created_at,
-    updated_at;
+    updated_at,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels;
 
 -- name: GetPreferencesByUserID :one
 SELECT
@@ -32,6 +68,42 @@ SELECT
     notifications_enabled,
     metadata,
     created_at,
-    updated_at
+    updated_at,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels
+FROM user_preferences
+WHERE user_id = $1;
+
+-- name: EnsureUserPreferences :exec
+INSERT INTO user_preferences (user_id)
+VALUES ($1)
+ON CONFLICT (user_id) DO NOTHING;
+
+-- name: GetPreferencesByUserIDForUpdate :one
+SELECT
+    id,
+    user_id,
+    theme,
+    notifications_enabled,
+    metadata,
+    created_at,
+    updated_at,
+    persona,
+    humor_intensity,
+    timezone,
+    language,
+    work_start_minute,
+    work_end_minute,
+    quiet_start_minute,
+    quiet_end_minute,
+    nudge_channels
 FROM user_preferences
-WHERE user_id = $1;
\ No newline at end of file
+WHERE user_id = $1
+FOR UPDATE;
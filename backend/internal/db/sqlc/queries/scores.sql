@@ -41,4 +41,29 @@ SELECT
     created_at,
     updated_at
 FROM guilt_scores
-WHERE entry_id = $1;
\ No newline at end of file
+WHERE entry_id = $1;
+
+-- name: UpsertEntryScore :one
+INSERT INTO guilt_scores (
+    session_id,
+    entry_id,
+    aggregate_score,
+    meta
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (entry_id) DO UPDATE SET
+    aggregate_score = EXCLUDED.aggregate_score,
+    meta = EXCLUDED.meta,
+    updated_at = NOW()
+RETURNING
+    id,
+    session_id,
+    entry_id,
+    aggregate_score,
+    meta,
+    created_at,
+    updated_at;
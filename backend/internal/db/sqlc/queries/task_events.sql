@@ -0,0 +1,35 @@
+-- name: InsertTaskEvent :one
+INSERT INTO task_events (
+    task_id,
+    user_id,
+    event_type,
+    event_payload
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING
+    id,
+    task_id,
+    user_id,
+    event_type,
+    event_payload,
+    created_at;
+
+-- name: ListTaskEventsByUser :many
+SELECT
+    id,
+    task_id,
+    user_id,
+    event_type,
+    event_payload,
+    created_at
+FROM task_events
+WHERE user_id = $1
+  AND (cardinality(sqlc.arg(event_types)::text[]) = 0 OR event_type = ANY(sqlc.arg(event_types)::text[]))
+  AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR created_at < sqlc.narg(until)::timestamptz)
+ORDER BY created_at ASC, id ASC
+LIMIT $2 OFFSET $3;
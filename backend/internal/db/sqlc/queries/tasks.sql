@@ -0,0 +1,141 @@
+-- name: CreateTask :one
+INSERT INTO tasks (
+    user_id,
+    title,
+    description,
+    due_at,
+    category,
+    priority,
+    estimated_minutes
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING
+    id,
+    user_id,
+    title,
+    description,
+    status,
+    due_at,
+    category,
+    priority,
+    estimated_minutes,
+    created_at,
+    updated_at,
+    deleted_at,
+    snooze_count;
+
+-- name: GetTask :one
+SELECT
+    id,
+    user_id,
+    title,
+    description,
+    status,
+    due_at,
+    category,
+    priority,
+    estimated_minutes,
+    created_at,
+    updated_at,
+    deleted_at,
+    snooze_count
+FROM tasks
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetTaskForUpdate :one
+SELECT
+    id,
+    user_id,
+    title,
+    description,
+    status,
+    due_at,
+    category,
+    priority,
+    estimated_minutes,
+    created_at,
+    updated_at,
+    deleted_at,
+    snooze_count
+FROM tasks
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ListTasksByUser :many
+SELECT
+    id,
+    user_id,
+    title,
+    description,
+    status,
+    due_at,
+    category,
+    priority,
+    estimated_minutes,
+    created_at,
+    updated_at,
+    deleted_at,
+    snooze_count
+FROM tasks
+WHERE user_id = $1
+  AND deleted_at IS NULL
+  AND (sqlc.arg(status)::text = '' OR status = sqlc.arg(status)::text)
+ORDER BY due_at ASC NULLS LAST, created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: UpdateTask :one
+UPDATE tasks
+SET
+    title = $2,
+    description = $3,
+    status = $4,
+    due_at = $5,
+    category = $6,
+    priority = $7,
+    estimated_minutes = $8,
+    snooze_count = $9,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING
+    id,
+    user_id,
+    title,
+    description,
+    status,
+    due_at,
+    category,
+    priority,
+    estimated_minutes,
+    created_at,
+    updated_at,
+    deleted_at,
+    snooze_count;
+
+-- name: SoftDeleteTask :one
+UPDATE tasks
+SET
+    status = 'deleted',
+    deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING
+    id,
+    user_id,
+    title,
+    description,
+    status,
+    due_at,
+    category,
+    priority,
+    estimated_minutes,
+    created_at,
+    updated_at,
+    deleted_at,
+    snooze_count;
@@ -147,3 +147,66 @@ func (q *Queries) GetScoreBySession(ctx context.Context, sessionID uuid.UUID) (G
 	)
 	return i, err
 }
+
+const upsertEntryScore = `-- name: UpsertEntryScore :one
+INSERT INTO guilt_scores (
+    session_id,
+    entry_id,
+    aggregate_score,
+    meta
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (entry_id) DO UPDATE SET
+    aggregate_score = EXCLUDED.aggregate_score,
+    meta = EXCLUDED.meta,
+    updated_at = NOW()
+RETURNING
+    id,
+    session_id,
+    entry_id,
+    aggregate_score,
+    meta,
+    created_at,
+    updated_at
+`
+
+type UpsertEntryScoreParams struct {
+	SessionID      uuid.UUID
+	EntryID        uuid.NullUUID
+	AggregateScore int32
+	Meta           pqtype.NullRawMessage
+}
+
+type UpsertEntryScoreRow struct {
+	ID             uuid.UUID
+	SessionID      uuid.UUID
+	EntryID        uuid.NullUUID
+	AggregateScore int32
+	Meta           pqtype.NullRawMessage
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (q *Queries) UpsertEntryScore(ctx context.Context, arg UpsertEntryScoreParams) (UpsertEntryScoreRow, error) {
+	row := q.db.QueryRowContext(ctx, upsertEntryScore,
+		arg.SessionID,
+		arg.EntryID,
+		arg.AggregateScore,
+		arg.Meta,
+	)
+	var i UpsertEntryScoreRow
+	err := row.Scan(
+		&i.ID,
+		&i.SessionID,
+		&i.EntryID,
+		&i.AggregateScore,
+		&i.Meta,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
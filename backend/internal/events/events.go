@@ -0,0 +1,40 @@
+// Package events fans out entry changes from the ML workers to API replicas
+// that stream them to watching clients.
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// EntryEvent is the state of an entry after a change
+type EntryEvent struct {
+	EntryID    string
+	SessionID  string
+	Status     string
+	RoastText  string `json:",omitempty"`
+	GuiltScore int32  `json:",omitempty"`
+	At         time.Time
+}
+
+// Publisher announces entry changes. Delivery is best effort: subscribers
+// that are not connected at publish time never see the event.
+type Publisher interface {
+	PublishEntry(ctx context.Context, ev EntryEvent) error
+}
+
+// Subscriber streams the events of every entry in a session. The channel is
+// closed once ctx is done or the subscription breaks.
+type Subscriber interface {
+	SubscribeSession(ctx context.Context, sessionID string) (<-chan EntryEvent, error)
+}
+
+type Bus interface {
+	Publisher
+	Subscriber
+}
+
+// SessionChannel is the pub/sub channel carrying a session's entry events
+func SessionChannel(sessionID string) string {
+	return "events:session:" + sessionID
+}
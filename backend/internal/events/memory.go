@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// memoryBuffer is how many events a slow subscriber may fall behind before
+// further events for it are dropped
+const memoryBuffer = 16
+
+// MemoryBus delivers events within one process. It backs the single-binary
+// dev mode and tests.
+type MemoryBus struct {
+	mu   sync.Mutex
+	subs map[string]map[chan EntryEvent]struct{}
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{subs: map[string]map[chan EntryEvent]struct{}{}}
+}
+
+func (b *MemoryBus) PublishEntry(ctx context.Context, ev EntryEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[ev.SessionID] {
+		select {
+		case ch <- ev:
+		default:
+			// like pub/sub, a subscriber that cannot keep up loses events
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBus) SubscribeSession(ctx context.Context, sessionID string) (<-chan EntryEvent, error) {
+	ch := make(chan EntryEvent, memoryBuffer)
+
+	b.mu.Lock()
+	if b.subs[sessionID] == nil {
+		b.subs[sessionID] = map[chan EntryEvent]struct{}{}
+	}
+	b.subs[sessionID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs[sessionID], ch)
+		if len(b.subs[sessionID]) == 0 {
+			delete(b.subs, sessionID)
+		}
+		b.mu.Unlock()
+		close(ch)
+	}()
+	return ch, nil
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBus carries events over Redis pub/sub, one channel per session
+type RedisBus struct {
+	rdb *redis.Client
+}
+
+func NewRedisBus(rdb *redis.Client) *RedisBus {
+	return &RedisBus{rdb: rdb}
+}
+
+func (b *RedisBus) PublishEntry(ctx context.Context, ev EntryEvent) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, SessionChannel(ev.SessionID), payload).Err()
+}
+
+func (b *RedisBus) SubscribeSession(ctx context.Context, sessionID string) (<-chan EntryEvent, error) {
+	sub := b.rdb.Subscribe(ctx, SessionChannel(sessionID))
+	// wait for the confirmation so no event published after we return is missed
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, err
+	}
+
+	out := make(chan EntryEvent)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				var ev EntryEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &ev); err != nil {
+					log.Printf("events: dropping undecodable message on %s: %v", msg.Channel, err)
+					continue
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,121 @@
+package ml
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// behavior modes, the labels of BehaviorFeatures.ModeCluster
+const (
+	ModeIdle = iota
+	ModeSteady
+	ModeBursty
+	ModeProcrastinating
+)
+
+// weights of the procrastination score components; components without data
+// are left out and the others reweighted
+const (
+	snoozeWeight  = 0.4
+	overdueWeight = 0.35
+	guiltWeight   = 0.25
+)
+
+// BehaviorSignals is what a user did during a feature window
+type BehaviorSignals struct {
+	// Activity holds when the user started sessions, wrote entries and
+	// changed tasks, in any order
+	Activity []time.Time
+	// GuiltScores are the scores of the user's entries, 0-1
+	GuiltScores []float64
+	Snoozes     int
+	// CompletionMinutes holds how long each completed task was open
+	CompletionMinutes []float64
+	// DatedCompletions counts completed tasks that had a due date,
+	// OverdueCompletions the ones of them finished late
+	DatedCompletions   int
+	OverdueCompletions int
+}
+
+// BehaviorFeatures are the features of a FeatureSnapshot
+type BehaviorFeatures struct {
+	// ProcrastinationScore is 0-1, a weighted mean of the snooze rate, the
+	// share of dated tasks finished late and the mean guilt score
+	ProcrastinationScore float64
+	// SnoozeRate is the share of snoozes among snoozes and completions
+	SnoozeRate float64
+	// CompletionLatencyAvg is the mean number of minutes tasks were open
+	CompletionLatencyAvg float64
+	// BurstinessIndex is -1 for perfectly regular activity, 0 for random and
+	// towards 1 for activity in bursts
+	BurstinessIndex float64
+	// ModeCluster is set by a ClusterModel; users without activity stay
+	// ModeIdle
+	ModeCluster int
+}
+
+// ComputeBehaviorFeatures derives the features of a snapshot from signals,
+// all but the mode
+func ComputeBehaviorFeatures(s BehaviorSignals) BehaviorFeatures {
+	f := BehaviorFeatures{
+		CompletionLatencyAvg: mean(s.CompletionMinutes),
+		BurstinessIndex:      Burstiness(s.Activity),
+	}
+
+	var weighted, weights float64
+	if resolved := s.Snoozes + len(s.CompletionMinutes); resolved > 0 {
+		f.SnoozeRate = float64(s.Snoozes) / float64(resolved)
+		weighted += snoozeWeight * f.SnoozeRate
+		weights += snoozeWeight
+	}
+	if s.DatedCompletions > 0 {
+		weighted += overdueWeight * float64(s.OverdueCompletions) / float64(s.DatedCompletions)
+		weights += overdueWeight
+	}
+	if len(s.GuiltScores) > 0 {
+		weighted += guiltWeight * mean(s.GuiltScores)
+		weights += guiltWeight
+	}
+	if weights > 0 {
+		f.ProcrastinationScore = weighted / weights
+	}
+
+	return f
+}
+
+// Burstiness is the burstiness index (σ-μ)/(σ+μ) of the gaps between
+// events. Fewer than three events have no pattern and get 0.
+func Burstiness(events []time.Time) float64 {
+	if len(events) < 3 {
+		return 0
+	}
+	sorted := slices.Clone(events)
+	slices.SortFunc(sorted, func(a, b time.Time) int { return a.Compare(b) })
+
+	gaps := make([]float64, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		gaps = append(gaps, sorted[i].Sub(sorted[i-1]).Seconds())
+	}
+	mu := mean(gaps)
+	var variance float64
+	for _, g := range gaps {
+		variance += (g - mu) * (g - mu)
+	}
+	sigma := math.Sqrt(variance / float64(len(gaps)))
+	if sigma+mu == 0 {
+		return 0
+	}
+	return (sigma - mu) / (sigma + mu)
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
@@ -0,0 +1,123 @@
+package ml
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of calling an LLM that keeps failing
+var ErrCircuitOpen = errors.New("llm circuit breaker is open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects calls until the cooldown has passed
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe call through; its result closes or
+	// reopens the breaker
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops calling the LLM after threshold failures in a row and
+// tries it again once cooldown has passed
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	failures int
+	openedAt time.Time
+	open     bool
+	// probing is set while the half-open probe call is in flight
+	probing bool
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return NewCircuitBreakerWithClock(threshold, cooldown, time.Now)
+}
+
+// NewCircuitBreakerWithClock uses now instead of time.Now, for tests
+func NewCircuitBreakerWithClock(threshold int, cooldown time.Duration, now func() time.Time) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: now}
+}
+
+// Allow returns ErrCircuitOpen while the breaker is open, and while half-open
+// once the probe is in flight. Every allowed call must end in Success, Failure
+// or Abandon.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state() {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state()
+}
+
+func (b *CircuitBreaker) state() BreakerState {
+	if !b.open {
+		return BreakerClosed
+	}
+	if b.now().Sub(b.openedAt) < b.cooldown {
+		return BreakerOpen
+	}
+	return BreakerHalfOpen
+}
+
+// Success closes the breaker
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.open = false
+	b.probing = false
+}
+
+// Failure counts a failed call; a failure while half-open reopens the breaker
+// right away
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state() == BreakerHalfOpen || b.failures >= b.threshold {
+		b.open = true
+		b.openedAt = b.now()
+	}
+	b.probing = false
+}
+
+// Abandon ends an allowed call that said nothing about the LLM, e.g. one the
+// caller canceled, so a half-open breaker can send another probe
+func (b *CircuitBreaker) Abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
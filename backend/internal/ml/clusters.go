@@ -0,0 +1,312 @@
+package ml
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+)
+
+//go:embed models/modes-v0.json
+var defaultClusterModel []byte
+
+// ClusterDims is the length of a feature vector: procrastination score,
+// snooze rate, scaled completion latency and scaled burstiness, each 0-1
+const ClusterDims = 4
+
+// latencyScaleMinutes is the completion latency, a week, that scales to 1;
+// latencies are log-scaled below it
+const latencyScaleMinutes = 7 * 24 * 60
+
+// ClusterIterations bounds a k-means run
+const ClusterIterations = 50
+
+// ClusterCount is the k of k-means, one cluster per mode but idle; idle users
+// have no features and are not clustered
+const ClusterCount = 3
+
+// ClusterModel assigns feature vectors to the behavior mode of the nearest
+// centroid. Trained models are stored as numbered versions; version 0 is
+// built into the binary and used until the first training.
+type ClusterModel struct {
+	Version   int32      `json:"version"`
+	Centroids []Centroid `json:"centroids"`
+}
+
+// Centroid is the center of the users of one mode
+type Centroid struct {
+	Mode   int
+	Center []float64
+}
+
+type centroidJSON struct {
+	Mode   string    `json:"mode"`
+	Center []float64 `json:"center"`
+}
+
+func (c Centroid) MarshalJSON() ([]byte, error) {
+	return json.Marshal(centroidJSON{Mode: ModeName(c.Mode), Center: c.Center})
+}
+
+func (c *Centroid) UnmarshalJSON(data []byte) error {
+	var raw centroidJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	mode, ok := ParseMode(raw.Mode)
+	if !ok {
+		return fmt.Errorf("unknown mode %q", raw.Mode)
+	}
+	*c = Centroid{Mode: mode, Center: raw.Center}
+	return nil
+}
+
+// ModeName is the label of a behavior mode, e.g. "bursty", for persona and
+// nudge rules to key off
+func ModeName(mode int) string {
+	switch mode {
+	case ModeSteady:
+		return "steady"
+	case ModeBursty:
+		return "bursty"
+	case ModeProcrastinating:
+		return "procrastinating"
+	default:
+		return "idle"
+	}
+}
+
+// ParseMode maps a mode name such as "bursty" to its mode
+func ParseMode(name string) (int, bool) {
+	for _, m := range []int{ModeIdle, ModeSteady, ModeBursty, ModeProcrastinating} {
+		if ModeName(m) == name {
+			return m, true
+		}
+	}
+	return ModeIdle, false
+}
+
+// LoadClusterModel reads a model file, see models/modes-v0.json for the format
+func LoadClusterModel(path string) (*ClusterModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cluster model: %w", err)
+	}
+	return ParseClusterModel(data)
+}
+
+// DefaultClusterModel returns the model built into the binary
+func DefaultClusterModel() *ClusterModel {
+	m, err := ParseClusterModel(defaultClusterModel)
+	if err != nil {
+		panic("ml: invalid built-in cluster model: " + err.Error())
+	}
+	return m
+}
+
+func ParseClusterModel(data []byte) (*ClusterModel, error) {
+	var m ClusterModel
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse cluster model: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ParseCentroids builds a model from stored centroids, the JSON of
+// ClusterModel.Centroids
+func ParseCentroids(version int32, data []byte) (*ClusterModel, error) {
+	m := ClusterModel{Version: version}
+	if err := json.Unmarshal(data, &m.Centroids); err != nil {
+		return nil, fmt.Errorf("parse cluster model %d: %w", version, err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *ClusterModel) validate() error {
+	if len(m.Centroids) == 0 {
+		return errors.New("cluster model: centroids are required")
+	}
+	for _, c := range m.Centroids {
+		if c.Mode == ModeIdle {
+			return errors.New("cluster model: idle is not a cluster")
+		}
+		if len(c.Center) != ClusterDims {
+			return fmt.Errorf("cluster model: %s center has %d dimensions, want %d", ModeName(c.Mode), len(c.Center), ClusterDims)
+		}
+	}
+	return nil
+}
+
+// Mode returns the mode of the centroid nearest to f
+func (m *ClusterModel) Mode(f BehaviorFeatures) int {
+	return m.modeOf(f.Vector())
+}
+
+func (m *ClusterModel) modeOf(v []float64) int {
+	best, bestDist := ModeIdle, math.Inf(1)
+	for _, c := range m.Centroids {
+		if d := sqDist(v, c.Center); d < bestDist {
+			best, bestDist = c.Mode, d
+		}
+	}
+	return best
+}
+
+// Vector scales f to the unit cube k-means runs in
+func (f BehaviorFeatures) Vector() []float64 {
+	return []float64{
+		clamp01(f.ProcrastinationScore),
+		clamp01(f.SnoozeRate),
+		clamp01(math.Log1p(max(f.CompletionLatencyAvg, 0)) / math.Log1p(latencyScaleMinutes)),
+		clamp01((f.BurstinessIndex + 1) / 2),
+	}
+}
+
+// TrainClusterModel clusters vectors into ClusterCount clusters, starting
+// from the centroids of prev. The clusters are labelled one-to-one with the
+// modes of the built-in model, which defines what the modes mean, so labels
+// keep their meaning between versions. The result has no version yet.
+func TrainClusterModel(prev *ClusterModel, vectors [][]float64) (*ClusterModel, error) {
+	if len(vectors) < ClusterCount {
+		return nil, fmt.Errorf("cluster model: need at least %d vectors, got %d", ClusterCount, len(vectors))
+	}
+	init := make([][]float64, 0, ClusterCount)
+	for _, c := range prev.Centroids {
+		init = append(init, slices.Clone(c.Center))
+	}
+	if len(init) != ClusterCount {
+		init = spreadInit(vectors, ClusterCount)
+	}
+
+	return &ClusterModel{Centroids: labelClusters(DefaultClusterModel(), KMeans(vectors, init, ClusterIterations))}, nil
+}
+
+// labelClusters gives every center the mode of a reference centroid, each
+// mode once: greedily the closest remaining pair of reference centroid and
+// center first
+func labelClusters(reference *ClusterModel, centers [][]float64) []Centroid {
+	labelled := make([]Centroid, len(centers))
+	usedRef := make([]bool, len(reference.Centroids))
+	usedCenter := make([]bool, len(centers))
+	for range min(len(centers), len(reference.Centroids)) {
+		bestRef, bestCenter, bestDist := -1, -1, math.Inf(1)
+		for r, ref := range reference.Centroids {
+			if usedRef[r] {
+				continue
+			}
+			for c, center := range centers {
+				if d := sqDist(ref.Center, center); !usedCenter[c] && d < bestDist {
+					bestRef, bestCenter, bestDist = r, c, d
+				}
+			}
+		}
+		usedRef[bestRef], usedCenter[bestCenter] = true, true
+		labelled[bestCenter] = Centroid{Mode: reference.Centroids[bestRef].Mode, Center: centers[bestCenter]}
+	}
+	return labelled
+}
+
+// KMeans runs Lloyd's algorithm from the centers init, which it does not
+// modify, for at most iterations rounds. A center left without points is
+// moved to the point farthest from its own center.
+func KMeans(points, init [][]float64, iterations int) [][]float64 {
+	centers := make([][]float64, len(init))
+	for i, c := range init {
+		centers[i] = slices.Clone(c)
+	}
+	assign := make([]int, len(points))
+	for iter := 0; iter < iterations; iter++ {
+		changed := iter == 0
+		for i, p := range points {
+			if c := nearest(centers, p); c != assign[i] {
+				assign[i] = c
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][]float64, len(centers))
+		counts := make([]int, len(centers))
+		for i := range sums {
+			sums[i] = make([]float64, len(points[0]))
+		}
+		for i, p := range points {
+			counts[assign[i]]++
+			for d, x := range p {
+				sums[assign[i]][d] += x
+			}
+		}
+		for c := range centers {
+			if counts[c] == 0 {
+				far := farthest(points, centers, assign)
+				centers[c] = slices.Clone(points[far])
+				assign[far] = c
+				continue
+			}
+			for d := range sums[c] {
+				centers[c][d] = sums[c][d] / float64(counts[c])
+			}
+		}
+	}
+	return centers
+}
+
+// spreadInit seeds k centers deterministically, k-means++ style without the
+// randomness: the first point, then repeatedly the point farthest from the
+// centers so far
+func spreadInit(points [][]float64, k int) [][]float64 {
+	centers := [][]float64{slices.Clone(points[0])}
+	for len(centers) < k {
+		far, farDist := 0, -1.0
+		for i, p := range points {
+			if d := sqDist(p, centers[nearest(centers, p)]); d > farDist {
+				far, farDist = i, d
+			}
+		}
+		centers = append(centers, slices.Clone(points[far]))
+	}
+	return centers
+}
+
+func nearest(centers [][]float64, p []float64) int {
+	best, bestDist := 0, math.Inf(1)
+	for i, c := range centers {
+		if d := sqDist(p, c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+func farthest(points, centers [][]float64, assign []int) int {
+	far, farDist := 0, -1.0
+	for i, p := range points {
+		if d := sqDist(p, centers[assign[i]]); d > farDist {
+			far, farDist = i, d
+		}
+	}
+	return far
+}
+
+func sqDist(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return sum
+}
+
+func clamp01(x float64) float64 {
+	return math.Min(1, math.Max(0, x))
+}
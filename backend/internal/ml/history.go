@@ -0,0 +1,41 @@
+package ml
+
+import "fmt"
+
+// HistoryItem is an earlier entry of the user and the roast it got
+type HistoryItem struct {
+	Text  string
+	Roast string
+}
+
+// FormatHistory turns earlier entries, newest first, into HybridInput.History
+// lines, oldest first. It keeps as many of the newest entries as fit into
+// maxChars (about four characters per LLM token); the newest entry is
+// shortened rather than dropped. maxChars <= 0 means no limit.
+func FormatHistory(items []HistoryItem, maxChars int) []string {
+	var lines []string
+	used := 0
+	for _, item := range items {
+		line := historyLine(item)
+		if maxChars > 0 && used+len(line) > maxChars {
+			if used > 0 || maxChars <= len("…") {
+				break
+			}
+			line = snippet(line, maxChars-len("…"))
+		}
+		used += len(line)
+		lines = append(lines, line)
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+func historyLine(item HistoryItem) string {
+	if item.Roast == "" {
+		return fmt.Sprintf("%q", item.Text)
+	}
+	return fmt.Sprintf("%q (you answered: %q)", item.Text, item.Roast)
+}
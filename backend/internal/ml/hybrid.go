@@ -2,43 +2,198 @@ package ml
 
 import (
 	"context"
+	"log"
 	"strings"
+	"unicode"
 )
 
 type HybridOrchestrator struct {
-	llm LLM
+	llm    LLM
+	scorer *ScoringModel
+	// templates and breaker are optional; without templates LLM errors are
+	// returned to the caller
+	templates *TemplateSet
+	breaker   *CircuitBreaker
 }
 
 func NewHybridOrchestrator(llm LLM) *HybridOrchestrator {
-	return &HybridOrchestrator{llm: llm}
+	return &HybridOrchestrator{llm: llm, scorer: DefaultScoringModel()}
+}
+
+// NewHybridOrchestratorWithFallback answers with a template roast when the LLM
+// fails or breaker has tripped. breaker may be nil.
+func NewHybridOrchestratorWithFallback(llm LLM, templates *TemplateSet, breaker *CircuitBreaker) *HybridOrchestrator {
+	return &HybridOrchestrator{llm: llm, scorer: DefaultScoringModel(), templates: templates, breaker: breaker}
+}
+
+// SetScoringModel replaces the built-in scoring model
+func (h *HybridOrchestrator) SetScoringModel(m *ScoringModel) {
+	h.scorer = m
 }
 
 func (h *HybridOrchestrator) Run(ctx context.Context, in HybridInput) (*HybridOutput, error) {
-	raw, err := h.llm.Generate(ctx, in)
+	raw, err := h.call(ctx, func() (string, error) {
+		return h.llm.Generate(ctx, in)
+	}, nil)
 	if err != nil {
+		if !h.canFallBack(ctx) {
+			return nil, err
+		}
+		log.Printf("llm unavailable, using a template roast: %v", err)
+		return h.templateOutput(in)
+	}
+
+	return h.output(in, raw), nil
+}
+
+// RunStream is Run with the roast handed to emit as it is generated. The
+// emitted pieces add up to the returned RoastText: the persona prefix goes
+// first and text is safety filtered a word at a time. LLMs that cannot stream
+// emit their whole answer at once. A template roast replaces the LLM only if
+// the LLM fails before any of its text was emitted.
+func (h *HybridOrchestrator) RunStream(ctx context.Context, in HybridInput, emit func(string) error) (*HybridOutput, error) {
+	if prefix := adjustPersona("", in.Persona, in.Intensity); prefix != "" {
+		if err := emit(prefix); err != nil {
+			return nil, err
+		}
+	}
+
+	var emitted bool
+	var emitErr error
+	w := &filteredWriter{emit: func(s string) error {
+		emitted = true
+		emitErr = emit(s)
+		return emitErr
+	}}
+	raw, err := h.call(ctx, func() (string, error) {
+		if streaming, ok := h.llm.(StreamingLLM); ok {
+			return streaming.GenerateStream(ctx, in, w.write)
+		}
+		raw, err := h.llm.Generate(ctx, in)
+		if err == nil {
+			err = w.write(raw)
+		}
+		return raw, err
+	}, func() bool { return emitErr != nil })
+	if err != nil {
+		if emitErr != nil || emitted || !h.canFallBack(ctx) {
+			return nil, err
+		}
+		log.Printf("llm unavailable, streaming a template roast: %v", err)
+		out, err := h.templateOutput(in)
+		if err != nil {
+			return nil, err
+		}
+		// the prefix is out already; the template goes in one piece
+		if text := strings.TrimPrefix(out.RoastText, adjustPersona("", in.Persona, in.Intensity)); text != "" {
+			if err := emit(text); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	if err := w.flush(); err != nil {
 		return nil, err
 	}
 
+	return h.output(in, strings.TrimSpace(raw)), nil
+}
+
+// call runs an LLM request through the circuit breaker. Requests abandoned by
+// the caller, by canceling or, as reported by callerFailed, by failing to take
+// the answer, say nothing about the LLM and are not counted.
+func (h *HybridOrchestrator) call(ctx context.Context, generate func() (string, error), callerFailed func() bool) (string, error) {
+	if h.breaker == nil {
+		return generate()
+	}
+	if err := h.breaker.Allow(); err != nil {
+		return "", err
+	}
+	raw, err := generate()
+	switch {
+	case err == nil:
+		h.breaker.Success()
+	case ctx.Err() != nil || (callerFailed != nil && callerFailed()):
+		h.breaker.Abandon()
+	default:
+		h.breaker.Failure()
+	}
+	return raw, err
+}
+
+func (h *HybridOrchestrator) canFallBack(ctx context.Context) bool {
+	return h.templates != nil && ctx.Err() == nil
+}
+
+func (h *HybridOrchestrator) templateOutput(in HybridInput) (*HybridOutput, error) {
+	raw, err := h.templates.Render(in)
+	if err != nil {
+		return nil, err
+	}
+	out := h.output(in, raw)
+	out.Source = SourceTemplate
+	out.Tags = append(out.Tags, "source="+SourceTemplate)
+	return out, nil
+}
+
+func (h *HybridOrchestrator) output(in HybridInput, raw string) *HybridOutput {
 	roast := adjustPersona(raw, in.Persona, in.Intensity)
 	safeRoast, safetyFlags := safetyFilter(roast)
 
-	score := guiltScore(in.Text)
+	score := h.scorer.Score(ScoreInput{Text: in.Text, GuiltLevel: in.GuiltLevel, At: in.At})
 
 	return &HybridOutput{
-		GuiltScore:  score,
-		RoastText:   safeRoast,
-		Tags:        []string{"hybrid"},
-		SafetyFlags: safetyFlags,
-	}, nil
+		GuiltScore:   score,
+		RoastText:    safeRoast,
+		Tags:         []string{"hybrid"},
+		SafetyFlags:  safetyFlags,
+		Source:       SourceLLM,
+		ModelVersion: h.scorer.Version,
+	}
+}
+
+// filteredWriter holds back streamed text until a word is complete, so the
+// safety filter never sees half a word. Surrounding whitespace is trimmed the
+// same way the final text is.
+type filteredWriter struct {
+	emit    func(string) error
+	pending string
+	started bool
+}
+
+func (w *filteredWriter) write(delta string) error {
+	w.pending += delta
+	if !w.started {
+		w.pending = strings.TrimLeftFunc(w.pending, unicode.IsSpace)
+		if w.pending == "" {
+			return nil
+		}
+		w.started = true
+	}
+
+	// keep the trailing whitespace and the word after it for the next call
+	end := strings.LastIndexFunc(w.pending, unicode.IsSpace)
+	if end < 0 {
+		return nil
+	}
+	end = len(strings.TrimRightFunc(w.pending[:end], unicode.IsSpace))
+	if end == 0 {
+		return nil
+	}
+
+	out, _ := safetyFilter(w.pending[:end])
+	w.pending = w.pending[end:]
+	return w.emit(out)
 }
 
-func guiltScore(text string) float64 {
-	w := float64(len(strings.Fields(text)))
-	s := w / 12.0
-	if s > 1 {
-		s = 1
+func (w *filteredWriter) flush() error {
+	rest := strings.TrimRightFunc(w.pending, unicode.IsSpace)
+	w.pending = ""
+	if rest == "" {
+		return nil
 	}
-	return s
+	out, _ := safetyFilter(rest)
+	return w.emit(out)
 }
 
 func adjustPersona(raw string, persona Persona, intensity int) string {
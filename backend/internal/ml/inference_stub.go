@@ -7,19 +7,27 @@ import (
 	gen "guiltmachine/internal/proto/gen/ml"
 )
 
-type InferenceStub struct{}
+// InferenceStub roasts from canned lines and scores with the same model as
+// HybridOrchestrator
+type InferenceStub struct {
+	scorer *ScoringModel
+}
 
 func NewInferenceStub() *InferenceStub {
-	return &InferenceStub{}
+	return NewInferenceStubWithModel(DefaultScoringModel())
 }
 
-func (s *InferenceStub) Roast(ctx context.Context, req *gen.RoastRequest) (*gen.RoastResponse, error) {
-	text := req.EntryText
+func NewInferenceStubWithModel(m *ScoringModel) *InferenceStub {
+	return &InferenceStub{scorer: m}
+}
 
-	score := float64(len(strings.Fields(text))) / 10.0
-	if score > 1 {
-		score = 1
+func (s *InferenceStub) Roast(ctx context.Context, req *gen.RoastRequest) (*gen.RoastResponse, error) {
+	in := ScoreInput{Text: req.EntryText, GuiltLevel: int(req.GuiltLevel)}
+	// the request carries no time zone, so late night is judged in UTC
+	if req.CreatedAt != nil {
+		in.At = req.CreatedAt.AsTime()
 	}
+	score := s.scorer.Score(in)
 
 	roast := "Mild roast: you really wrote that?"
 	if req.HumorIntensity > 5 {
@@ -27,10 +35,11 @@ func (s *InferenceStub) Roast(ctx context.Context, req *gen.RoastRequest) (*gen.
 	}
 
 	return &gen.RoastResponse{
-		GuiltScore:  score,
-		RoastText:   roast,
-		Tags:        []string{"stub"},
-		SafetyFlags: []string{},
+		GuiltScore:   score,
+		RoastText:    roast,
+		Tags:         []string{"stub"},
+		SafetyFlags:  []string{},
+		ModelVersion: s.scorer.Version,
 	}, nil
 }
 
@@ -42,3 +51,17 @@ func (s *InferenceStub) Generate(ctx context.Context, in HybridInput) (string, e
 	}
 	return roast, nil
 }
+
+// GenerateStream implements StreamingLLM, handing out the roast word by word
+func (s *InferenceStub) GenerateStream(ctx context.Context, in HybridInput, onDelta func(string) error) (string, error) {
+	roast, _ := s.Generate(ctx, in)
+	for i, word := range strings.Fields(roast) {
+		if i > 0 {
+			word = " " + word
+		}
+		if err := onDelta(word); err != nil {
+			return "", err
+		}
+	}
+	return roast, nil
+}
@@ -5,3 +5,11 @@ import "context"
 type LLM interface {
 	Generate(context.Context, HybridInput) (string, error)
 }
+
+// StreamingLLM is an LLM that can hand out its text while generating it.
+// GenerateStream calls onDelta with each new piece, in order, and returns the
+// full text; an error from onDelta aborts generation.
+type StreamingLLM interface {
+	LLM
+	GenerateStream(ctx context.Context, in HybridInput, onDelta func(string) error) (string, error)
+}
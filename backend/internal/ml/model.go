@@ -1,5 +1,7 @@
 package ml
 
+import "time"
+
 type Persona int
 
 const (
@@ -9,17 +11,60 @@ const (
 	PersonaChill
 )
 
+// String returns the persona name used in preferences and template files
+func (p Persona) String() string {
+	return personaName(p)
+}
+
+func personaName(p Persona) string {
+	switch p {
+	case PersonaRoast:
+		return "roast"
+	case PersonaCoach:
+		return "coach"
+	case PersonaChill:
+		return "chill"
+	default:
+		return "neutral"
+	}
+}
+
+// ParsePersona maps a persona name such as "coach" to its Persona
+func ParsePersona(name string) (Persona, bool) {
+	for _, p := range []Persona{PersonaNeutral, PersonaRoast, PersonaCoach, PersonaChill} {
+		if personaName(p) == name {
+			return p, true
+		}
+	}
+	return PersonaNeutral, false
+}
+
 type HybridInput struct {
 	Text      string
 	UserID    string
 	Intensity int
 	Persona   Persona
 	History   []string
+	// GuiltLevel is the user's own 0-10 rating, 0 when not given
+	GuiltLevel int
+	// At is when the entry was written, in the user's time zone so late-night
+	// scoring uses their local hour
+	At time.Time
 }
 
+// where a roast came from
+const (
+	SourceLLM      = "llm"
+	SourceTemplate = "template"
+)
+
 type HybridOutput struct {
 	GuiltScore  float64
 	RoastText   string
 	Tags        []string
 	SafetyFlags []string
+	// Source is SourceLLM, or SourceTemplate when the LLM was unavailable
+	Source string
+	// ModelVersion is the version of the scoring model behind GuiltScore
+	ModelVersion string
 }
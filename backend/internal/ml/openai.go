@@ -0,0 +1,309 @@
+package ml
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrEmptyCompletion is returned when the server answers without any text
+	ErrEmptyCompletion = errors.New("llm returned no completion")
+	// ErrStreamTruncated is returned when a stream ends without data: [DONE]
+	ErrStreamTruncated = errors.New("llm stream ended before [DONE]")
+	// ErrStreamStalled is returned when a stream sends nothing for Timeout
+	ErrStreamStalled = errors.New("llm stream stalled")
+)
+
+// OpenAIConfig points the client at any server speaking the OpenAI chat
+// completions API: OpenAI itself, vLLM, llama.cpp, Ollama, LM Studio, ...
+type OpenAIConfig struct {
+	// BaseURL is the API root without /chat/completions, e.g. https://api.openai.com/v1
+	BaseURL string
+	// APIKey is sent as a bearer token; local servers usually need none
+	APIKey string
+	Model  string
+	// Timeout bounds a whole completion, but for streams only the wait for
+	// each line, so long roasts can keep streaming
+	Timeout     time.Duration
+	MaxTokens   int
+	Temperature float64
+}
+
+func DefaultOpenAIConfig() OpenAIConfig {
+	return OpenAIConfig{
+		BaseURL:     "https://api.openai.com/v1",
+		Model:       "gpt-4o-mini",
+		Timeout:     30 * time.Second,
+		MaxTokens:   200,
+		Temperature: 0.9,
+	}
+}
+
+// APIError is a non-2xx answer from the completions endpoint
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("llm request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// OpenAIClient implements LLM against an OpenAI-compatible HTTP API
+type OpenAIClient struct {
+	cfg  OpenAIConfig
+	http *http.Client
+}
+
+func NewOpenAIClient(cfg OpenAIConfig) *OpenAIClient {
+	return NewOpenAIClientWithHTTP(cfg, &http.Client{})
+}
+
+// NewOpenAIClientWithHTTP uses the given http.Client, e.g. one with a custom
+// transport. A Timeout on hc also caps streams, so leave it unset.
+func NewOpenAIClientWithHTTP(cfg OpenAIConfig, hc *http.Client) *OpenAIClient {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &OpenAIClient{cfg: cfg, http: hc}
+}
+
+// ChatMessage is one message of a chat completions request
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message ChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type chatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+type apiErrorBody struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Generate implements the LLM interface for HybridOrchestrator
+func (c *OpenAIClient) Generate(ctx context.Context, in HybridInput) (string, error) {
+	if c.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+	}
+
+	resp, err := c.post(ctx, chatRequest{
+		Model:       c.cfg.Model,
+		Messages:    BuildMessages(in),
+		MaxTokens:   c.cfg.MaxTokens,
+		Temperature: c.cfg.Temperature,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode llm response: %w", err)
+	}
+	if len(out.Choices) == 0 {
+		return "", ErrEmptyCompletion
+	}
+	text := strings.TrimSpace(out.Choices[0].Message.Content)
+	if text == "" {
+		return "", ErrEmptyCompletion
+	}
+	return text, nil
+}
+
+// GenerateStream implements StreamingLLM using server-sent events. The stream
+// fails once the server sends nothing for Timeout, however long it ran before.
+func (c *OpenAIClient) GenerateStream(ctx context.Context, in HybridInput, onDelta func(string) error) (string, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	// idle is reset on every line; nil when there is no timeout
+	var idle *time.Timer
+	if c.cfg.Timeout > 0 {
+		idle = time.AfterFunc(c.cfg.Timeout, func() { cancel(ErrStreamStalled) })
+		defer idle.Stop()
+	}
+
+	resp, err := c.post(ctx, chatRequest{
+		Model:       c.cfg.Model,
+		Messages:    BuildMessages(in),
+		MaxTokens:   c.cfg.MaxTokens,
+		Temperature: c.cfg.Temperature,
+		Stream:      true,
+	})
+	if err != nil {
+		return "", streamErr(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	done := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if idle != nil {
+			idle.Reset(c.cfg.Timeout)
+		}
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			// blank separators, comments and other SSE fields
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			done = true
+			break
+		}
+
+		var chunk chatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("decode llm stream: %w", err)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		delta := chunk.Choices[0].Delta.Content
+		text.WriteString(delta)
+		if err := onDelta(delta); err != nil {
+			return "", err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read llm stream: %w", streamErr(ctx, err))
+	}
+	if !done {
+		return "", ErrStreamTruncated
+	}
+
+	if strings.TrimSpace(text.String()) == "" {
+		return "", ErrEmptyCompletion
+	}
+	return text.String(), nil
+}
+
+// streamErr reports a stalled stream as ErrStreamStalled rather than as the
+// cancellation it caused
+func streamErr(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrStreamStalled) {
+		return cause
+	}
+	return err
+}
+
+// post sends a completions request and returns the response once it has a 2xx status
+func (c *OpenAIClient) post(ctx context.Context, req chatRequest) (*http.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("llm request: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		msg := strings.TrimSpace(string(raw))
+		var apiErr apiErrorBody
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			msg = apiErr.Error.Message
+		}
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: msg}
+	}
+	return resp, nil
+}
+
+// BuildMessages turns a HybridInput into the system prompt, the user's
+// earlier entries as context and the entry to roast
+func BuildMessages(in HybridInput) []ChatMessage {
+	system := fmt.Sprintf(
+		"You are the Guilt Machine. %s Intensity: %d out of 10; %s Reply with the response only, at most three sentences. Never insult protected traits, never encourage self-harm.",
+		personaPrompt(in.Persona), clampIntensity(in.Intensity), intensityPrompt(in.Intensity),
+	)
+	msgs := []ChatMessage{{Role: "system", Content: system}}
+
+	if len(in.History) > 0 {
+		var b strings.Builder
+		b.WriteString("For context, my earlier confessions were:")
+		for _, h := range in.History {
+			b.WriteString("\n- ")
+			b.WriteString(h)
+		}
+		msgs = append(msgs, ChatMessage{Role: "user", Content: b.String()})
+	}
+
+	return append(msgs, ChatMessage{Role: "user", Content: in.Text})
+}
+
+func personaPrompt(p Persona) string {
+	switch p {
+	case PersonaRoast:
+		return "Roast the user for the guilty confession they share, like a comedian at a roast."
+	case PersonaCoach:
+		return "Respond to the user's guilty confession like a tough but caring coach, with one concrete next step."
+	case PersonaChill:
+		return "Respond to the user's guilty confession like a laid-back friend who finds it mildly funny."
+	default:
+		return "Respond to the user's guilty confession with a short, dry remark."
+	}
+}
+
+func intensityPrompt(intensity int) string {
+	switch i := clampIntensity(intensity); {
+	case i <= 3:
+		return "keep it gentle."
+	case i <= 6:
+		return "be pointed but friendly."
+	default:
+		return "be savage."
+	}
+}
+
+func clampIntensity(intensity int) int {
+	if intensity < 0 {
+		return 0
+	}
+	if intensity > 10 {
+		return 10
+	}
+	return intensity
+}
@@ -0,0 +1,186 @@
+package ml
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//go:embed models/guilt-v1.json
+var defaultScoringModel []byte
+
+// ScoreInput is what the scoring model looks at
+type ScoreInput struct {
+	Text string
+	// GuiltLevel is the user's own 0-10 rating; 0 means not given
+	GuiltLevel int
+	// At is when the entry was written, in the writer's time zone; the zero
+	// time skips the time of day
+	At time.Time
+}
+
+// ScoreFeatures are the model inputs, each scaled to [0,1] except Sentiment,
+// which runs from -1 (positive) to 1 (negative)
+type ScoreFeatures struct {
+	Sentiment       float64
+	Procrastination float64
+	GuiltLevel      float64
+	LateNight       float64
+	Length          float64
+}
+
+// ScoringModel is a logistic model over lexicon features. Weights, lexicons and
+// the version live in a model file so they can change without a release.
+type ScoringModel struct {
+	Version string  `json:"version"`
+	Bias    float64 `json:"bias"`
+	Weights struct {
+		Sentiment       float64 `json:"sentiment"`
+		Procrastination float64 `json:"procrastination"`
+		GuiltLevel      float64 `json:"guilt_level"`
+		LateNight       float64 `json:"late_night"`
+		Length          float64 `json:"length"`
+	} `json:"weights"`
+	// LengthSaturation is the word count at which the length feature reaches 1
+	LengthSaturation int `json:"length_saturation"`
+	// LateNightHours is [start, end) in the hours of ScoreInput.At's location and
+	// may wrap midnight
+	LateNightHours [2]int `json:"late_night_hours"`
+	Lexicon        struct {
+		Negative []string `json:"negative"`
+		Positive []string `json:"positive"`
+		// Negators flip the sentiment of the word right after them
+		Negators []string `json:"negators"`
+		// Procrastination terms may be phrases. A word already in the
+		// sentiment lists would count twice, so the lists must be disjoint.
+		Procrastination []string `json:"procrastination"`
+	} `json:"lexicon"`
+}
+
+// LoadScoringModel reads a model file, see models/guilt-v1.json for the format
+func LoadScoringModel(path string) (*ScoringModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read scoring model: %w", err)
+	}
+	return ParseScoringModel(data)
+}
+
+// DefaultScoringModel returns the model built into the binary
+func DefaultScoringModel() *ScoringModel {
+	m, err := ParseScoringModel(defaultScoringModel)
+	if err != nil {
+		panic("ml: invalid built-in scoring model: " + err.Error())
+	}
+	return m
+}
+
+func ParseScoringModel(data []byte) (*ScoringModel, error) {
+	var m ScoringModel
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse scoring model: %w", err)
+	}
+	if m.Version == "" {
+		return nil, errors.New("scoring model: version is required")
+	}
+	if m.LengthSaturation <= 0 {
+		return nil, errors.New("scoring model: length_saturation must be positive")
+	}
+	for _, h := range m.LateNightHours {
+		if h < 0 || h > 23 {
+			return nil, fmt.Errorf("scoring model: late_night_hours out of range: %v", m.LateNightHours)
+		}
+	}
+	for _, term := range m.Lexicon.Procrastination {
+		for _, w := range strings.Fields(term) {
+			if contains(m.Lexicon.Negative, w) || contains(m.Lexicon.Positive, w) || contains(m.Lexicon.Negators, w) {
+				return nil, fmt.Errorf("scoring model: procrastination term %q overlaps the sentiment lexicon", term)
+			}
+		}
+	}
+	return &m, nil
+}
+
+// Score returns the guilt score in [0,1]
+func (m *ScoringModel) Score(in ScoreInput) float64 {
+	f := m.Features(in)
+	z := m.Bias +
+		m.Weights.Sentiment*f.Sentiment +
+		m.Weights.Procrastination*f.Procrastination +
+		m.Weights.GuiltLevel*f.GuiltLevel +
+		m.Weights.LateNight*f.LateNight +
+		m.Weights.Length*f.Length
+	return 1 / (1 + math.Exp(-z))
+}
+
+func (m *ScoringModel) Features(in ScoreInput) ScoreFeatures {
+	words := strings.FieldsFunc(strings.ToLower(in.Text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+	// padded so phrases only match whole words
+	text := " " + strings.Join(words, " ") + " "
+
+	var f ScoreFeatures
+	if neg, pos := m.sentiment(words); neg+pos > 0 {
+		f.Sentiment = float64(neg-pos) / float64(neg+pos)
+	}
+	f.Procrastination = math.Min(1, float64(countTerms(text, m.Lexicon.Procrastination))/2)
+	if in.GuiltLevel > 0 {
+		f.GuiltLevel = float64(clampIntensity(in.GuiltLevel)) / 10
+	}
+	if !in.At.IsZero() && m.lateNight(in.At.Hour()) {
+		f.LateNight = 1
+	}
+	f.Length = math.Min(1, float64(len(words))/float64(m.LengthSaturation))
+	return f
+}
+
+// sentiment counts negative and positive words, so "not productive" is negative
+func (m *ScoringModel) sentiment(words []string) (neg, pos int) {
+	for i, w := range words {
+		negative, positive := contains(m.Lexicon.Negative, w), contains(m.Lexicon.Positive, w)
+		if !negative && !positive {
+			continue
+		}
+		if i > 0 && contains(m.Lexicon.Negators, words[i-1]) {
+			negative, positive = positive, negative
+		}
+		if negative {
+			neg++
+		} else {
+			pos++
+		}
+	}
+	return neg, pos
+}
+
+func (m *ScoringModel) lateNight(hour int) bool {
+	start, end := m.LateNightHours[0], m.LateNightHours[1]
+	if start <= end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+func countTerms(text string, terms []string) int {
+	n := 0
+	for _, term := range terms {
+		n += strings.Count(text, " "+term+" ")
+	}
+	return n
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package ml
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+//go:embed templates/default.json
+var defaultTemplates []byte
+
+// intensity bands templates are keyed by
+const (
+	bandLow  = "low"  // 0-3
+	bandMid  = "mid"  // 4-6
+	bandHigh = "high" // 7-10
+)
+
+// TemplateSet renders canned roasts when no LLM is available. Templates are
+// keyed by persona and intensity band and use text/template with TemplateData.
+type TemplateSet struct {
+	Version  int
+	personas map[string]map[string][]*template.Template
+}
+
+// TemplateData is what a template can reference, e.g. {{.Snippet}}
+type TemplateData struct {
+	// Snippet is the start of the entry, short enough to quote
+	Snippet   string
+	Text      string
+	Intensity int
+}
+
+type templateFile struct {
+	Version  int                            `json:"version"`
+	Personas map[string]map[string][]string `json:"personas"`
+}
+
+// LoadTemplates reads a template file, see templates/default.json for the format
+func LoadTemplates(path string) (*TemplateSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+	return ParseTemplates(data)
+}
+
+// DefaultTemplates returns the templates built into the binary
+func DefaultTemplates() *TemplateSet {
+	t, err := ParseTemplates(defaultTemplates)
+	if err != nil {
+		panic("ml: invalid built-in templates: " + err.Error())
+	}
+	return t
+}
+
+func ParseTemplates(data []byte) (*TemplateSet, error) {
+	var f templateFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse templates: %w", err)
+	}
+
+	set := &TemplateSet{Version: f.Version, personas: map[string]map[string][]*template.Template{}}
+	for persona, bands := range f.Personas {
+		if _, ok := ParsePersona(persona); !ok {
+			return nil, fmt.Errorf("templates: unknown persona %q", persona)
+		}
+		set.personas[persona] = map[string][]*template.Template{}
+		for band, texts := range bands {
+			if band != bandLow && band != bandMid && band != bandHigh {
+				return nil, fmt.Errorf("templates: unknown intensity band %q for %s", band, persona)
+			}
+			for i, text := range texts {
+				tmpl, err := template.New(fmt.Sprintf("%s.%s.%d", persona, band, i)).Option("missingkey=error").Parse(text)
+				if err != nil {
+					return nil, fmt.Errorf("templates: %w", err)
+				}
+				set.personas[persona][band] = append(set.personas[persona][band], tmpl)
+			}
+		}
+	}
+	if anyBand(set.personas[personaName(PersonaNeutral)]) == nil {
+		return nil, fmt.Errorf("templates: %s templates are required as the last fallback", personaName(PersonaNeutral))
+	}
+	return set, nil
+}
+
+// Render picks a template for the input's persona and intensity. The same
+// entry always gets the same template. Personas or bands without templates
+// fall back to another band of the persona, then to neutral.
+func (t *TemplateSet) Render(in HybridInput) (string, error) {
+	band := intensityBand(in.Intensity)
+	candidates := t.personas[personaName(in.Persona)]
+	choices := candidates[band]
+	if len(choices) == 0 {
+		choices = anyBand(candidates)
+	}
+	if len(choices) == 0 {
+		neutral := t.personas[personaName(PersonaNeutral)]
+		if choices = neutral[band]; len(choices) == 0 {
+			choices = anyBand(neutral)
+		}
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(in.Text))
+	tmpl := choices[int(h.Sum32()%uint32(len(choices)))]
+
+	var b strings.Builder
+	err := tmpl.Execute(&b, TemplateData{
+		Snippet:   snippet(in.Text, 60),
+		Text:      in.Text,
+		Intensity: clampIntensity(in.Intensity),
+	})
+	if err != nil {
+		return "", fmt.Errorf("render template %s: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func anyBand(bands map[string][]*template.Template) []*template.Template {
+	for _, band := range []string{bandMid, bandLow, bandHigh} {
+		if len(bands[band]) > 0 {
+			return bands[band]
+		}
+	}
+	return nil
+}
+
+func intensityBand(intensity int) string {
+	switch i := clampIntensity(intensity); {
+	case i <= 3:
+		return bandLow
+	case i <= 6:
+		return bandMid
+	default:
+		return bandHigh
+	}
+}
+
+// snippet shortens text to at most max bytes, cutting at a word boundary
+func snippet(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= max {
+		return text
+	}
+	cut := strings.LastIndex(text[:max], " ")
+	if cut <= 0 {
+		cut = max
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+	}
+	return text[:cut] + "…"
+}
@@ -0,0 +1,104 @@
+// Package outbox relays messages written to the outbox table in the same
+// transaction as domain rows (see EntriesRepository.CreateEntryWithOutbox)
+// to the job queue. Messages are marked sent only after the queue accepted
+// them, so every message is delivered at least once. Messages that can never
+// be published are marked dead so they do not hold up the ones behind them.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	sqlc "guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/queue"
+	"guiltmachine/internal/repository"
+)
+
+// TopicEntryMLJob carries a queue.EntryMLJob for a newly created entry
+const TopicEntryMLJob = "entry.ml_job"
+
+const (
+	defaultBatchSize = 100
+	// sent rows are kept for a while to help debugging, then purged
+	sentRetention = 24 * time.Hour
+	purgeInterval = time.Hour
+)
+
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher queue.Publisher
+	interval  time.Duration
+	batch     int32
+	lastPurge time.Time
+}
+
+// NewRelay creates a Relay that checks for unsent messages every interval
+func NewRelay(repo repository.OutboxRepository, publisher queue.Publisher, interval time.Duration) *Relay {
+	return &Relay{repo: repo, publisher: publisher, interval: interval, batch: defaultBatchSize}
+}
+
+// Run relays until ctx is done
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// drain full batches back to back, then wait for the next tick
+		for {
+			sent, err := r.RelayOnce(ctx)
+			if err != nil {
+				log.Printf("outbox relay failed: %v", err)
+				break
+			}
+			if sent < int(r.batch) {
+				break
+			}
+		}
+		r.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of unsent messages and returns how many were sent
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	return r.repo.RelayBatch(ctx, r.batch, r.publish)
+}
+
+func (r *Relay) publish(ctx context.Context, m sqlc.Outbox) error {
+	switch m.Topic {
+	case TopicEntryMLJob:
+		var job queue.EntryMLJob
+		if err := json.Unmarshal(m.Payload, &job); err != nil {
+			return r.undeliverable(fmt.Errorf("outbox %d: decode: %w: %w", m.ID, repository.ErrUndeliverable, err))
+		}
+		return r.publisher.Publish(ctx, job)
+	default:
+		return r.undeliverable(fmt.Errorf("outbox %d: %w: unknown topic %q", m.ID, repository.ErrUndeliverable, m.Topic))
+	}
+}
+
+// undeliverable logs a message the repository is about to set aside for good
+func (r *Relay) undeliverable(err error) error {
+	log.Printf("outbox relay dropped a message: %v", err)
+	return err
+}
+
+func (r *Relay) purge(ctx context.Context) {
+	if time.Since(r.lastPurge) < purgeInterval {
+		return
+	}
+	r.lastPurge = time.Now()
+	if n, err := r.repo.DeleteSent(ctx, time.Now().Add(-sentRetention)); err != nil {
+		log.Printf("outbox purge failed: %v", err)
+	} else if n > 0 {
+		log.Printf("outbox purged %d sent messages", n)
+	}
+}
@@ -490,6 +490,290 @@ func (x *GetEntryResponse) GetGuiltScore() int32 {
 	return 0
 }
 
+type StreamRoastRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntryId       string                 `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRoastRequest) Reset() {
+	*x = StreamRoastRequest{}
+	mi := &file_internal_proto_entry_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRoastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRoastRequest) ProtoMessage() {}
+
+func (x *StreamRoastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_entry_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRoastRequest.ProtoReflect.Descriptor instead.
+func (*StreamRoastRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_entry_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamRoastRequest) GetEntryId() string {
+	if x != nil {
+		return x.EntryId
+	}
+	return ""
+}
+
+type StreamRoastResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delta         string                 `protobuf:"bytes,1,opt,name=delta,proto3" json:"delta,omitempty"`                              // text generated since the previous message
+	Done          bool                   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`                               // set on the last message only
+	RoastText     string                 `protobuf:"bytes,3,opt,name=roast_text,json=roastText,proto3" json:"roast_text,omitempty"`     // full persisted roast, last message only
+	GuiltScore    int32                  `protobuf:"varint,4,opt,name=guilt_score,json=guiltScore,proto3" json:"guilt_score,omitempty"` // last message only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRoastResponse) Reset() {
+	*x = StreamRoastResponse{}
+	mi := &file_internal_proto_entry_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRoastResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRoastResponse) ProtoMessage() {}
+
+func (x *StreamRoastResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_entry_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRoastResponse.ProtoReflect.Descriptor instead.
+func (*StreamRoastResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_entry_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamRoastResponse) GetDelta() string {
+	if x != nil {
+		return x.Delta
+	}
+	return ""
+}
+
+func (x *StreamRoastResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *StreamRoastResponse) GetRoastText() string {
+	if x != nil {
+		return x.RoastText
+	}
+	return ""
+}
+
+func (x *StreamRoastResponse) GetGuiltScore() int32 {
+	if x != nil {
+		return x.GuiltScore
+	}
+	return 0
+}
+
+type WatchEntryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntryId       string                 `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEntryRequest) Reset() {
+	*x = WatchEntryRequest{}
+	mi := &file_internal_proto_entry_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEntryRequest) ProtoMessage() {}
+
+func (x *WatchEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_entry_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEntryRequest.ProtoReflect.Descriptor instead.
+func (*WatchEntryRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_entry_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WatchEntryRequest) GetEntryId() string {
+	if x != nil {
+		return x.EntryId
+	}
+	return ""
+}
+
+type WatchSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSessionRequest) Reset() {
+	*x = WatchSessionRequest{}
+	mi := &file_internal_proto_entry_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSessionRequest) ProtoMessage() {}
+
+func (x *WatchSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_entry_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSessionRequest.ProtoReflect.Descriptor instead.
+func (*WatchSessionRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_entry_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type EntryEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntryId       string                 `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // pending/processing/completed/failed
+	RoastText     string                 `protobuf:"bytes,4,opt,name=roast_text,json=roastText,proto3" json:"roast_text,omitempty"`
+	GuiltScore    int32                  `protobuf:"varint,5,opt,name=guilt_score,json=guiltScore,proto3" json:"guilt_score,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntryEvent) Reset() {
+	*x = EntryEvent{}
+	mi := &file_internal_proto_entry_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntryEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntryEvent) ProtoMessage() {}
+
+func (x *EntryEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_entry_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntryEvent.ProtoReflect.Descriptor instead.
+func (*EntryEvent) Descriptor() ([]byte, []int) {
+	return file_internal_proto_entry_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EntryEvent) GetEntryId() string {
+	if x != nil {
+		return x.EntryId
+	}
+	return ""
+}
+
+func (x *EntryEvent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *EntryEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *EntryEvent) GetRoastText() string {
+	if x != nil {
+		return x.RoastText
+	}
+	return ""
+}
+
+func (x *EntryEvent) GetGuiltScore() int32 {
+	if x != nil {
+		return x.GuiltScore
+	}
+	return 0
+}
+
+func (x *EntryEvent) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
 var File_internal_proto_entry_proto protoreflect.FileDescriptor
 
 const file_internal_proto_entry_proto_rawDesc = "" +
@@ -539,11 +823,41 @@ const file_internal_proto_entry_proto_rawDesc = "" +
 	"\n" +
 	"roast_text\x18\a \x01(\tR\troastText\x12\x1f\n" +
 	"\vguilt_score\x18\b \x01(\x05R\n" +
-	"guiltScore2\x93\x02\n" +
+	"guiltScore\"/\n" +
+	"\x12StreamRoastRequest\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\tR\aentryId\"\x7f\n" +
+	"\x13StreamRoastResponse\x12\x14\n" +
+	"\x05delta\x18\x01 \x01(\tR\x05delta\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done\x12\x1d\n" +
+	"\n" +
+	"roast_text\x18\x03 \x01(\tR\troastText\x12\x1f\n" +
+	"\vguilt_score\x18\x04 \x01(\x05R\n" +
+	"guiltScore\".\n" +
+	"\x11WatchEntryRequest\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\tR\aentryId\"4\n" +
+	"\x13WatchSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\xd9\x01\n" +
+	"\n" +
+	"EntryEvent\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\tR\aentryId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"roast_text\x18\x04 \x01(\tR\troastText\x12\x1f\n" +
+	"\vguilt_score\x18\x05 \x01(\x05R\n" +
+	"guiltScore\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt2\x95\x04\n" +
 	"\fEntryService\x12X\n" +
 	"\vCreateEntry\x12#.guiltmachine.v1.CreateEntryRequest\x1a$.guiltmachine.v1.CreateEntryResponse\x12X\n" +
 	"\vListEntries\x12#.guiltmachine.v1.ListEntriesRequest\x1a$.guiltmachine.v1.ListEntriesResponse\x12O\n" +
-	"\bGetEntry\x12 .guiltmachine.v1.GetEntryRequest\x1a!.guiltmachine.v1.GetEntryResponseB/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
+	"\bGetEntry\x12 .guiltmachine.v1.GetEntryRequest\x1a!.guiltmachine.v1.GetEntryResponse\x12Z\n" +
+	"\vStreamRoast\x12#.guiltmachine.v1.StreamRoastRequest\x1a$.guiltmachine.v1.StreamRoastResponse0\x01\x12O\n" +
+	"\n" +
+	"WatchEntry\x12\".guiltmachine.v1.WatchEntryRequest\x1a\x1b.guiltmachine.v1.EntryEvent0\x01\x12S\n" +
+	"\fWatchSession\x12$.guiltmachine.v1.WatchSessionRequest\x1a\x1b.guiltmachine.v1.EntryEvent0\x01B/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
 
 var (
 	file_internal_proto_entry_proto_rawDescOnce sync.Once
@@ -557,7 +871,7 @@ func file_internal_proto_entry_proto_rawDescGZIP() []byte {
 	return file_internal_proto_entry_proto_rawDescData
 }
 
-var file_internal_proto_entry_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_internal_proto_entry_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_internal_proto_entry_proto_goTypes = []any{
 	(*CreateEntryRequest)(nil),    // 0: guiltmachine.v1.CreateEntryRequest
 	(*CreateEntryResponse)(nil),   // 1: guiltmachine.v1.CreateEntryResponse
@@ -566,24 +880,36 @@ var file_internal_proto_entry_proto_goTypes = []any{
 	(*EntryItem)(nil),             // 4: guiltmachine.v1.EntryItem
 	(*GetEntryRequest)(nil),       // 5: guiltmachine.v1.GetEntryRequest
 	(*GetEntryResponse)(nil),      // 6: guiltmachine.v1.GetEntryResponse
-	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+	(*StreamRoastRequest)(nil),    // 7: guiltmachine.v1.StreamRoastRequest
+	(*StreamRoastResponse)(nil),   // 8: guiltmachine.v1.StreamRoastResponse
+	(*WatchEntryRequest)(nil),     // 9: guiltmachine.v1.WatchEntryRequest
+	(*WatchSessionRequest)(nil),   // 10: guiltmachine.v1.WatchSessionRequest
+	(*EntryEvent)(nil),            // 11: guiltmachine.v1.EntryEvent
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
 }
 var file_internal_proto_entry_proto_depIdxs = []int32{
-	7, // 0: guiltmachine.v1.CreateEntryResponse.created_at:type_name -> google.protobuf.Timestamp
-	4, // 1: guiltmachine.v1.ListEntriesResponse.entries:type_name -> guiltmachine.v1.EntryItem
-	7, // 2: guiltmachine.v1.EntryItem.created_at:type_name -> google.protobuf.Timestamp
-	7, // 3: guiltmachine.v1.GetEntryResponse.created_at:type_name -> google.protobuf.Timestamp
-	0, // 4: guiltmachine.v1.EntryService.CreateEntry:input_type -> guiltmachine.v1.CreateEntryRequest
-	2, // 5: guiltmachine.v1.EntryService.ListEntries:input_type -> guiltmachine.v1.ListEntriesRequest
-	5, // 6: guiltmachine.v1.EntryService.GetEntry:input_type -> guiltmachine.v1.GetEntryRequest
-	1, // 7: guiltmachine.v1.EntryService.CreateEntry:output_type -> guiltmachine.v1.CreateEntryResponse
-	3, // 8: guiltmachine.v1.EntryService.ListEntries:output_type -> guiltmachine.v1.ListEntriesResponse
-	6, // 9: guiltmachine.v1.EntryService.GetEntry:output_type -> guiltmachine.v1.GetEntryResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	12, // 0: guiltmachine.v1.CreateEntryResponse.created_at:type_name -> google.protobuf.Timestamp
+	4,  // 1: guiltmachine.v1.ListEntriesResponse.entries:type_name -> guiltmachine.v1.EntryItem
+	12, // 2: guiltmachine.v1.EntryItem.created_at:type_name -> google.protobuf.Timestamp
+	12, // 3: guiltmachine.v1.GetEntryResponse.created_at:type_name -> google.protobuf.Timestamp
+	12, // 4: guiltmachine.v1.EntryEvent.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: guiltmachine.v1.EntryService.CreateEntry:input_type -> guiltmachine.v1.CreateEntryRequest
+	2,  // 6: guiltmachine.v1.EntryService.ListEntries:input_type -> guiltmachine.v1.ListEntriesRequest
+	5,  // 7: guiltmachine.v1.EntryService.GetEntry:input_type -> guiltmachine.v1.GetEntryRequest
+	7,  // 8: guiltmachine.v1.EntryService.StreamRoast:input_type -> guiltmachine.v1.StreamRoastRequest
+	9,  // 9: guiltmachine.v1.EntryService.WatchEntry:input_type -> guiltmachine.v1.WatchEntryRequest
+	10, // 10: guiltmachine.v1.EntryService.WatchSession:input_type -> guiltmachine.v1.WatchSessionRequest
+	1,  // 11: guiltmachine.v1.EntryService.CreateEntry:output_type -> guiltmachine.v1.CreateEntryResponse
+	3,  // 12: guiltmachine.v1.EntryService.ListEntries:output_type -> guiltmachine.v1.ListEntriesResponse
+	6,  // 13: guiltmachine.v1.EntryService.GetEntry:output_type -> guiltmachine.v1.GetEntryResponse
+	8,  // 14: guiltmachine.v1.EntryService.StreamRoast:output_type -> guiltmachine.v1.StreamRoastResponse
+	11, // 15: guiltmachine.v1.EntryService.WatchEntry:output_type -> guiltmachine.v1.EntryEvent
+	11, // 16: guiltmachine.v1.EntryService.WatchSession:output_type -> guiltmachine.v1.EntryEvent
+	11, // [11:17] is the sub-list for method output_type
+	5,  // [5:11] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_internal_proto_entry_proto_init() }
@@ -597,7 +923,7 @@ func file_internal_proto_entry_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_proto_entry_proto_rawDesc), len(file_internal_proto_entry_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
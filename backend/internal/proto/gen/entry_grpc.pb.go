@@ -19,9 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	EntryService_CreateEntry_FullMethodName = "/guiltmachine.v1.EntryService/CreateEntry"
-	EntryService_ListEntries_FullMethodName = "/guiltmachine.v1.EntryService/ListEntries"
-	EntryService_GetEntry_FullMethodName    = "/guiltmachine.v1.EntryService/GetEntry"
+	EntryService_CreateEntry_FullMethodName  = "/guiltmachine.v1.EntryService/CreateEntry"
+	EntryService_ListEntries_FullMethodName  = "/guiltmachine.v1.EntryService/ListEntries"
+	EntryService_GetEntry_FullMethodName     = "/guiltmachine.v1.EntryService/GetEntry"
+	EntryService_StreamRoast_FullMethodName  = "/guiltmachine.v1.EntryService/StreamRoast"
+	EntryService_WatchEntry_FullMethodName   = "/guiltmachine.v1.EntryService/WatchEntry"
+	EntryService_WatchSession_FullMethodName = "/guiltmachine.v1.EntryService/WatchSession"
 )
 
 // EntryServiceClient is the client API for EntryService service.
@@ -36,6 +39,15 @@ type EntryServiceClient interface {
 	ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error)
 	// GetEntry retrieves a single entry with score and roast
 	GetEntry(ctx context.Context, in *GetEntryRequest, opts ...grpc.CallOption) (*GetEntryResponse, error)
+	// StreamRoast generates the roast for an entry, streaming text as the model
+	// produces it; entries that already have a roast get it in a single message
+	StreamRoast(ctx context.Context, in *StreamRoastRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamRoastResponse], error)
+	// WatchEntry sends the entry's current state, then each change, and ends
+	// once the entry is completed or failed
+	WatchEntry(ctx context.Context, in *WatchEntryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EntryEvent], error)
+	// WatchSession sends the current state of every entry in the session, then
+	// each change, until the client cancels
+	WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EntryEvent], error)
 }
 
 type entryServiceClient struct {
@@ -76,6 +88,63 @@ func (c *entryServiceClient) GetEntry(ctx context.Context, in *GetEntryRequest,
 	return out, nil
 }
 
+func (c *entryServiceClient) StreamRoast(ctx context.Context, in *StreamRoastRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamRoastResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[0], EntryService_StreamRoast_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRoastRequest, StreamRoastResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_StreamRoastClient = grpc.ServerStreamingClient[StreamRoastResponse]
+
+func (c *entryServiceClient) WatchEntry(ctx context.Context, in *WatchEntryRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EntryEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[1], EntryService_WatchEntry_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEntryRequest, EntryEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_WatchEntryClient = grpc.ServerStreamingClient[EntryEvent]
+
+func (c *entryServiceClient) WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[EntryEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EntryService_ServiceDesc.Streams[2], EntryService_WatchSession_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchSessionRequest, EntryEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_WatchSessionClient = grpc.ServerStreamingClient[EntryEvent]
+
 // EntryServiceServer is the server API for EntryService service.
 // All implementations must embed UnimplementedEntryServiceServer
 // for forward compatibility.
@@ -88,6 +157,15 @@ type EntryServiceServer interface {
 	ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error)
 	// GetEntry retrieves a single entry with score and roast
 	GetEntry(context.Context, *GetEntryRequest) (*GetEntryResponse, error)
+	// StreamRoast generates the roast for an entry, streaming text as the model
+	// produces it; entries that already have a roast get it in a single message
+	StreamRoast(*StreamRoastRequest, grpc.ServerStreamingServer[StreamRoastResponse]) error
+	// WatchEntry sends the entry's current state, then each change, and ends
+	// once the entry is completed or failed
+	WatchEntry(*WatchEntryRequest, grpc.ServerStreamingServer[EntryEvent]) error
+	// WatchSession sends the current state of every entry in the session, then
+	// each change, until the client cancels
+	WatchSession(*WatchSessionRequest, grpc.ServerStreamingServer[EntryEvent]) error
 	mustEmbedUnimplementedEntryServiceServer()
 }
 
@@ -107,6 +185,15 @@ func (UnimplementedEntryServiceServer) ListEntries(context.Context, *ListEntries
 func (UnimplementedEntryServiceServer) GetEntry(context.Context, *GetEntryRequest) (*GetEntryResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetEntry not implemented")
 }
+func (UnimplementedEntryServiceServer) StreamRoast(*StreamRoastRequest, grpc.ServerStreamingServer[StreamRoastResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamRoast not implemented")
+}
+func (UnimplementedEntryServiceServer) WatchEntry(*WatchEntryRequest, grpc.ServerStreamingServer[EntryEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchEntry not implemented")
+}
+func (UnimplementedEntryServiceServer) WatchSession(*WatchSessionRequest, grpc.ServerStreamingServer[EntryEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchSession not implemented")
+}
 func (UnimplementedEntryServiceServer) mustEmbedUnimplementedEntryServiceServer() {}
 func (UnimplementedEntryServiceServer) testEmbeddedByValue()                      {}
 
@@ -182,6 +269,39 @@ func _EntryService_GetEntry_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EntryService_StreamRoast_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRoastRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).StreamRoast(m, &grpc.GenericServerStream[StreamRoastRequest, StreamRoastResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_StreamRoastServer = grpc.ServerStreamingServer[StreamRoastResponse]
+
+func _EntryService_WatchEntry_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEntryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).WatchEntry(m, &grpc.GenericServerStream[WatchEntryRequest, EntryEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_WatchEntryServer = grpc.ServerStreamingServer[EntryEvent]
+
+func _EntryService_WatchSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSessionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EntryServiceServer).WatchSession(m, &grpc.GenericServerStream[WatchSessionRequest, EntryEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EntryService_WatchSessionServer = grpc.ServerStreamingServer[EntryEvent]
+
 // EntryService_ServiceDesc is the grpc.ServiceDesc for EntryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -202,6 +322,22 @@ var EntryService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _EntryService_GetEntry_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamRoast",
+			Handler:       _EntryService_StreamRoast_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEntry",
+			Handler:       _EntryService_WatchEntry_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchSession",
+			Handler:       _EntryService_WatchSession_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "internal/proto/entry.proto",
 }
@@ -9,6 +9,7 @@ package ml
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,14 +22,71 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Persona int32
+
+const (
+	Persona_PERSONA_NEUTRAL Persona = 0
+	Persona_PERSONA_ROAST   Persona = 1
+	Persona_PERSONA_COACH   Persona = 2
+	Persona_PERSONA_CHILL   Persona = 3
+)
+
+// Enum value maps for Persona.
+var (
+	Persona_name = map[int32]string{
+		0: "PERSONA_NEUTRAL",
+		1: "PERSONA_ROAST",
+		2: "PERSONA_COACH",
+		3: "PERSONA_CHILL",
+	}
+	Persona_value = map[string]int32{
+		"PERSONA_NEUTRAL": 0,
+		"PERSONA_ROAST":   1,
+		"PERSONA_COACH":   2,
+		"PERSONA_CHILL":   3,
+	}
+)
+
+func (x Persona) Enum() *Persona {
+	p := new(Persona)
+	*p = x
+	return p
+}
+
+func (x Persona) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Persona) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_proto_ml_ml_proto_enumTypes[0].Descriptor()
+}
+
+func (Persona) Type() protoreflect.EnumType {
+	return &file_internal_proto_ml_ml_proto_enumTypes[0]
+}
+
+func (x Persona) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Persona.Descriptor instead.
+func (Persona) EnumDescriptor() ([]byte, []int) {
+	return file_internal_proto_ml_ml_proto_rawDescGZIP(), []int{0}
+}
+
 type RoastRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	EntryText      string                 `protobuf:"bytes,1,opt,name=entry_text,json=entryText,proto3" json:"entry_text,omitempty"`
 	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	HumorIntensity int32                  `protobuf:"varint,3,opt,name=humor_intensity,json=humorIntensity,proto3" json:"humor_intensity,omitempty"`
-	History        []string               `protobuf:"bytes,4,rep,name=history,proto3" json:"history,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	Persona        Persona                `protobuf:"varint,4,opt,name=persona,proto3,enum=ml.Persona" json:"persona,omitempty"`
+	History        []string               `protobuf:"bytes,5,rep,name=history,proto3" json:"history,omitempty"`
+	// self-reported guilt 0-10, 0 when not given
+	GuiltLevel int32 `protobuf:"varint,6,opt,name=guilt_level,json=guiltLevel,proto3" json:"guilt_level,omitempty"`
+	// when the entry was written; feeds the time of day into the score
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RoastRequest) Reset() {
@@ -82,6 +140,13 @@ func (x *RoastRequest) GetHumorIntensity() int32 {
 	return 0
 }
 
+func (x *RoastRequest) GetPersona() Persona {
+	if x != nil {
+		return x.Persona
+	}
+	return Persona_PERSONA_NEUTRAL
+}
+
 func (x *RoastRequest) GetHistory() []string {
 	if x != nil {
 		return x.History
@@ -89,12 +154,28 @@ func (x *RoastRequest) GetHistory() []string {
 	return nil
 }
 
+func (x *RoastRequest) GetGuiltLevel() int32 {
+	if x != nil {
+		return x.GuiltLevel
+	}
+	return 0
+}
+
+func (x *RoastRequest) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type RoastResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	GuiltScore    float64                `protobuf:"fixed64,1,opt,name=guilt_score,json=guiltScore,proto3" json:"guilt_score,omitempty"`
-	RoastText     string                 `protobuf:"bytes,2,opt,name=roast_text,json=roastText,proto3" json:"roast_text,omitempty"`
-	Tags          []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
-	SafetyFlags   []string               `protobuf:"bytes,4,rep,name=safety_flags,json=safetyFlags,proto3" json:"safety_flags,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	GuiltScore  float64                `protobuf:"fixed64,1,opt,name=guilt_score,json=guiltScore,proto3" json:"guilt_score,omitempty"`
+	RoastText   string                 `protobuf:"bytes,2,opt,name=roast_text,json=roastText,proto3" json:"roast_text,omitempty"`
+	Tags        []string               `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	SafetyFlags []string               `protobuf:"bytes,4,rep,name=safety_flags,json=safetyFlags,proto3" json:"safety_flags,omitempty"`
+	// version of the scoring model that produced guilt_score
+	ModelVersion  string `protobuf:"bytes,5,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -157,24 +238,42 @@ func (x *RoastResponse) GetSafetyFlags() []string {
 	return nil
 }
 
+func (x *RoastResponse) GetModelVersion() string {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return ""
+}
+
 var File_internal_proto_ml_ml_proto protoreflect.FileDescriptor
 
 const file_internal_proto_ml_ml_proto_rawDesc = "" +
 	"\n" +
-	"\x1ainternal/proto/ml/ml.proto\x12\x02ml\"\x89\x01\n" +
+	"\x1ainternal/proto/ml/ml.proto\x12\x02ml\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8c\x02\n" +
 	"\fRoastRequest\x12\x1d\n" +
 	"\n" +
 	"entry_text\x18\x01 \x01(\tR\tentryText\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
-	"\x0fhumor_intensity\x18\x03 \x01(\x05R\x0ehumorIntensity\x12\x18\n" +
-	"\ahistory\x18\x04 \x03(\tR\ahistory\"\x86\x01\n" +
+	"\x0fhumor_intensity\x18\x03 \x01(\x05R\x0ehumorIntensity\x12%\n" +
+	"\apersona\x18\x04 \x01(\x0e2\v.ml.PersonaR\apersona\x12\x18\n" +
+	"\ahistory\x18\x05 \x03(\tR\ahistory\x12\x1f\n" +
+	"\vguilt_level\x18\x06 \x01(\x05R\n" +
+	"guiltLevel\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xab\x01\n" +
 	"\rRoastResponse\x12\x1f\n" +
 	"\vguilt_score\x18\x01 \x01(\x01R\n" +
 	"guiltScore\x12\x1d\n" +
 	"\n" +
 	"roast_text\x18\x02 \x01(\tR\troastText\x12\x12\n" +
 	"\x04tags\x18\x03 \x03(\tR\x04tags\x12!\n" +
-	"\fsafety_flags\x18\x04 \x03(\tR\vsafetyFlags29\n" +
+	"\fsafety_flags\x18\x04 \x03(\tR\vsafetyFlags\x12#\n" +
+	"\rmodel_version\x18\x05 \x01(\tR\fmodelVersion*W\n" +
+	"\aPersona\x12\x13\n" +
+	"\x0fPERSONA_NEUTRAL\x10\x00\x12\x11\n" +
+	"\rPERSONA_ROAST\x10\x01\x12\x11\n" +
+	"\rPERSONA_COACH\x10\x02\x12\x11\n" +
+	"\rPERSONA_CHILL\x10\x0329\n" +
 	"\tMLService\x12,\n" +
 	"\x05Roast\x12\x10.ml.RoastRequest\x1a\x11.ml.RoastResponseB'Z%guiltmachine/internal/proto/gen/ml;mlb\x06proto3"
 
@@ -190,19 +289,24 @@ func file_internal_proto_ml_ml_proto_rawDescGZIP() []byte {
 	return file_internal_proto_ml_ml_proto_rawDescData
 }
 
+var file_internal_proto_ml_ml_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_internal_proto_ml_ml_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_internal_proto_ml_ml_proto_goTypes = []any{
-	(*RoastRequest)(nil),  // 0: ml.RoastRequest
-	(*RoastResponse)(nil), // 1: ml.RoastResponse
+	(Persona)(0),                  // 0: ml.Persona
+	(*RoastRequest)(nil),          // 1: ml.RoastRequest
+	(*RoastResponse)(nil),         // 2: ml.RoastResponse
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
 }
 var file_internal_proto_ml_ml_proto_depIdxs = []int32{
-	0, // 0: ml.MLService.Roast:input_type -> ml.RoastRequest
-	1, // 1: ml.MLService.Roast:output_type -> ml.RoastResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: ml.RoastRequest.persona:type_name -> ml.Persona
+	3, // 1: ml.RoastRequest.created_at:type_name -> google.protobuf.Timestamp
+	1, // 2: ml.MLService.Roast:input_type -> ml.RoastRequest
+	2, // 3: ml.MLService.Roast:output_type -> ml.RoastResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_internal_proto_ml_ml_proto_init() }
@@ -215,13 +319,14 @@ func file_internal_proto_ml_ml_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_proto_ml_ml_proto_rawDesc), len(file_internal_proto_ml_ml_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_internal_proto_ml_ml_proto_goTypes,
 		DependencyIndexes: file_internal_proto_ml_ml_proto_depIdxs,
+		EnumInfos:         file_internal_proto_ml_ml_proto_enumTypes,
 		MessageInfos:      file_internal_proto_ml_ml_proto_msgTypes,
 	}.Build()
 	File_internal_proto_ml_ml_proto = out.File
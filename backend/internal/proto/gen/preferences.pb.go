@@ -9,6 +9,7 @@ package v1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,19 +22,298 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Persona int32
+
+const (
+	Persona_PERSONA_UNSPECIFIED Persona = 0
+	Persona_PERSONA_NEUTRAL     Persona = 1
+	Persona_PERSONA_ROAST       Persona = 2
+	Persona_PERSONA_COACH       Persona = 3
+	Persona_PERSONA_CHILL       Persona = 4
+)
+
+// Enum value maps for Persona.
+var (
+	Persona_name = map[int32]string{
+		0: "PERSONA_UNSPECIFIED",
+		1: "PERSONA_NEUTRAL",
+		2: "PERSONA_ROAST",
+		3: "PERSONA_COACH",
+		4: "PERSONA_CHILL",
+	}
+	Persona_value = map[string]int32{
+		"PERSONA_UNSPECIFIED": 0,
+		"PERSONA_NEUTRAL":     1,
+		"PERSONA_ROAST":       2,
+		"PERSONA_COACH":       3,
+		"PERSONA_CHILL":       4,
+	}
+)
+
+func (x Persona) Enum() *Persona {
+	p := new(Persona)
+	*p = x
+	return p
+}
+
+func (x Persona) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Persona) Descriptor() protoreflect.EnumDescriptor {
+	return file_preferences_proto_enumTypes[0].Descriptor()
+}
+
+func (Persona) Type() protoreflect.EnumType {
+	return &file_preferences_proto_enumTypes[0]
+}
+
+func (x Persona) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Persona.Descriptor instead.
+func (Persona) EnumDescriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{0}
+}
+
+type NudgeChannel int32
+
+const (
+	NudgeChannel_NUDGE_CHANNEL_UNSPECIFIED NudgeChannel = 0
+	NudgeChannel_NUDGE_CHANNEL_PUSH        NudgeChannel = 1
+	NudgeChannel_NUDGE_CHANNEL_EMAIL       NudgeChannel = 2
+	NudgeChannel_NUDGE_CHANNEL_SMS         NudgeChannel = 3
+	NudgeChannel_NUDGE_CHANNEL_IN_APP      NudgeChannel = 4
+)
+
+// Enum value maps for NudgeChannel.
+var (
+	NudgeChannel_name = map[int32]string{
+		0: "NUDGE_CHANNEL_UNSPECIFIED",
+		1: "NUDGE_CHANNEL_PUSH",
+		2: "NUDGE_CHANNEL_EMAIL",
+		3: "NUDGE_CHANNEL_SMS",
+		4: "NUDGE_CHANNEL_IN_APP",
+	}
+	NudgeChannel_value = map[string]int32{
+		"NUDGE_CHANNEL_UNSPECIFIED": 0,
+		"NUDGE_CHANNEL_PUSH":        1,
+		"NUDGE_CHANNEL_EMAIL":       2,
+		"NUDGE_CHANNEL_SMS":         3,
+		"NUDGE_CHANNEL_IN_APP":      4,
+	}
+)
+
+func (x NudgeChannel) Enum() *NudgeChannel {
+	p := new(NudgeChannel)
+	*p = x
+	return p
+}
+
+func (x NudgeChannel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NudgeChannel) Descriptor() protoreflect.EnumDescriptor {
+	return file_preferences_proto_enumTypes[1].Descriptor()
+}
+
+func (NudgeChannel) Type() protoreflect.EnumType {
+	return &file_preferences_proto_enumTypes[1]
+}
+
+func (x NudgeChannel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NudgeChannel.Descriptor instead.
+func (NudgeChannel) EnumDescriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{1}
+}
+
+// TimeRange is a daily window in the user's timezone, "HH:MM" 24h.
+// An end before the start wraps past midnight.
+type TimeRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           string                 `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeRange) Reset() {
+	*x = TimeRange{}
+	mi := &file_preferences_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeRange) ProtoMessage() {}
+
+func (x *TimeRange) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeRange.ProtoReflect.Descriptor instead.
+func (*TimeRange) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TimeRange) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *TimeRange) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type Preferences struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Theme          string                 `protobuf:"bytes,1,opt,name=theme,proto3" json:"theme,omitempty"` // empty means none
+	Notifications  bool                   `protobuf:"varint,2,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	Persona        Persona                `protobuf:"varint,3,opt,name=persona,proto3,enum=guiltmachine.v1.Persona" json:"persona,omitempty"`        // unspecified means roast
+	HumorIntensity int32                  `protobuf:"varint,4,opt,name=humor_intensity,json=humorIntensity,proto3" json:"humor_intensity,omitempty"` // 0-10
+	Timezone       string                 `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"`                                    // IANA name, empty means UTC
+	WorkHours      *TimeRange             `protobuf:"bytes,6,opt,name=work_hours,json=workHours,proto3" json:"work_hours,omitempty"`                 // unset means none
+	QuietHours     *TimeRange             `protobuf:"bytes,7,opt,name=quiet_hours,json=quietHours,proto3" json:"quiet_hours,omitempty"`              // unset means none
+	Language       string                 `protobuf:"bytes,8,opt,name=language,proto3" json:"language,omitempty"`                                    // BCP 47 tag, empty means en
+	NudgeChannels  []NudgeChannel         `protobuf:"varint,9,rep,packed,name=nudge_channels,json=nudgeChannels,proto3,enum=guiltmachine.v1.NudgeChannel" json:"nudge_channels,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Preferences) Reset() {
+	*x = Preferences{}
+	mi := &file_preferences_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Preferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Preferences) ProtoMessage() {}
+
+func (x *Preferences) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Preferences.ProtoReflect.Descriptor instead.
+func (*Preferences) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Preferences) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *Preferences) GetNotifications() bool {
+	if x != nil {
+		return x.Notifications
+	}
+	return false
+}
+
+func (x *Preferences) GetPersona() Persona {
+	if x != nil {
+		return x.Persona
+	}
+	return Persona_PERSONA_UNSPECIFIED
+}
+
+func (x *Preferences) GetHumorIntensity() int32 {
+	if x != nil {
+		return x.HumorIntensity
+	}
+	return 0
+}
+
+func (x *Preferences) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Preferences) GetWorkHours() *TimeRange {
+	if x != nil {
+		return x.WorkHours
+	}
+	return nil
+}
+
+func (x *Preferences) GetQuietHours() *TimeRange {
+	if x != nil {
+		return x.QuietHours
+	}
+	return nil
+}
+
+func (x *Preferences) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Preferences) GetNudgeChannels() []NudgeChannel {
+	if x != nil {
+		return x.NudgeChannels
+	}
+	return nil
+}
+
 type UpsertPreferencesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Theme         string                 `protobuf:"bytes,2,opt,name=theme,proto3" json:"theme,omitempty"` // optional, empty means none
 	Notifications bool                   `protobuf:"varint,3,opt,name=notifications,proto3" json:"notifications,omitempty"`
-	MetadataJson  string                 `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	// free-form extras; persona, humor_intensity, timezone and language keys
+	// are moved to the typed fields
+	//
+	// Deprecated: Marked as deprecated in preferences.proto.
+	MetadataJson string `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	// replaces all typed preferences when set, theme and notifications above
+	// are ignored then
+	Preferences   *Preferences `protobuf:"bytes,5,opt,name=preferences,proto3" json:"preferences,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpsertPreferencesRequest) Reset() {
 	*x = UpsertPreferencesRequest{}
-	mi := &file_preferences_proto_msgTypes[0]
+	mi := &file_preferences_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -45,7 +325,7 @@ func (x *UpsertPreferencesRequest) String() string {
 func (*UpsertPreferencesRequest) ProtoMessage() {}
 
 func (x *UpsertPreferencesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_preferences_proto_msgTypes[0]
+	mi := &file_preferences_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -58,7 +338,7 @@ func (x *UpsertPreferencesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertPreferencesRequest.ProtoReflect.Descriptor instead.
 func (*UpsertPreferencesRequest) Descriptor() ([]byte, []int) {
-	return file_preferences_proto_rawDescGZIP(), []int{0}
+	return file_preferences_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *UpsertPreferencesRequest) GetUserId() string {
@@ -82,6 +362,7 @@ func (x *UpsertPreferencesRequest) GetNotifications() bool {
 	return false
 }
 
+// Deprecated: Marked as deprecated in preferences.proto.
 func (x *UpsertPreferencesRequest) GetMetadataJson() string {
 	if x != nil {
 		return x.MetadataJson
@@ -89,19 +370,28 @@ func (x *UpsertPreferencesRequest) GetMetadataJson() string {
 	return ""
 }
 
+func (x *UpsertPreferencesRequest) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
 type UpsertPreferencesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Theme         string                 `protobuf:"bytes,2,opt,name=theme,proto3" json:"theme,omitempty"`
 	Notifications bool                   `protobuf:"varint,3,opt,name=notifications,proto3" json:"notifications,omitempty"`
-	MetadataJson  string                 `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	// Deprecated: Marked as deprecated in preferences.proto.
+	MetadataJson  string       `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	Preferences   *Preferences `protobuf:"bytes,5,opt,name=preferences,proto3" json:"preferences,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpsertPreferencesResponse) Reset() {
 	*x = UpsertPreferencesResponse{}
-	mi := &file_preferences_proto_msgTypes[1]
+	mi := &file_preferences_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -113,7 +403,7 @@ func (x *UpsertPreferencesResponse) String() string {
 func (*UpsertPreferencesResponse) ProtoMessage() {}
 
 func (x *UpsertPreferencesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_preferences_proto_msgTypes[1]
+	mi := &file_preferences_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -126,7 +416,7 @@ func (x *UpsertPreferencesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertPreferencesResponse.ProtoReflect.Descriptor instead.
 func (*UpsertPreferencesResponse) Descriptor() ([]byte, []int) {
-	return file_preferences_proto_rawDescGZIP(), []int{1}
+	return file_preferences_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UpsertPreferencesResponse) GetUserId() string {
@@ -150,6 +440,7 @@ func (x *UpsertPreferencesResponse) GetNotifications() bool {
 	return false
 }
 
+// Deprecated: Marked as deprecated in preferences.proto.
 func (x *UpsertPreferencesResponse) GetMetadataJson() string {
 	if x != nil {
 		return x.MetadataJson
@@ -157,6 +448,13 @@ func (x *UpsertPreferencesResponse) GetMetadataJson() string {
 	return ""
 }
 
+func (x *UpsertPreferencesResponse) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
 type GetPreferencesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -166,7 +464,7 @@ type GetPreferencesRequest struct {
 
 func (x *GetPreferencesRequest) Reset() {
 	*x = GetPreferencesRequest{}
-	mi := &file_preferences_proto_msgTypes[2]
+	mi := &file_preferences_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -178,7 +476,7 @@ func (x *GetPreferencesRequest) String() string {
 func (*GetPreferencesRequest) ProtoMessage() {}
 
 func (x *GetPreferencesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_preferences_proto_msgTypes[2]
+	mi := &file_preferences_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -191,7 +489,7 @@ func (x *GetPreferencesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPreferencesRequest.ProtoReflect.Descriptor instead.
 func (*GetPreferencesRequest) Descriptor() ([]byte, []int) {
-	return file_preferences_proto_rawDescGZIP(), []int{2}
+	return file_preferences_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetPreferencesRequest) GetUserId() string {
@@ -206,14 +504,16 @@ type GetPreferencesResponse struct {
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Theme         string                 `protobuf:"bytes,2,opt,name=theme,proto3" json:"theme,omitempty"`
 	Notifications bool                   `protobuf:"varint,3,opt,name=notifications,proto3" json:"notifications,omitempty"`
-	MetadataJson  string                 `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	// Deprecated: Marked as deprecated in preferences.proto.
+	MetadataJson  string       `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	Preferences   *Preferences `protobuf:"bytes,5,opt,name=preferences,proto3" json:"preferences,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetPreferencesResponse) Reset() {
 	*x = GetPreferencesResponse{}
-	mi := &file_preferences_proto_msgTypes[3]
+	mi := &file_preferences_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -225,7 +525,7 @@ func (x *GetPreferencesResponse) String() string {
 func (*GetPreferencesResponse) ProtoMessage() {}
 
 func (x *GetPreferencesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_preferences_proto_msgTypes[3]
+	mi := &file_preferences_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -238,7 +538,7 @@ func (x *GetPreferencesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPreferencesResponse.ProtoReflect.Descriptor instead.
 func (*GetPreferencesResponse) Descriptor() ([]byte, []int) {
-	return file_preferences_proto_rawDescGZIP(), []int{3}
+	return file_preferences_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetPreferencesResponse) GetUserId() string {
@@ -262,6 +562,7 @@ func (x *GetPreferencesResponse) GetNotifications() bool {
 	return false
 }
 
+// Deprecated: Marked as deprecated in preferences.proto.
 func (x *GetPreferencesResponse) GetMetadataJson() string {
 	if x != nil {
 		return x.MetadataJson
@@ -269,31 +570,191 @@ func (x *GetPreferencesResponse) GetMetadataJson() string {
 	return ""
 }
 
+func (x *GetPreferencesResponse) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+type UpdatePreferencesRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Preferences *Preferences           `protobuf:"bytes,2,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	// paths are Preferences field names; a named field left unset in
+	// preferences is reset to its default
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePreferencesRequest) Reset() {
+	*x = UpdatePreferencesRequest{}
+	mi := &file_preferences_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePreferencesRequest) ProtoMessage() {}
+
+func (x *UpdatePreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePreferencesRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdatePreferencesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdatePreferencesRequest) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+func (x *UpdatePreferencesRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type UpdatePreferencesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Preferences   *Preferences           `protobuf:"bytes,2,opt,name=preferences,proto3" json:"preferences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePreferencesResponse) Reset() {
+	*x = UpdatePreferencesResponse{}
+	mi := &file_preferences_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePreferencesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePreferencesResponse) ProtoMessage() {}
+
+func (x *UpdatePreferencesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_preferences_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePreferencesResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePreferencesResponse) Descriptor() ([]byte, []int) {
+	return file_preferences_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdatePreferencesResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdatePreferencesResponse) GetPreferences() *Preferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
 var File_preferences_proto protoreflect.FileDescriptor
 
 const file_preferences_proto_rawDesc = "" +
 	"\n" +
-	"\x11preferences.proto\x12\x0fguiltmachine.v1\"\x94\x01\n" +
+	"\x11preferences.proto\x12\x0fguiltmachine.v1\x1a google/protobuf/field_mask.proto\"3\n" +
+	"\tTimeRange\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\"\x9c\x03\n" +
+	"\vPreferences\x12\x14\n" +
+	"\x05theme\x18\x01 \x01(\tR\x05theme\x12$\n" +
+	"\rnotifications\x18\x02 \x01(\bR\rnotifications\x122\n" +
+	"\apersona\x18\x03 \x01(\x0e2\x18.guiltmachine.v1.PersonaR\apersona\x12'\n" +
+	"\x0fhumor_intensity\x18\x04 \x01(\x05R\x0ehumorIntensity\x12\x1a\n" +
+	"\btimezone\x18\x05 \x01(\tR\btimezone\x129\n" +
+	"\n" +
+	"work_hours\x18\x06 \x01(\v2\x1a.guiltmachine.v1.TimeRangeR\tworkHours\x12;\n" +
+	"\vquiet_hours\x18\a \x01(\v2\x1a.guiltmachine.v1.TimeRangeR\n" +
+	"quietHours\x12\x1a\n" +
+	"\blanguage\x18\b \x01(\tR\blanguage\x12D\n" +
+	"\x0enudge_channels\x18\t \x03(\x0e2\x1d.guiltmachine.v1.NudgeChannelR\rnudgeChannels\"\xd8\x01\n" +
 	"\x18UpsertPreferencesRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05theme\x18\x02 \x01(\tR\x05theme\x12$\n" +
-	"\rnotifications\x18\x03 \x01(\bR\rnotifications\x12#\n" +
-	"\rmetadata_json\x18\x04 \x01(\tR\fmetadataJson\"\x95\x01\n" +
+	"\rnotifications\x18\x03 \x01(\bR\rnotifications\x12'\n" +
+	"\rmetadata_json\x18\x04 \x01(\tB\x02\x18\x01R\fmetadataJson\x12>\n" +
+	"\vpreferences\x18\x05 \x01(\v2\x1c.guiltmachine.v1.PreferencesR\vpreferences\"\xd9\x01\n" +
 	"\x19UpsertPreferencesResponse\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05theme\x18\x02 \x01(\tR\x05theme\x12$\n" +
-	"\rnotifications\x18\x03 \x01(\bR\rnotifications\x12#\n" +
-	"\rmetadata_json\x18\x04 \x01(\tR\fmetadataJson\"0\n" +
+	"\rnotifications\x18\x03 \x01(\bR\rnotifications\x12'\n" +
+	"\rmetadata_json\x18\x04 \x01(\tB\x02\x18\x01R\fmetadataJson\x12>\n" +
+	"\vpreferences\x18\x05 \x01(\v2\x1c.guiltmachine.v1.PreferencesR\vpreferences\"0\n" +
 	"\x15GetPreferencesRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x92\x01\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xd6\x01\n" +
 	"\x16GetPreferencesResponse\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05theme\x18\x02 \x01(\tR\x05theme\x12$\n" +
-	"\rnotifications\x18\x03 \x01(\bR\rnotifications\x12#\n" +
-	"\rmetadata_json\x18\x04 \x01(\tR\fmetadataJson2\xe3\x01\n" +
+	"\rnotifications\x18\x03 \x01(\bR\rnotifications\x12'\n" +
+	"\rmetadata_json\x18\x04 \x01(\tB\x02\x18\x01R\fmetadataJson\x12>\n" +
+	"\vpreferences\x18\x05 \x01(\v2\x1c.guiltmachine.v1.PreferencesR\vpreferences\"\xb0\x01\n" +
+	"\x18UpdatePreferencesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12>\n" +
+	"\vpreferences\x18\x02 \x01(\v2\x1c.guiltmachine.v1.PreferencesR\vpreferences\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"t\n" +
+	"\x19UpdatePreferencesResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12>\n" +
+	"\vpreferences\x18\x02 \x01(\v2\x1c.guiltmachine.v1.PreferencesR\vpreferences*p\n" +
+	"\aPersona\x12\x17\n" +
+	"\x13PERSONA_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fPERSONA_NEUTRAL\x10\x01\x12\x11\n" +
+	"\rPERSONA_ROAST\x10\x02\x12\x11\n" +
+	"\rPERSONA_COACH\x10\x03\x12\x11\n" +
+	"\rPERSONA_CHILL\x10\x04*\x8f\x01\n" +
+	"\fNudgeChannel\x12\x1d\n" +
+	"\x19NUDGE_CHANNEL_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12NUDGE_CHANNEL_PUSH\x10\x01\x12\x17\n" +
+	"\x13NUDGE_CHANNEL_EMAIL\x10\x02\x12\x15\n" +
+	"\x11NUDGE_CHANNEL_SMS\x10\x03\x12\x18\n" +
+	"\x14NUDGE_CHANNEL_IN_APP\x10\x042\xcf\x02\n" +
 	"\x12PreferencesService\x12j\n" +
 	"\x11UpsertPreferences\x12).guiltmachine.v1.UpsertPreferencesRequest\x1a*.guiltmachine.v1.UpsertPreferencesResponse\x12a\n" +
-	"\x0eGetPreferences\x12&.guiltmachine.v1.GetPreferencesRequest\x1a'.guiltmachine.v1.GetPreferencesResponseB/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
+	"\x0eGetPreferences\x12&.guiltmachine.v1.GetPreferencesRequest\x1a'.guiltmachine.v1.GetPreferencesResponse\x12j\n" +
+	"\x11UpdatePreferences\x12).guiltmachine.v1.UpdatePreferencesRequest\x1a*.guiltmachine.v1.UpdatePreferencesResponseB/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
 
 var (
 	file_preferences_proto_rawDescOnce sync.Once
@@ -307,23 +768,43 @@ func file_preferences_proto_rawDescGZIP() []byte {
 	return file_preferences_proto_rawDescData
 }
 
-var file_preferences_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_preferences_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_preferences_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_preferences_proto_goTypes = []any{
-	(*UpsertPreferencesRequest)(nil),  // 0: guiltmachine.v1.UpsertPreferencesRequest
-	(*UpsertPreferencesResponse)(nil), // 1: guiltmachine.v1.UpsertPreferencesResponse
-	(*GetPreferencesRequest)(nil),     // 2: guiltmachine.v1.GetPreferencesRequest
-	(*GetPreferencesResponse)(nil),    // 3: guiltmachine.v1.GetPreferencesResponse
+	(Persona)(0),                      // 0: guiltmachine.v1.Persona
+	(NudgeChannel)(0),                 // 1: guiltmachine.v1.NudgeChannel
+	(*TimeRange)(nil),                 // 2: guiltmachine.v1.TimeRange
+	(*Preferences)(nil),               // 3: guiltmachine.v1.Preferences
+	(*UpsertPreferencesRequest)(nil),  // 4: guiltmachine.v1.UpsertPreferencesRequest
+	(*UpsertPreferencesResponse)(nil), // 5: guiltmachine.v1.UpsertPreferencesResponse
+	(*GetPreferencesRequest)(nil),     // 6: guiltmachine.v1.GetPreferencesRequest
+	(*GetPreferencesResponse)(nil),    // 7: guiltmachine.v1.GetPreferencesResponse
+	(*UpdatePreferencesRequest)(nil),  // 8: guiltmachine.v1.UpdatePreferencesRequest
+	(*UpdatePreferencesResponse)(nil), // 9: guiltmachine.v1.UpdatePreferencesResponse
+	(*fieldmaskpb.FieldMask)(nil),     // 10: google.protobuf.FieldMask
 }
 var file_preferences_proto_depIdxs = []int32{
-	0, // 0: guiltmachine.v1.PreferencesService.UpsertPreferences:input_type -> guiltmachine.v1.UpsertPreferencesRequest
-	2, // 1: guiltmachine.v1.PreferencesService.GetPreferences:input_type -> guiltmachine.v1.GetPreferencesRequest
-	1, // 2: guiltmachine.v1.PreferencesService.UpsertPreferences:output_type -> guiltmachine.v1.UpsertPreferencesResponse
-	3, // 3: guiltmachine.v1.PreferencesService.GetPreferences:output_type -> guiltmachine.v1.GetPreferencesResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: guiltmachine.v1.Preferences.persona:type_name -> guiltmachine.v1.Persona
+	2,  // 1: guiltmachine.v1.Preferences.work_hours:type_name -> guiltmachine.v1.TimeRange
+	2,  // 2: guiltmachine.v1.Preferences.quiet_hours:type_name -> guiltmachine.v1.TimeRange
+	1,  // 3: guiltmachine.v1.Preferences.nudge_channels:type_name -> guiltmachine.v1.NudgeChannel
+	3,  // 4: guiltmachine.v1.UpsertPreferencesRequest.preferences:type_name -> guiltmachine.v1.Preferences
+	3,  // 5: guiltmachine.v1.UpsertPreferencesResponse.preferences:type_name -> guiltmachine.v1.Preferences
+	3,  // 6: guiltmachine.v1.GetPreferencesResponse.preferences:type_name -> guiltmachine.v1.Preferences
+	3,  // 7: guiltmachine.v1.UpdatePreferencesRequest.preferences:type_name -> guiltmachine.v1.Preferences
+	10, // 8: guiltmachine.v1.UpdatePreferencesRequest.update_mask:type_name -> google.protobuf.FieldMask
+	3,  // 9: guiltmachine.v1.UpdatePreferencesResponse.preferences:type_name -> guiltmachine.v1.Preferences
+	4,  // 10: guiltmachine.v1.PreferencesService.UpsertPreferences:input_type -> guiltmachine.v1.UpsertPreferencesRequest
+	6,  // 11: guiltmachine.v1.PreferencesService.GetPreferences:input_type -> guiltmachine.v1.GetPreferencesRequest
+	8,  // 12: guiltmachine.v1.PreferencesService.UpdatePreferences:input_type -> guiltmachine.v1.UpdatePreferencesRequest
+	5,  // 13: guiltmachine.v1.PreferencesService.UpsertPreferences:output_type -> guiltmachine.v1.UpsertPreferencesResponse
+	7,  // 14: guiltmachine.v1.PreferencesService.GetPreferences:output_type -> guiltmachine.v1.GetPreferencesResponse
+	9,  // 15: guiltmachine.v1.PreferencesService.UpdatePreferences:output_type -> guiltmachine.v1.UpdatePreferencesResponse
+	13, // [13:16] is the sub-list for method output_type
+	10, // [10:13] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_preferences_proto_init() }
@@ -336,13 +817,14 @@ func file_preferences_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_preferences_proto_rawDesc), len(file_preferences_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      2,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_preferences_proto_goTypes,
 		DependencyIndexes: file_preferences_proto_depIdxs,
+		EnumInfos:         file_preferences_proto_enumTypes,
 		MessageInfos:      file_preferences_proto_msgTypes,
 	}.Build()
 	File_preferences_proto = out.File
@@ -21,6 +21,7 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	PreferencesService_UpsertPreferences_FullMethodName = "/guiltmachine.v1.PreferencesService/UpsertPreferences"
 	PreferencesService_GetPreferences_FullMethodName    = "/guiltmachine.v1.PreferencesService/GetPreferences"
+	PreferencesService_UpdatePreferences_FullMethodName = "/guiltmachine.v1.PreferencesService/UpdatePreferences"
 )
 
 // PreferencesServiceClient is the client API for PreferencesService service.
@@ -29,6 +30,8 @@ const (
 type PreferencesServiceClient interface {
 	UpsertPreferences(ctx context.Context, in *UpsertPreferencesRequest, opts ...grpc.CallOption) (*UpsertPreferencesResponse, error)
 	GetPreferences(ctx context.Context, in *GetPreferencesRequest, opts ...grpc.CallOption) (*GetPreferencesResponse, error)
+	// UpdatePreferences changes only the fields named in update_mask
+	UpdatePreferences(ctx context.Context, in *UpdatePreferencesRequest, opts ...grpc.CallOption) (*UpdatePreferencesResponse, error)
 }
 
 type preferencesServiceClient struct {
@@ -59,12 +62,24 @@ func (c *preferencesServiceClient) GetPreferences(ctx context.Context, in *GetPr
 	return out, nil
 }
 
+func (c *preferencesServiceClient) UpdatePreferences(ctx context.Context, in *UpdatePreferencesRequest, opts ...grpc.CallOption) (*UpdatePreferencesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdatePreferencesResponse)
+	err := c.cc.Invoke(ctx, PreferencesService_UpdatePreferences_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PreferencesServiceServer is the server API for PreferencesService service.
 // All implementations must embed UnimplementedPreferencesServiceServer
 // for forward compatibility.
 type PreferencesServiceServer interface {
 	UpsertPreferences(context.Context, *UpsertPreferencesRequest) (*UpsertPreferencesResponse, error)
 	GetPreferences(context.Context, *GetPreferencesRequest) (*GetPreferencesResponse, error)
+	// UpdatePreferences changes only the fields named in update_mask
+	UpdatePreferences(context.Context, *UpdatePreferencesRequest) (*UpdatePreferencesResponse, error)
 	mustEmbedUnimplementedPreferencesServiceServer()
 }
 
@@ -81,6 +96,9 @@ func (UnimplementedPreferencesServiceServer) UpsertPreferences(context.Context,
 func (UnimplementedPreferencesServiceServer) GetPreferences(context.Context, *GetPreferencesRequest) (*GetPreferencesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetPreferences not implemented")
 }
+func (UnimplementedPreferencesServiceServer) UpdatePreferences(context.Context, *UpdatePreferencesRequest) (*UpdatePreferencesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdatePreferences not implemented")
+}
 func (UnimplementedPreferencesServiceServer) mustEmbedUnimplementedPreferencesServiceServer() {}
 func (UnimplementedPreferencesServiceServer) testEmbeddedByValue()                            {}
 
@@ -138,6 +156,24 @@ func _PreferencesService_GetPreferences_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PreferencesService_UpdatePreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePreferencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PreferencesServiceServer).UpdatePreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PreferencesService_UpdatePreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PreferencesServiceServer).UpdatePreferences(ctx, req.(*UpdatePreferencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PreferencesService_ServiceDesc is the grpc.ServiceDesc for PreferencesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +189,10 @@ var PreferencesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPreferences",
 			Handler:    _PreferencesService_GetPreferences_Handler,
 		},
+		{
+			MethodName: "UpdatePreferences",
+			Handler:    _PreferencesService_UpdatePreferences_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "preferences.proto",
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.11
+// 	protoc-gen-go v1.36.10
 // 	protoc        v6.33.4
 // source: user.proto
 
@@ -25,7 +25,7 @@ const (
 type CreateUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	PasswordHash  string                 `protobuf:"bytes,2,opt,name=password_hash,json=passwordHash,proto3" json:"password_hash,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"` // plaintext, hashed by the server
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -67,9 +67,9 @@ func (x *CreateUserRequest) GetEmail() string {
 	return ""
 }
 
-func (x *CreateUserRequest) GetPasswordHash() string {
+func (x *CreateUserRequest) GetPassword() string {
 	if x != nil {
-		return x.PasswordHash
+		return x.Password
 	}
 	return ""
 }
@@ -246,15 +246,135 @@ func (x *GetUserResponse) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+type LoginRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_user_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type LoginResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Jwt           string                 `protobuf:"bytes,3,opt,name=jwt,proto3" json:"jwt,omitempty"`                                       // JWT token for authentication
+	RefreshToken  string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // single-use token for SessionService.RefreshToken
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_user_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_user_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LoginResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetJwt() string {
+	if x != nil {
+		return x.Jwt
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
 var File_user_proto protoreflect.FileDescriptor
 
 const file_user_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"user.proto\x12\x0fguiltmachine.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"N\n" +
+	"user.proto\x12\x0fguiltmachine.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"E\n" +
 	"\x11CreateUserRequest\x12\x14\n" +
-	"\x05email\x18\x01 \x01(\tR\x05email\x12#\n" +
-	"\rpassword_hash\x18\x02 \x01(\tR\fpasswordHash\"u\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"u\n" +
 	"\x12CreateUserResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x129\n" +
@@ -268,11 +388,21 @@ const file_user_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt2\xb2\x01\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"@\n" +
+	"\fLoginRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"~\n" +
+	"\rLoginResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x10\n" +
+	"\x03jwt\x18\x03 \x01(\tR\x03jwt\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken2\xfa\x01\n" +
 	"\vUserService\x12U\n" +
 	"\n" +
 	"CreateUser\x12\".guiltmachine.v1.CreateUserRequest\x1a#.guiltmachine.v1.CreateUserResponse\x12L\n" +
-	"\aGetUser\x12\x1f.guiltmachine.v1.GetUserRequest\x1a .guiltmachine.v1.GetUserResponseB/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
+	"\aGetUser\x12\x1f.guiltmachine.v1.GetUserRequest\x1a .guiltmachine.v1.GetUserResponse\x12F\n" +
+	"\x05Login\x12\x1d.guiltmachine.v1.LoginRequest\x1a\x1e.guiltmachine.v1.LoginResponseB/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
 
 var (
 	file_user_proto_rawDescOnce sync.Once
@@ -286,24 +416,28 @@ func file_user_proto_rawDescGZIP() []byte {
 	return file_user_proto_rawDescData
 }
 
-var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_user_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_user_proto_goTypes = []any{
 	(*CreateUserRequest)(nil),     // 0: guiltmachine.v1.CreateUserRequest
 	(*CreateUserResponse)(nil),    // 1: guiltmachine.v1.CreateUserResponse
 	(*GetUserRequest)(nil),        // 2: guiltmachine.v1.GetUserRequest
 	(*GetUserResponse)(nil),       // 3: guiltmachine.v1.GetUserResponse
-	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*LoginRequest)(nil),          // 4: guiltmachine.v1.LoginRequest
+	(*LoginResponse)(nil),         // 5: guiltmachine.v1.LoginResponse
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
 }
 var file_user_proto_depIdxs = []int32{
-	4, // 0: guiltmachine.v1.CreateUserResponse.created_at:type_name -> google.protobuf.Timestamp
-	4, // 1: guiltmachine.v1.GetUserResponse.created_at:type_name -> google.protobuf.Timestamp
-	4, // 2: guiltmachine.v1.GetUserResponse.updated_at:type_name -> google.protobuf.Timestamp
+	6, // 0: guiltmachine.v1.CreateUserResponse.created_at:type_name -> google.protobuf.Timestamp
+	6, // 1: guiltmachine.v1.GetUserResponse.created_at:type_name -> google.protobuf.Timestamp
+	6, // 2: guiltmachine.v1.GetUserResponse.updated_at:type_name -> google.protobuf.Timestamp
 	0, // 3: guiltmachine.v1.UserService.CreateUser:input_type -> guiltmachine.v1.CreateUserRequest
 	2, // 4: guiltmachine.v1.UserService.GetUser:input_type -> guiltmachine.v1.GetUserRequest
-	1, // 5: guiltmachine.v1.UserService.CreateUser:output_type -> guiltmachine.v1.CreateUserResponse
-	3, // 6: guiltmachine.v1.UserService.GetUser:output_type -> guiltmachine.v1.GetUserResponse
-	5, // [5:7] is the sub-list for method output_type
-	3, // [3:5] is the sub-list for method input_type
+	4, // 5: guiltmachine.v1.UserService.Login:input_type -> guiltmachine.v1.LoginRequest
+	1, // 6: guiltmachine.v1.UserService.CreateUser:output_type -> guiltmachine.v1.CreateUserResponse
+	3, // 7: guiltmachine.v1.UserService.GetUser:output_type -> guiltmachine.v1.GetUserResponse
+	5, // 8: guiltmachine.v1.UserService.Login:output_type -> guiltmachine.v1.LoginResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
 	3, // [3:3] is the sub-list for extension type_name
 	3, // [3:3] is the sub-list for extension extendee
 	0, // [0:3] is the sub-list for field type_name
@@ -320,7 +454,7 @@ func file_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_proto_rawDesc), len(file_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1,207 +0,0 @@
-// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
-// versions:
-// - protoc-gen-go-grpc v1.6.0
-// - protoc             v6.33.4
-// source: internal/proto/entry.proto
-
-package v1
-
-import (
-	context "context"
-	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
-)
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.64.0 or later.
-const _ = grpc.SupportPackageIsVersion9
-
-const (
-	EntryService_CreateEntry_FullMethodName = "/guiltmachine.v1.EntryService/CreateEntry"
-	EntryService_ListEntries_FullMethodName = "/guiltmachine.v1.EntryService/ListEntries"
-	EntryService_GetEntry_FullMethodName    = "/guiltmachine.v1.EntryService/GetEntry"
-)
-
-// EntryServiceClient is the client API for EntryService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-//
-// EntryService manages guilt entries
-type EntryServiceClient interface {
-	// CreateEntry creates a new guilt entry
-	CreateEntry(ctx context.Context, in *CreateEntryRequest, opts ...grpc.CallOption) (*CreateEntryResponse, error)
-	// ListEntries lists all guilt entries for a session
-	ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error)
-	// GetEntry retrieves a single entry with score and roast
-	GetEntry(ctx context.Context, in *GetEntryRequest, opts ...grpc.CallOption) (*GetEntryResponse, error)
-}
-
-type entryServiceClient struct {
-	cc grpc.ClientConnInterface
-}
-
-func NewEntryServiceClient(cc grpc.ClientConnInterface) EntryServiceClient {
-	return &entryServiceClient{cc}
-}
-
-func (c *entryServiceClient) CreateEntry(ctx context.Context, in *CreateEntryRequest, opts ...grpc.CallOption) (*CreateEntryResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(CreateEntryResponse)
-	err := c.cc.Invoke(ctx, EntryService_CreateEntry_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *entryServiceClient) ListEntries(ctx context.Context, in *ListEntriesRequest, opts ...grpc.CallOption) (*ListEntriesResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListEntriesResponse)
-	err := c.cc.Invoke(ctx, EntryService_ListEntries_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *entryServiceClient) GetEntry(ctx context.Context, in *GetEntryRequest, opts ...grpc.CallOption) (*GetEntryResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(GetEntryResponse)
-	err := c.cc.Invoke(ctx, EntryService_GetEntry_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-// EntryServiceServer is the server API for EntryService service.
-// All implementations must embed UnimplementedEntryServiceServer
-// for forward compatibility.
-//
-// EntryService manages guilt entries
-type EntryServiceServer interface {
-	// CreateEntry creates a new guilt entry
-	CreateEntry(context.Context, *CreateEntryRequest) (*CreateEntryResponse, error)
-	// ListEntries lists all guilt entries for a session
-	ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error)
-	// GetEntry retrieves a single entry with score and roast
-	GetEntry(context.Context, *GetEntryRequest) (*GetEntryResponse, error)
-	mustEmbedUnimplementedEntryServiceServer()
-}
-
-// UnimplementedEntryServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedEntryServiceServer struct{}
-
-func (UnimplementedEntryServiceServer) CreateEntry(context.Context, *CreateEntryRequest) (*CreateEntryResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method CreateEntry not implemented")
-}
-func (UnimplementedEntryServiceServer) ListEntries(context.Context, *ListEntriesRequest) (*ListEntriesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method ListEntries not implemented")
-}
-func (UnimplementedEntryServiceServer) GetEntry(context.Context, *GetEntryRequest) (*GetEntryResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "method GetEntry not implemented")
-}
-func (UnimplementedEntryServiceServer) mustEmbedUnimplementedEntryServiceServer() {}
-func (UnimplementedEntryServiceServer) testEmbeddedByValue()                      {}
-
-// UnsafeEntryServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to EntryServiceServer will
-// result in compilation errors.
-type UnsafeEntryServiceServer interface {
-	mustEmbedUnimplementedEntryServiceServer()
-}
-
-func RegisterEntryServiceServer(s grpc.ServiceRegistrar, srv EntryServiceServer) {
-	// If the following call panics, it indicates UnimplementedEntryServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
-	}
-	s.RegisterService(&EntryService_ServiceDesc, srv)
-}
-
-func _EntryService_CreateEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateEntryRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(EntryServiceServer).CreateEntry(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: EntryService_CreateEntry_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EntryServiceServer).CreateEntry(ctx, req.(*CreateEntryRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _EntryService_ListEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListEntriesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(EntryServiceServer).ListEntries(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: EntryService_ListEntries_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EntryServiceServer).ListEntries(ctx, req.(*ListEntriesRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _EntryService_GetEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetEntryRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(EntryServiceServer).GetEntry(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: EntryService_GetEntry_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(EntryServiceServer).GetEntry(ctx, req.(*GetEntryRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-// EntryService_ServiceDesc is the grpc.ServiceDesc for EntryService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var EntryService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "guiltmachine.v1.EntryService",
-	HandlerType: (*EntryServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateEntry",
-			Handler:    _EntryService_CreateEntry_Handler,
-		},
-		{
-			MethodName: "ListEntries",
-			Handler:    _EntryService_ListEntries_Handler,
-		},
-		{
-			MethodName: "GetEntry",
-			Handler:    _EntryService_GetEntry_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "internal/proto/entry.proto",
-}
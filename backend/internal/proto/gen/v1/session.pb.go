@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.11
+// 	protoc-gen-go v1.36.10
 // 	protoc        v6.33.4
 // source: internal/proto/session.proto
 
@@ -80,7 +80,8 @@ type CreateSessionResponse struct {
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Notes         string                 `protobuf:"bytes,3,opt,name=notes,proto3" json:"notes,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	Jwt           string                 `protobuf:"bytes,5,opt,name=jwt,proto3" json:"jwt,omitempty"` // JWT token for authentication
+	Jwt           string                 `protobuf:"bytes,5,opt,name=jwt,proto3" json:"jwt,omitempty"`                                       // JWT token for authentication
+	RefreshToken  string                 `protobuf:"bytes,6,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // single-use token for RefreshToken
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -150,6 +151,13 @@ func (x *CreateSessionResponse) GetJwt() string {
 	return ""
 }
 
+func (x *CreateSessionResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
 type EndSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -570,6 +578,110 @@ func (x *SessionItem) GetEndedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	mi := &file_internal_proto_session_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_session_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_internal_proto_session_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type RefreshTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jwt           string                 `protobuf:"bytes,1,opt,name=jwt,proto3" json:"jwt,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // access token expiry
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenResponse) Reset() {
+	*x = RefreshTokenResponse{}
+	mi := &file_internal_proto_session_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenResponse) ProtoMessage() {}
+
+func (x *RefreshTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_proto_session_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenResponse.ProtoReflect.Descriptor instead.
+func (*RefreshTokenResponse) Descriptor() ([]byte, []int) {
+	return file_internal_proto_session_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RefreshTokenResponse) GetJwt() string {
+	if x != nil {
+		return x.Jwt
+	}
+	return ""
+}
+
+func (x *RefreshTokenResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *RefreshTokenResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 var File_internal_proto_session_proto protoreflect.FileDescriptor
 
 const file_internal_proto_session_proto_rawDesc = "" +
@@ -577,14 +689,15 @@ const file_internal_proto_session_proto_rawDesc = "" +
 	"\x1cinternal/proto/session.proto\x12\x0fguiltmachine.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"E\n" +
 	"\x14CreateSessionRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
-	"\x05notes\x18\x02 \x01(\tR\x05notes\"\xa3\x01\n" +
+	"\x05notes\x18\x02 \x01(\tR\x05notes\"\xc8\x01\n" +
 	"\x15CreateSessionResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05notes\x18\x03 \x01(\tR\x05notes\x129\n" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x10\n" +
-	"\x03jwt\x18\x05 \x01(\tR\x03jwt\"#\n" +
+	"\x03jwt\x18\x05 \x01(\tR\x03jwt\x12#\n" +
+	"\rrefresh_token\x18\x06 \x01(\tR\frefreshToken\"#\n" +
 	"\x11EndSessionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"\xc5\x01\n" +
 	"\x12EndSessionResponse\x12\x0e\n" +
@@ -615,14 +728,22 @@ const file_internal_proto_session_proto_rawDesc = "" +
 	"\x05notes\x18\x03 \x01(\tR\x05notes\x129\n" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x125\n" +
-	"\bended_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt2\x8d\x03\n" +
+	"\bended_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\x88\x01\n" +
+	"\x14RefreshTokenResponse\x12\x10\n" +
+	"\x03jwt\x18\x01 \x01(\tR\x03jwt\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt2\xea\x03\n" +
 	"\x0eSessionService\x12^\n" +
 	"\rCreateSession\x12%.guiltmachine.v1.CreateSessionRequest\x1a&.guiltmachine.v1.CreateSessionResponse\x12U\n" +
 	"\n" +
 	"EndSession\x12\".guiltmachine.v1.EndSessionRequest\x1a#.guiltmachine.v1.EndSessionResponse\x12U\n" +
 	"\n" +
 	"GetSession\x12\".guiltmachine.v1.GetSessionRequest\x1a#.guiltmachine.v1.GetSessionResponse\x12m\n" +
-	"\x12ListSessionsByUser\x12*.guiltmachine.v1.ListSessionsByUserRequest\x1a+.guiltmachine.v1.ListSessionsByUserResponseB/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
+	"\x12ListSessionsByUser\x12*.guiltmachine.v1.ListSessionsByUserRequest\x1a+.guiltmachine.v1.ListSessionsByUserResponse\x12[\n" +
+	"\fRefreshToken\x12$.guiltmachine.v1.RefreshTokenRequest\x1a%.guiltmachine.v1.RefreshTokenResponseB/Z-guiltmachine/backend/internal/proto/gen/v1;v1b\x06proto3"
 
 var (
 	file_internal_proto_session_proto_rawDescOnce sync.Once
@@ -636,7 +757,7 @@ func file_internal_proto_session_proto_rawDescGZIP() []byte {
 	return file_internal_proto_session_proto_rawDescData
 }
 
-var file_internal_proto_session_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_internal_proto_session_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_internal_proto_session_proto_goTypes = []any{
 	(*CreateSessionRequest)(nil),       // 0: guiltmachine.v1.CreateSessionRequest
 	(*CreateSessionResponse)(nil),      // 1: guiltmachine.v1.CreateSessionResponse
@@ -647,30 +768,35 @@ var file_internal_proto_session_proto_goTypes = []any{
 	(*ListSessionsByUserRequest)(nil),  // 6: guiltmachine.v1.ListSessionsByUserRequest
 	(*ListSessionsByUserResponse)(nil), // 7: guiltmachine.v1.ListSessionsByUserResponse
 	(*SessionItem)(nil),                // 8: guiltmachine.v1.SessionItem
-	(*timestamppb.Timestamp)(nil),      // 9: google.protobuf.Timestamp
+	(*RefreshTokenRequest)(nil),        // 9: guiltmachine.v1.RefreshTokenRequest
+	(*RefreshTokenResponse)(nil),       // 10: guiltmachine.v1.RefreshTokenResponse
+	(*timestamppb.Timestamp)(nil),      // 11: google.protobuf.Timestamp
 }
 var file_internal_proto_session_proto_depIdxs = []int32{
-	9,  // 0: guiltmachine.v1.CreateSessionResponse.created_at:type_name -> google.protobuf.Timestamp
-	9,  // 1: guiltmachine.v1.EndSessionResponse.created_at:type_name -> google.protobuf.Timestamp
-	9,  // 2: guiltmachine.v1.EndSessionResponse.ended_at:type_name -> google.protobuf.Timestamp
-	9,  // 3: guiltmachine.v1.GetSessionResponse.created_at:type_name -> google.protobuf.Timestamp
-	9,  // 4: guiltmachine.v1.GetSessionResponse.ended_at:type_name -> google.protobuf.Timestamp
+	11, // 0: guiltmachine.v1.CreateSessionResponse.created_at:type_name -> google.protobuf.Timestamp
+	11, // 1: guiltmachine.v1.EndSessionResponse.created_at:type_name -> google.protobuf.Timestamp
+	11, // 2: guiltmachine.v1.EndSessionResponse.ended_at:type_name -> google.protobuf.Timestamp
+	11, // 3: guiltmachine.v1.GetSessionResponse.created_at:type_name -> google.protobuf.Timestamp
+	11, // 4: guiltmachine.v1.GetSessionResponse.ended_at:type_name -> google.protobuf.Timestamp
 	8,  // 5: guiltmachine.v1.ListSessionsByUserResponse.sessions:type_name -> guiltmachine.v1.SessionItem
-	9,  // 6: guiltmachine.v1.SessionItem.created_at:type_name -> google.protobuf.Timestamp
-	9,  // 7: guiltmachine.v1.SessionItem.ended_at:type_name -> google.protobuf.Timestamp
-	0,  // 8: guiltmachine.v1.SessionService.CreateSession:input_type -> guiltmachine.v1.CreateSessionRequest
-	2,  // 9: guiltmachine.v1.SessionService.EndSession:input_type -> guiltmachine.v1.EndSessionRequest
-	4,  // 10: guiltmachine.v1.SessionService.GetSession:input_type -> guiltmachine.v1.GetSessionRequest
-	6,  // 11: guiltmachine.v1.SessionService.ListSessionsByUser:input_type -> guiltmachine.v1.ListSessionsByUserRequest
-	1,  // 12: guiltmachine.v1.SessionService.CreateSession:output_type -> guiltmachine.v1.CreateSessionResponse
-	3,  // 13: guiltmachine.v1.SessionService.EndSession:output_type -> guiltmachine.v1.EndSessionResponse
-	5,  // 14: guiltmachine.v1.SessionService.GetSession:output_type -> guiltmachine.v1.GetSessionResponse
-	7,  // 15: guiltmachine.v1.SessionService.ListSessionsByUser:output_type -> guiltmachine.v1.ListSessionsByUserResponse
-	12, // [12:16] is the sub-list for method output_type
-	8,  // [8:12] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	11, // 6: guiltmachine.v1.SessionItem.created_at:type_name -> google.protobuf.Timestamp
+	11, // 7: guiltmachine.v1.SessionItem.ended_at:type_name -> google.protobuf.Timestamp
+	11, // 8: guiltmachine.v1.RefreshTokenResponse.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 9: guiltmachine.v1.SessionService.CreateSession:input_type -> guiltmachine.v1.CreateSessionRequest
+	2,  // 10: guiltmachine.v1.SessionService.EndSession:input_type -> guiltmachine.v1.EndSessionRequest
+	4,  // 11: guiltmachine.v1.SessionService.GetSession:input_type -> guiltmachine.v1.GetSessionRequest
+	6,  // 12: guiltmachine.v1.SessionService.ListSessionsByUser:input_type -> guiltmachine.v1.ListSessionsByUserRequest
+	9,  // 13: guiltmachine.v1.SessionService.RefreshToken:input_type -> guiltmachine.v1.RefreshTokenRequest
+	1,  // 14: guiltmachine.v1.SessionService.CreateSession:output_type -> guiltmachine.v1.CreateSessionResponse
+	3,  // 15: guiltmachine.v1.SessionService.EndSession:output_type -> guiltmachine.v1.EndSessionResponse
+	5,  // 16: guiltmachine.v1.SessionService.GetSession:output_type -> guiltmachine.v1.GetSessionResponse
+	7,  // 17: guiltmachine.v1.SessionService.ListSessionsByUser:output_type -> guiltmachine.v1.ListSessionsByUserResponse
+	10, // 18: guiltmachine.v1.SessionService.RefreshToken:output_type -> guiltmachine.v1.RefreshTokenResponse
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_internal_proto_session_proto_init() }
@@ -684,7 +810,7 @@ func file_internal_proto_session_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_proto_session_proto_rawDesc), len(file_internal_proto_session_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
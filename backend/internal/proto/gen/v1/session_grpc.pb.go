@@ -23,6 +23,7 @@ const (
 	SessionService_EndSession_FullMethodName         = "/guiltmachine.v1.SessionService/EndSession"
 	SessionService_GetSession_FullMethodName         = "/guiltmachine.v1.SessionService/GetSession"
 	SessionService_ListSessionsByUser_FullMethodName = "/guiltmachine.v1.SessionService/ListSessionsByUser"
+	SessionService_RefreshToken_FullMethodName       = "/guiltmachine.v1.SessionService/RefreshToken"
 )
 
 // SessionServiceClient is the client API for SessionService service.
@@ -33,6 +34,7 @@ type SessionServiceClient interface {
 	EndSession(ctx context.Context, in *EndSessionRequest, opts ...grpc.CallOption) (*EndSessionResponse, error)
 	GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*GetSessionResponse, error)
 	ListSessionsByUser(ctx context.Context, in *ListSessionsByUserRequest, opts ...grpc.CallOption) (*ListSessionsByUserResponse, error)
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
 }
 
 type sessionServiceClient struct {
@@ -83,6 +85,16 @@ func (c *sessionServiceClient) ListSessionsByUser(ctx context.Context, in *ListS
 	return out, nil
 }
 
+func (c *sessionServiceClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshTokenResponse)
+	err := c.cc.Invoke(ctx, SessionService_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SessionServiceServer is the server API for SessionService service.
 // All implementations must embed UnimplementedSessionServiceServer
 // for forward compatibility.
@@ -91,6 +103,7 @@ type SessionServiceServer interface {
 	EndSession(context.Context, *EndSessionRequest) (*EndSessionResponse, error)
 	GetSession(context.Context, *GetSessionRequest) (*GetSessionResponse, error)
 	ListSessionsByUser(context.Context, *ListSessionsByUserRequest) (*ListSessionsByUserResponse, error)
+	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
 	mustEmbedUnimplementedSessionServiceServer()
 }
 
@@ -113,6 +126,9 @@ func (UnimplementedSessionServiceServer) GetSession(context.Context, *GetSession
 func (UnimplementedSessionServiceServer) ListSessionsByUser(context.Context, *ListSessionsByUserRequest) (*ListSessionsByUserResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListSessionsByUser not implemented")
 }
+func (UnimplementedSessionServiceServer) RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RefreshToken not implemented")
+}
 func (UnimplementedSessionServiceServer) mustEmbedUnimplementedSessionServiceServer() {}
 func (UnimplementedSessionServiceServer) testEmbeddedByValue()                        {}
 
@@ -206,6 +222,24 @@ func _SessionService_ListSessionsByUser_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SessionService_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SessionServiceServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SessionService_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SessionServiceServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SessionService_ServiceDesc is the grpc.ServiceDesc for SessionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +263,10 @@ var SessionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListSessionsByUser",
 			Handler:    _SessionService_ListSessionsByUser_Handler,
 		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _SessionService_RefreshToken_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/proto/session.proto",
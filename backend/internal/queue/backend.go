@@ -0,0 +1,55 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// Publisher enqueues ML jobs
+type Publisher interface {
+	Publish(ctx context.Context, job EntryMLJob) error
+}
+
+// Backend stores ML jobs between the API that publishes them and the workers
+// that lease them. Implementations: Redis Streams, Postgres and in-memory.
+type Backend interface {
+	Publisher
+	// Source returns a JobSource that leases jobs to the named consumer
+	Source(consumer string) JobSource
+}
+
+// DeadLetterStore inspects and replays jobs that exhausted their retries
+type DeadLetterStore interface {
+	DeadLetters(ctx context.Context, count int64) ([]DeadLetter, error)
+	DeadLetter(ctx context.Context, id string) (DeadLetter, error)
+	Replay(ctx context.Context, id string) error
+	DropDeadLetter(ctx context.Context, id string) error
+}
+
+var (
+	_ Backend         = (*RedisBackend)(nil)
+	_ DeadLetterStore = (*Streams)(nil)
+	_ JobSource       = (*Consumer)(nil)
+)
+
+// RedisBackend serves jobs from a Redis stream through a consumer group
+type RedisBackend struct {
+	streams     *Streams
+	group       string
+	wait        time.Duration
+	reclaimIdle time.Duration
+}
+
+// NewRedisBackend creates a Backend whose consumers block up to wait for new
+// messages and take over messages left pending for reclaimIdle
+func NewRedisBackend(s *Streams, group string, wait, reclaimIdle time.Duration) *RedisBackend {
+	return &RedisBackend{streams: s, group: group, wait: wait, reclaimIdle: reclaimIdle}
+}
+
+func (b *RedisBackend) Publish(ctx context.Context, job EntryMLJob) error {
+	return b.streams.Publish(ctx, job)
+}
+
+func (b *RedisBackend) Source(consumer string) JobSource {
+	return NewConsumerWithReclaim(b.streams, b.group, consumer, b.wait, b.reclaimIdle)
+}
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"log"
 	"time"
 )
 
@@ -10,6 +11,12 @@ type Consumer struct {
 	group   string
 	name    string
 	timeout time.Duration
+
+	// reclaimIdle is how long a message may sit unacknowledged with another
+	// consumer before this one takes it over; zero disables reclaiming
+	reclaimIdle  time.Duration
+	reclaimEvery time.Duration
+	lastReclaim  time.Time
 }
 
 func NewConsumer(s *Streams, group, name string, timeout time.Duration) *Consumer {
@@ -21,6 +28,78 @@ func NewConsumer(s *Streams, group, name string, timeout time.Duration) *Consume
 	}
 }
 
-func (c *Consumer) Poll(ctx context.Context) ([]EntryMLJob, error) {
-	return c.streams.Consume(ctx, c.group, c.name, c.timeout)
+// NewConsumerWithReclaim creates a Consumer that also takes over messages left
+// pending by dead consumers once they have been idle for minIdle. minIdle must
+// be longer than the slowest job, or live workers will have jobs stolen.
+func NewConsumerWithReclaim(s *Streams, group, name string, timeout, minIdle time.Duration) *Consumer {
+	c := NewConsumer(s, group, name, timeout)
+	c.reclaimIdle = minIdle
+	c.reclaimEvery = minIdle / 2
+	if c.reclaimEvery < time.Second {
+		c.reclaimEvery = time.Second
+	}
+	return c
+}
+
+// Name returns the consumer name registered in the group
+func (c *Consumer) Name() string {
+	return c.name
+}
+
+// Poll returns the next delivery, see PollN
+func (c *Consumer) Poll(ctx context.Context) ([]Delivery, error) {
+	return c.PollN(ctx, 1)
+}
+
+// PollN moves due retries back onto the stream and reads up to n deliveries.
+// Abandoned messages reclaimed from dead consumers are returned first.
+func (c *Consumer) PollN(ctx context.Context, n int64) ([]Delivery, error) {
+	if n < 1 {
+		n = 1
+	}
+	if reclaimed, err := c.reclaim(ctx, n); err != nil {
+		return nil, err
+	} else if len(reclaimed) > 0 {
+		return reclaimed, nil
+	}
+
+	if _, err := c.streams.PromoteDue(ctx, time.Now()); err != nil {
+		return nil, err
+	}
+	return c.streams.Consume(ctx, c.group, c.name, n, c.timeout)
+}
+
+func (c *Consumer) reclaim(ctx context.Context, n int64) ([]Delivery, error) {
+	if c.reclaimIdle <= 0 || time.Since(c.lastReclaim) < c.reclaimEvery {
+		return nil, nil
+	}
+
+	deliveries, err := c.streams.Reclaim(ctx, c.group, c.name, c.reclaimIdle, n)
+	if err != nil {
+		return nil, err
+	}
+	// keep draining while there is a backlog, otherwise wait for the next interval
+	if int64(len(deliveries)) < n {
+		c.lastReclaim = time.Now()
+		if removed, err := c.streams.PruneConsumers(ctx, c.group); err != nil {
+			log.Printf("queue: prune consumers failed: %v", err)
+		} else if len(removed) > 0 {
+			log.Printf("queue: removed idle consumers %v", removed)
+		}
+	}
+	if len(deliveries) > 0 {
+		log.Printf("queue: %s reclaimed %d abandoned messages", c.name, len(deliveries))
+	}
+	return deliveries, nil
+}
+
+// Ack acknowledges a successfully processed delivery
+func (c *Consumer) Ack(ctx context.Context, d Delivery) error {
+	return c.streams.Ack(ctx, c.group, d)
+}
+
+// Fail schedules a retry for the delivery or dead-letters it.
+// It reports whether the job was dead-lettered.
+func (c *Consumer) Fail(ctx context.Context, d Delivery, cause error) (bool, error) {
+	return c.streams.Fail(ctx, c.group, d, cause)
 }
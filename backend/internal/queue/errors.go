@@ -3,5 +3,7 @@ package queue
 import "errors"
 
 var (
-	ErrQueueUnavailable = errors.New("queue unavailable")
+	ErrQueueUnavailable   = errors.New("queue unavailable")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrUndecodableJob     = errors.New("dead letter payload cannot be decoded")
 )
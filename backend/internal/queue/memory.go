@@ -0,0 +1,257 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryBackend is an in-process queue for tests and single-binary dev mode.
+// It follows the same lease, retry and dead-letter rules as PostgresBackend;
+// jobs are lost when the process exits.
+type MemoryBackend struct {
+	lease  time.Duration
+	policy RetryPolicy
+
+	mu     sync.Mutex
+	seq    int64
+	jobs   []*memoryJob
+	dead   []DeadLetter
+	notify chan struct{}
+}
+
+type memoryJob struct {
+	id          string
+	job         EntryMLJob
+	deliveries  int
+	runAt       time.Time
+	lockedBy    string
+	lockedUntil time.Time
+}
+
+func NewMemoryBackend(lease time.Duration) *MemoryBackend {
+	return NewMemoryBackendWithPolicy(lease, DefaultRetryPolicy())
+}
+
+func NewMemoryBackendWithPolicy(lease time.Duration, policy RetryPolicy) *MemoryBackend {
+	return &MemoryBackend{lease: lease, policy: policy, notify: make(chan struct{})}
+}
+
+func (b *MemoryBackend) Publish(ctx context.Context, job EntryMLJob) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	b.jobs = append(b.jobs, &memoryJob{id: strconv.FormatInt(b.seq, 10), job: job, runAt: time.Now()})
+	b.wake()
+	return nil
+}
+
+func (b *MemoryBackend) Source(consumer string) JobSource {
+	return &memorySource{backend: b, name: consumer}
+}
+
+// Len returns the number of queued and running jobs
+func (b *MemoryBackend) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.jobs)
+}
+
+// wake releases every consumer waiting for new jobs; b.mu must be held
+func (b *MemoryBackend) wake() {
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+func (b *MemoryBackend) claim(consumer string, n int64, now time.Time) ([]Delivery, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var deliveries []Delivery
+	kept := b.jobs[:0]
+	for _, j := range b.jobs {
+		ready := j.lockedBy == "" && !j.runAt.After(now)
+		abandoned := j.lockedBy != "" && j.lockedUntil.Before(now)
+		if int64(len(deliveries)) >= n || !(ready || abandoned) {
+			kept = append(kept, j)
+			continue
+		}
+		// a job that keeps killing its worker never reaches Fail
+		if b.policy.Exhausted(j.deliveries) {
+			b.bury(j, fmt.Sprintf("abandoned by consumers after %d deliveries", j.deliveries))
+			continue
+		}
+		j.job.Attempts = j.deliveries
+		j.deliveries++
+		j.lockedBy = consumer
+		j.lockedUntil = now.Add(b.lease)
+		deliveries = append(deliveries, Delivery{ID: j.id, Job: j.job})
+		kept = append(kept, j)
+	}
+	b.jobs = kept
+	return deliveries, b.notify
+}
+
+// bury records j as dead; b.mu must be held and j removed from b.jobs by the caller
+func (b *MemoryBackend) bury(j *memoryJob, reason string) {
+	job := j.job
+	job.Attempts = j.deliveries
+	job.LastError = reason
+	raw, _ := json.Marshal(job)
+	b.dead = append(b.dead, DeadLetter{
+		ID:       j.id,
+		SourceID: j.id,
+		Job:      job,
+		Raw:      string(raw),
+		Error:    reason,
+		FailedAt: time.Now(),
+	})
+}
+
+// leased returns the index of the job if consumer still holds its lease; b.mu must be held
+func (b *MemoryBackend) leased(consumer, id string) (int, error) {
+	for i, j := range b.jobs {
+		if j.id == id {
+			if j.lockedBy != consumer {
+				return -1, ErrLeaseLost
+			}
+			return i, nil
+		}
+	}
+	return -1, ErrLeaseLost
+}
+
+func (b *MemoryBackend) ack(consumer string, d Delivery) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i, err := b.leased(consumer, d.ID)
+	if err != nil {
+		return err
+	}
+	b.jobs = append(b.jobs[:i], b.jobs[i+1:]...)
+	return nil
+}
+
+func (b *MemoryBackend) fail(consumer string, d Delivery, cause error) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i, err := b.leased(consumer, d.ID)
+	if err != nil {
+		return false, err
+	}
+
+	j := b.jobs[i]
+	reason := ""
+	if cause != nil {
+		reason = cause.Error()
+	}
+	if b.policy.Exhausted(j.deliveries) {
+		b.bury(j, reason)
+		b.jobs = append(b.jobs[:i], b.jobs[i+1:]...)
+		return true, nil
+	}
+
+	j.job.LastError = reason
+	j.lockedBy = ""
+	j.runAt = time.Now().Add(b.policy.Backoff(j.deliveries))
+	return false, nil
+}
+
+// DeadLetters returns up to count dead jobs, oldest first. A count of 0 returns all of them.
+func (b *MemoryBackend) DeadLetters(ctx context.Context, count int64) ([]DeadLetter, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := int64(len(b.dead))
+	if count > 0 && count < n {
+		n = count
+	}
+	return append([]DeadLetter(nil), b.dead[:n]...), nil
+}
+
+// DeadLetter returns a single dead job by its id
+func (b *MemoryBackend) DeadLetter(ctx context.Context, id string) (DeadLetter, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, l := range b.dead {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return DeadLetter{}, ErrDeadLetterNotFound
+}
+
+// Replay puts a dead job back in the queue with a fresh attempt counter
+func (b *MemoryBackend) Replay(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, l := range b.dead {
+		if l.ID == id {
+			job := l.Job
+			job.Attempts = 0
+			job.LastError = ""
+			b.dead = append(b.dead[:i], b.dead[i+1:]...)
+			b.jobs = append(b.jobs, &memoryJob{id: l.ID, job: job, runAt: time.Now()})
+			b.wake()
+			return nil
+		}
+	}
+	return ErrDeadLetterNotFound
+}
+
+// DropDeadLetter permanently discards a dead job
+func (b *MemoryBackend) DropDeadLetter(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, l := range b.dead {
+		if l.ID == id {
+			b.dead = append(b.dead[:i], b.dead[i+1:]...)
+			return nil
+		}
+	}
+	return ErrDeadLetterNotFound
+}
+
+// memorySource is a MemoryBackend consumer
+type memorySource struct {
+	backend *MemoryBackend
+	name    string
+}
+
+// memoryPollWait caps how long PollN blocks, so retries and expired leases
+// are noticed even when nothing is published
+const memoryPollWait = 100 * time.Millisecond
+
+func (s *memorySource) PollN(ctx context.Context, n int64) ([]Delivery, error) {
+	if n < 1 {
+		n = 1
+	}
+	deliveries, notify := s.backend.claim(s.name, n, time.Now())
+	if len(deliveries) > 0 {
+		return deliveries, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, nil
+	case <-notify:
+	case <-time.After(memoryPollWait):
+	}
+	deliveries, _ = s.backend.claim(s.name, n, time.Now())
+	return deliveries, nil
+}
+
+func (s *memorySource) Ack(ctx context.Context, d Delivery) error {
+	return s.backend.ack(s.name, d)
+}
+
+func (s *memorySource) Fail(ctx context.Context, d Delivery, cause error) (bool, error) {
+	return s.backend.fail(s.name, d, cause)
+}
+
+var (
+	_ Backend         = (*MemoryBackend)(nil)
+	_ DeadLetterStore = (*MemoryBackend)(nil)
+)
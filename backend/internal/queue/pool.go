@@ -0,0 +1,180 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Handler processes a single job. The context carries the per-job timeout.
+type Handler func(ctx context.Context, job EntryMLJob) error
+
+// JobSource is where a Pool takes deliveries from and reports results to
+type JobSource interface {
+	PollN(ctx context.Context, n int64) ([]Delivery, error)
+	Ack(ctx context.Context, d Delivery) error
+	Fail(ctx context.Context, d Delivery, cause error) (bool, error)
+}
+
+// PoolConfig sizes a Pool
+type PoolConfig struct {
+	// Workers is the number of goroutines processing jobs
+	Workers int
+	// MaxInFlight bounds deliveries that were read but not yet acked or failed
+	MaxInFlight int
+	// BatchSize is the maximum number of messages read per poll
+	BatchSize int
+	// JobTimeout cancels a single job that runs too long
+	JobTimeout time.Duration
+	// DrainTimeout is how long shutdown waits for in-flight jobs before
+	// cancelling them; their messages stay pending and are reclaimed later
+	DrainTimeout time.Duration
+}
+
+// DefaultPoolConfig returns the settings used when nothing is configured
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		Workers:      4,
+		MaxInFlight:  8,
+		BatchSize:    4,
+		JobTimeout:   time.Minute,
+		DrainTimeout: 30 * time.Second,
+	}
+}
+
+// Pool runs a Handler over deliveries from a JobSource with bounded concurrency
+type Pool struct {
+	source  JobSource
+	handler Handler
+	cfg     PoolConfig
+}
+
+func NewPool(source JobSource, handler Handler, cfg PoolConfig) *Pool {
+	def := DefaultPoolConfig()
+	if cfg.Workers < 1 {
+		cfg.Workers = def.Workers
+	}
+	if cfg.MaxInFlight < cfg.Workers {
+		cfg.MaxInFlight = cfg.Workers
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = def.BatchSize
+	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = def.JobTimeout
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = def.DrainTimeout
+	}
+	return &Pool{source: source, handler: handler, cfg: cfg}
+}
+
+// Run polls until ctx is cancelled, then stops polling and waits for
+// in-flight jobs to finish (up to DrainTimeout) before returning
+func (p *Pool) Run(ctx context.Context) {
+	// jobs outlive ctx so a shutdown signal does not abort them midway
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	slots := make(chan struct{}, p.cfg.MaxInFlight)
+	work := make(chan Delivery, p.cfg.MaxInFlight)
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range work {
+				p.process(jobCtx, d)
+				<-slots
+			}
+		}()
+	}
+
+	p.poll(ctx, slots, work)
+	close(work)
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Printf("worker pool: drained")
+	case <-time.After(p.cfg.DrainTimeout):
+		log.Printf("worker pool: drain timed out after %s, cancelling in-flight jobs", p.cfg.DrainTimeout)
+		cancelJobs()
+		<-drained
+	}
+}
+
+func (p *Pool) poll(ctx context.Context, slots chan struct{}, work chan<- Delivery) {
+	for {
+		// wait for at least one free slot before reading more
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+		free := 1
+	fill:
+		for free < p.cfg.BatchSize {
+			select {
+			case slots <- struct{}{}:
+				free++
+			default:
+				break fill
+			}
+		}
+
+		deliveries, err := p.source.PollN(ctx, int64(free))
+		if err != nil && ctx.Err() == nil {
+			log.Printf("worker pool: poll failed: %v", err)
+			time.Sleep(time.Second)
+		}
+
+		for _, d := range deliveries {
+			work <- d
+		}
+		// release the slots that were reserved but not filled
+		for i := len(deliveries); i < free; i++ {
+			<-slots
+		}
+	}
+}
+
+func (p *Pool) process(ctx context.Context, d Delivery) {
+	job := d.Job
+	log.Printf("Processing job entry=%s user=%s attempt=%d", job.EntryID, job.UserID, job.Attempts+1)
+
+	runCtx, cancel := context.WithTimeout(ctx, p.cfg.JobTimeout)
+	err := p.handler(runCtx, job)
+	cancel()
+
+	if ctx.Err() != nil {
+		// shutdown gave up on this job; leave it pending so it is reclaimed
+		log.Printf("job abandoned during shutdown entry=%s", job.EntryID)
+		return
+	}
+
+	if err != nil {
+		dead, failErr := p.source.Fail(ctx, d, err)
+		switch {
+		case failErr != nil:
+			// left pending in the group; redelivered once reclaimed
+			log.Printf("job failed: %v (could not schedule retry: %v)", err, failErr)
+		case dead:
+			log.Printf("job failed: %v (dead-lettered after %d attempts)", err, job.Attempts+1)
+		default:
+			log.Printf("job failed: %v (retry scheduled)", err)
+		}
+		return
+	}
+
+	if err := p.source.Ack(ctx, d); err != nil {
+		log.Printf("ack failed for entry=%s: %v", job.EntryID, err)
+	}
+}
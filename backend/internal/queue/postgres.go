@@ -0,0 +1,312 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrLeaseLost is returned when a job is acked or failed after its lease
+// expired and another consumer took it over
+var ErrLeaseLost = errors.New("job lease lost")
+
+const pgPollInterval = 500 * time.Millisecond
+
+// PostgresBackend keeps jobs in the jobs table. Workers lease rows with
+// FOR UPDATE SKIP LOCKED, so any number of them can drain the same queue.
+// A lease that expires without an ack makes the row available again, which
+// covers crashed workers the same way XAUTOCLAIM does for Redis.
+type PostgresBackend struct {
+	db     *sql.DB
+	queue  string
+	lease  time.Duration
+	policy RetryPolicy
+}
+
+func NewPostgresBackend(db *sql.DB, queue string, lease time.Duration) *PostgresBackend {
+	return NewPostgresBackendWithPolicy(db, queue, lease, DefaultRetryPolicy())
+}
+
+func NewPostgresBackendWithPolicy(db *sql.DB, queue string, lease time.Duration, policy RetryPolicy) *PostgresBackend {
+	return &PostgresBackend{db: db, queue: queue, lease: lease, policy: policy}
+}
+
+func (b *PostgresBackend) Publish(ctx context.Context, job EntryMLJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	_, err = b.db.ExecContext(ctx, `INSERT INTO jobs (queue, payload) VALUES ($1, $2)`, b.queue, data)
+	return err
+}
+
+func (b *PostgresBackend) Source(consumer string) JobSource {
+	return &pgSource{backend: b, name: consumer}
+}
+
+const leaseJobsSQL = `
+WITH next AS (
+	SELECT id FROM jobs
+	WHERE queue = $1
+	  AND ((status = 'queued' AND run_at <= NOW()) OR (status = 'running' AND locked_until < NOW()))
+	ORDER BY run_at, id
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE jobs j
+SET status = 'running', attempts = j.attempts + 1, locked_by = $3,
+    locked_until = NOW() + make_interval(secs => $4), updated_at = NOW()
+FROM next
+WHERE j.id = next.id
+RETURNING j.id, j.payload, j.attempts`
+
+// claim leases up to n ready or abandoned jobs for consumer
+func (b *PostgresBackend) claim(ctx context.Context, consumer string, n int64) ([]Delivery, error) {
+	rows, err := b.db.QueryContext(ctx, leaseJobsSQL, b.queue, n, consumer, b.lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type leased struct {
+		id       int64
+		payload  []byte
+		attempts int
+	}
+	var claimed []leased
+	for rows.Next() {
+		var l leased
+		if err := rows.Scan(&l.id, &l.payload, &l.attempts); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var deliveries []Delivery
+	for _, l := range claimed {
+		id := strconv.FormatInt(l.id, 10)
+		var job EntryMLJob
+		if err := json.Unmarshal(l.payload, &job); err != nil {
+			if err := b.bury(ctx, l.id, fmt.Sprintf("decode: %v", err), 0); err != nil {
+				return deliveries, err
+			}
+			continue
+		}
+		// a job that keeps killing its worker never reaches Fail
+		if b.policy.Exhausted(l.attempts - 1) {
+			reason := fmt.Sprintf("abandoned by consumers after %d deliveries", l.attempts-1)
+			if err := b.bury(ctx, l.id, reason, 1); err != nil {
+				return deliveries, err
+			}
+			continue
+		}
+		job.Attempts = l.attempts - 1
+		deliveries = append(deliveries, Delivery{ID: id, Job: job})
+	}
+	return deliveries, nil
+}
+
+// bury moves a job to the dead state; undo is subtracted from attempts for
+// deliveries that never ran
+func (b *PostgresBackend) bury(ctx context.Context, id int64, reason string, undo int) error {
+	_, err := b.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = 'dead', last_error = $2, attempts = attempts - $3,
+		    locked_by = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1`, id, reason, undo)
+	return err
+}
+
+func (b *PostgresBackend) ack(ctx context.Context, consumer string, d Delivery) error {
+	id, err := strconv.ParseInt(d.ID, 10, 64)
+	if err != nil {
+		return ErrLeaseLost
+	}
+	res, err := b.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = $1 AND locked_by = $2 AND status = 'running'`, id, consumer)
+	return leaseResult(res, err)
+}
+
+func (b *PostgresBackend) fail(ctx context.Context, consumer string, d Delivery, cause error) (bool, error) {
+	id, err := strconv.ParseInt(d.ID, 10, 64)
+	if err != nil {
+		return false, ErrLeaseLost
+	}
+	attempts := d.Job.Attempts + 1
+	reason := ""
+	if cause != nil {
+		reason = cause.Error()
+	}
+
+	if b.policy.Exhausted(attempts) {
+		res, err := b.db.ExecContext(ctx, `
+			UPDATE jobs
+			SET status = 'dead', last_error = $3, locked_by = NULL, locked_until = NULL, updated_at = NOW()
+			WHERE id = $1 AND locked_by = $2 AND status = 'running'`, id, consumer, reason)
+		return true, leaseResult(res, err)
+	}
+
+	res, err := b.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = 'queued', last_error = $3, run_at = NOW() + make_interval(secs => $4),
+		    locked_by = NULL, locked_until = NULL, updated_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'`,
+		id, consumer, reason, b.policy.Backoff(attempts).Seconds())
+	return false, leaseResult(res, err)
+}
+
+func leaseResult(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+const deadLetterColumns = `id, payload, attempts, COALESCE(last_error, ''), updated_at`
+
+// DeadLetters returns up to count dead jobs, oldest first. A count of 0 returns all of them.
+func (b *PostgresBackend) DeadLetters(ctx context.Context, count int64) ([]DeadLetter, error) {
+	var limit interface{}
+	if count > 0 {
+		limit = count
+	}
+	rows, err := b.db.QueryContext(ctx, `
+		SELECT `+deadLetterColumns+` FROM jobs
+		WHERE queue = $1 AND status = 'dead'
+		ORDER BY updated_at, id
+		LIMIT $2`, b.queue, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var letters []DeadLetter
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, letter)
+	}
+	return letters, rows.Err()
+}
+
+// DeadLetter returns a single dead job by its id
+func (b *PostgresBackend) DeadLetter(ctx context.Context, id string) (DeadLetter, error) {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	row := b.db.QueryRowContext(ctx, `
+		SELECT `+deadLetterColumns+` FROM jobs
+		WHERE id = $1 AND queue = $2 AND status = 'dead'`, jobID, b.queue)
+	letter, err := scanDeadLetter(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	return letter, err
+}
+
+// Replay puts a dead job back in the queue with a fresh attempt counter
+func (b *PostgresBackend) Replay(ctx context.Context, id string) error {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrDeadLetterNotFound
+	}
+	res, err := b.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = 'queued', attempts = 0, last_error = NULL, run_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND queue = $2 AND status = 'dead'`, jobID, b.queue)
+	return deadLetterResult(res, err)
+}
+
+// DropDeadLetter permanently discards a dead job
+func (b *PostgresBackend) DropDeadLetter(ctx context.Context, id string) error {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrDeadLetterNotFound
+	}
+	res, err := b.db.ExecContext(ctx, `DELETE FROM jobs WHERE id = $1 AND queue = $2 AND status = 'dead'`, jobID, b.queue)
+	return deadLetterResult(res, err)
+}
+
+func deadLetterResult(res sql.Result, err error) error {
+	if err := leaseResult(res, err); errors.Is(err, ErrLeaseLost) {
+		return ErrDeadLetterNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDeadLetter(row rowScanner) (DeadLetter, error) {
+	var (
+		id       int64
+		payload  []byte
+		attempts int
+		letter   DeadLetter
+	)
+	if err := row.Scan(&id, &payload, &attempts, &letter.Error, &letter.FailedAt); err != nil {
+		return DeadLetter{}, err
+	}
+	letter.ID = strconv.FormatInt(id, 10)
+	letter.SourceID = letter.ID
+	letter.Raw = string(payload)
+	_ = json.Unmarshal(payload, &letter.Job)
+	letter.Job.Attempts = attempts
+	letter.Job.LastError = letter.Error
+	return letter, nil
+}
+
+// pgSource is a PostgresBackend consumer
+type pgSource struct {
+	backend *PostgresBackend
+	name    string
+}
+
+func (s *pgSource) PollN(ctx context.Context, n int64) ([]Delivery, error) {
+	if n < 1 {
+		n = 1
+	}
+	deliveries, err := s.backend.claim(ctx, s.name, n)
+	if err != nil || len(deliveries) > 0 {
+		return deliveries, err
+	}
+
+	// nothing ready; back off instead of hammering the table
+	select {
+	case <-ctx.Done():
+	case <-time.After(pgPollInterval):
+	}
+	return nil, nil
+}
+
+func (s *pgSource) Ack(ctx context.Context, d Delivery) error {
+	return s.backend.ack(ctx, s.name, d)
+}
+
+func (s *pgSource) Fail(ctx context.Context, d Delivery, cause error) (bool, error) {
+	return s.backend.fail(ctx, s.name, d, cause)
+}
+
+var (
+	_ Backend         = (*PostgresBackend)(nil)
+	_ DeadLetterStore = (*PostgresBackend)(nil)
+)
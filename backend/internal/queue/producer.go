@@ -3,13 +3,14 @@ package queue
 import "context"
 
 type Producer struct {
-	streams *Streams
+	publisher Publisher
 }
 
-func NewProducer(s *Streams) *Producer {
-	return &Producer{streams: s}
+// NewProducer creates a Producer on top of any queue backend, e.g. *Streams
+func NewProducer(p Publisher) *Producer {
+	return &Producer{publisher: p}
 }
 
 func (p *Producer) Enqueue(ctx context.Context, job EntryMLJob) error {
-	return p.streams.Publish(ctx, job)
+	return p.publisher.Publish(ctx, job)
 }
@@ -0,0 +1,156 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// staleConsumerIdle is how long a consumer with nothing pending may stay
+// silent before it is removed from the group. Worker names are unique per
+// process, so without this every restart would leave an entry behind.
+const staleConsumerIdle = time.Hour
+
+// ConsumerName builds a consumer name unique to this process, e.g. ml-worker-host-1234
+func ConsumerName(prefix string) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%s-%d", prefix, host, os.Getpid())
+}
+
+// Reclaim takes over messages that another consumer received but did not
+// acknowledge within minIdle, typically because its process died. Messages
+// that were already delivered more often than the retry policy allows are
+// dead-lettered instead of being handed out again.
+func (s *Streams) Reclaim(ctx context.Context, group, consumer string, minIdle time.Duration, count int64) ([]Delivery, error) {
+	msgs, _, err := s.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   s.stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []Delivery
+	for _, msg := range msgs {
+		raw, _ := msg.Values["job"].(string)
+		if raw == "" {
+			// trimmed from the stream while pending, nothing left to process
+			if err := s.client.XAck(ctx, s.stream, group, msg.ID).Err(); err != nil {
+				return deliveries, err
+			}
+			continue
+		}
+
+		var job EntryMLJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			if err := s.deadLetter(ctx, group, msg.ID, raw, fmt.Sprintf("decode: %v", err)); err != nil {
+				return deliveries, err
+			}
+			continue
+		}
+
+		deliveredTimes, err := s.deliveryCount(ctx, group, msg.ID)
+		if err != nil {
+			return deliveries, err
+		}
+		// a job that keeps killing its worker never reaches Fail
+		if s.policy.Exhausted(int(deliveredTimes) - 1) {
+			reason := fmt.Sprintf("abandoned by consumers after %d deliveries", deliveredTimes-1)
+			if err := s.deadLetter(ctx, group, msg.ID, raw, reason); err != nil {
+				return deliveries, err
+			}
+			continue
+		}
+
+		deliveries = append(deliveries, Delivery{ID: msg.ID, Job: job})
+	}
+	return deliveries, nil
+}
+
+// deliveryCount returns how many times a pending message has been delivered, including the current claim
+func (s *Streams) deliveryCount(ctx context.Context, group, id string) (int64, error) {
+	pending, err := s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: s.stream,
+		Group:  group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	return pending[0].RetryCount, nil
+}
+
+// PruneConsumers removes consumers that have nothing pending and have been
+// idle for a long time. It returns the names of the removed consumers.
+func (s *Streams) PruneConsumers(ctx context.Context, group string) ([]string, error) {
+	consumers, err := s.consumers(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, c := range consumers {
+		if c.Pending > 0 || c.Idle < staleConsumerIdle {
+			continue
+		}
+		if err := s.client.XGroupDelConsumer(ctx, s.stream, group, c.Name).Err(); err != nil {
+			return removed, err
+		}
+		removed = append(removed, c.Name)
+	}
+	return removed, nil
+}
+
+// consumers runs XINFO CONSUMERS directly: the typed client rejects the
+// "inactive" field that Redis 7.2 added to the reply
+func (s *Streams) consumers(ctx context.Context, group string) ([]redis.XInfoConsumer, error) {
+	res, err := s.client.Do(ctx, "XINFO", "CONSUMERS", s.stream, group).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	consumers := make([]redis.XInfoConsumer, 0, len(res))
+	for _, item := range res {
+		fields := map[string]interface{}{}
+		switch v := item.(type) {
+		case []interface{}: // RESP2 flat key/value list
+			for i := 0; i+1 < len(v); i += 2 {
+				if k, ok := v[i].(string); ok {
+					fields[k] = v[i+1]
+				}
+			}
+		case map[interface{}]interface{}: // RESP3 map
+			for k, val := range v {
+				if ks, ok := k.(string); ok {
+					fields[ks] = val
+				}
+			}
+		}
+		c := redis.XInfoConsumer{}
+		c.Name, _ = fields["name"].(string)
+		if n, ok := fields["pending"].(int64); ok {
+			c.Pending = n
+		}
+		if n, ok := fields["idle"].(int64); ok {
+			c.Idle = time.Duration(n) * time.Millisecond
+		}
+		consumers = append(consumers, c)
+	}
+	return consumers, nil
+}
@@ -0,0 +1,44 @@
+package queue
+
+import "time"
+
+// RetryPolicy controls how often a failed job is re-delivered before it is
+// moved to the dead-letter stream
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy makes 5 attempts, so 4 retries after delays of 2s, 4s, 8s
+// and 16s, then dead-letters the job. MaxDelay only bites with more attempts.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   2 * time.Second,
+		MaxDelay:    5 * time.Minute,
+	}
+}
+
+// Backoff returns the delay before the given attempt (1-based) is re-delivered
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// Exhausted reports whether a job that has failed attempts times should be dead-lettered
+func (p RetryPolicy) Exhausted(attempts int) bool {
+	return attempts >= p.MaxAttempts
+}
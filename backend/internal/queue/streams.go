@@ -4,18 +4,48 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// promoteDueScript moves every retry whose backoff has elapsed back onto the
+// main stream. Running it as a script keeps concurrent workers from
+// re-publishing the same job twice.
+var promoteDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, job in ipairs(due) do
+	redis.call('XADD', KEYS[2], '*', 'job', job)
+	redis.call('ZREM', KEYS[1], job)
+end
+return #due
+`)
+
+const promoteBatchSize = 100
+
 type Streams struct {
-	client *redis.Client
-	stream string
+	client  *redis.Client
+	stream  string
+	dlq     string
+	delayed string
+	policy  RetryPolicy
 }
 
 func NewStreams(client *redis.Client, stream string) *Streams {
-	return &Streams{client: client, stream: stream}
+	return NewStreamsWithPolicy(client, stream, DefaultRetryPolicy())
+}
+
+// NewStreamsWithPolicy creates Streams with a custom retry policy.
+// Failed jobs wait in <stream>:retry and dead jobs land in <stream>:dlq.
+func NewStreamsWithPolicy(client *redis.Client, stream string, policy RetryPolicy) *Streams {
+	return &Streams{
+		client:  client,
+		stream:  stream,
+		dlq:     stream + ":dlq",
+		delayed: stream + ":retry",
+		policy:  policy,
+	}
 }
 
 func (s *Streams) Publish(ctx context.Context, job EntryMLJob) error {
@@ -30,37 +60,173 @@ func (s *Streams) Publish(ctx context.Context, job EntryMLJob) error {
 	return err
 }
 
-func (s *Streams) Consume(ctx context.Context, group, consumer string, timeout time.Duration) ([]EntryMLJob, error) {
+// Consume reads up to count new jobs for the consumer. Messages stay pending in the group
+// until Ack or Fail is called, so a crashed worker does not lose them.
+func (s *Streams) Consume(ctx context.Context, group, consumer string, count int64, timeout time.Duration) ([]Delivery, error) {
 	res, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
 		Group:    group,
 		Consumer: consumer,
 		Streams:  []string{s.stream, ">"},
-		Count:    1,
+		Count:    count,
 		Block:    timeout,
 	}).Result()
-
+	if err == redis.Nil {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	var jobs []EntryMLJob
+	var deliveries []Delivery
 	for _, stream := range res {
 		for _, msg := range stream.Messages {
-			raw, ok := msg.Values["job"].(string)
-			if !ok {
-				continue
-			}
+			raw, _ := msg.Values["job"].(string)
 			var job EntryMLJob
 			if err := json.Unmarshal([]byte(raw), &job); err != nil {
+				// retrying cannot fix a bad payload
+				if dlqErr := s.deadLetter(ctx, group, msg.ID, raw, fmt.Sprintf("decode: %v", err)); dlqErr != nil {
+					return deliveries, dlqErr
+				}
 				continue
 			}
-			jobs = append(jobs, job)
-
-			// ack
-			_, _ = s.client.XAck(ctx, s.stream, group, msg.ID).Result()
+			deliveries = append(deliveries, Delivery{ID: msg.ID, Job: job})
 		}
 	}
-	return jobs, nil
+	return deliveries, nil
+}
+
+// Ack marks a delivery as successfully processed
+func (s *Streams) Ack(ctx context.Context, group string, d Delivery) error {
+	return s.client.XAck(ctx, s.stream, group, d.ID).Err()
+}
+
+// Fail records a failed attempt. The job is scheduled for re-delivery with
+// exponential backoff, or moved to the dead-letter stream once the retry
+// policy is exhausted. It reports whether the job was dead-lettered.
+func (s *Streams) Fail(ctx context.Context, group string, d Delivery, cause error) (bool, error) {
+	job := d.Job
+	job.Attempts++
+	if cause != nil {
+		job.LastError = cause.Error()
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return false, err
+	}
+
+	if s.policy.Exhausted(job.Attempts) {
+		return true, s.deadLetter(ctx, group, d.ID, string(data), job.LastError)
+	}
+
+	due := time.Now().Add(s.policy.Backoff(job.Attempts))
+	pipe := s.client.TxPipeline()
+	pipe.ZAdd(ctx, s.delayed, redis.Z{Score: float64(due.UnixMilli()), Member: string(data)})
+	pipe.XAck(ctx, s.stream, group, d.ID)
+	_, err = pipe.Exec(ctx)
+	return false, err
+}
+
+// PromoteDue re-publishes retries whose backoff has elapsed
+func (s *Streams) PromoteDue(ctx context.Context, now time.Time) (int, error) {
+	n, err := promoteDueScript.Run(ctx, s.client, []string{s.delayed, s.stream}, now.UnixMilli(), promoteBatchSize).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// deadLetter copies the message to the DLQ and acknowledges the original in one transaction
+func (s *Streams) deadLetter(ctx context.Context, group, id, raw, reason string) error {
+	pipe := s.client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.dlq,
+		Values: map[string]interface{}{
+			"job":       raw,
+			"error":     reason,
+			"source_id": id,
+			"failed_at": time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	pipe.XAck(ctx, s.stream, group, id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeadLetters returns up to count dead jobs, oldest first. A count of 0 returns all of them.
+func (s *Streams) DeadLetters(ctx context.Context, count int64) ([]DeadLetter, error) {
+	var msgs []redis.XMessage
+	var err error
+	if count > 0 {
+		msgs, err = s.client.XRangeN(ctx, s.dlq, "-", "+", count).Result()
+	} else {
+		msgs, err = s.client.XRange(ctx, s.dlq, "-", "+").Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]DeadLetter, 0, len(msgs))
+	for _, msg := range msgs {
+		letters = append(letters, toDeadLetter(msg))
+	}
+	return letters, nil
+}
+
+// DeadLetter returns a single dead job by its DLQ message ID
+func (s *Streams) DeadLetter(ctx context.Context, id string) (DeadLetter, error) {
+	msgs, err := s.client.XRange(ctx, s.dlq, id, id).Result()
+	if err != nil {
+		return DeadLetter{}, err
+	}
+	if len(msgs) == 0 {
+		return DeadLetter{}, ErrDeadLetterNotFound
+	}
+	return toDeadLetter(msgs[0]), nil
+}
+
+// Replay re-publishes a dead job with a fresh attempt counter and removes it from the DLQ
+func (s *Streams) Replay(ctx context.Context, id string) error {
+	letter, err := s.DeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if letter.Job.EntryID == "" {
+		return ErrUndecodableJob
+	}
+
+	job := letter.Job
+	job.Attempts = 0
+	job.LastError = ""
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		Values: map[string]interface{}{"job": data},
+	})
+	pipe.XDel(ctx, s.dlq, id)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// DropDeadLetter permanently discards a dead job
+func (s *Streams) DropDeadLetter(ctx context.Context, id string) error {
+	n, err := s.client.XDel(ctx, s.dlq, id).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+// DeadLetterStream returns the name of the dead-letter stream
+func (s *Streams) DeadLetterStream() string {
+	return s.dlq
 }
 
 func (s *Streams) EnsureGroup(ctx context.Context, group string) error {
@@ -73,6 +239,26 @@ func (s *Streams) EnsureGroup(ctx context.Context, group string) error {
 }
 
 func isBusyGroupError(err error) bool {
-	return err != nil && (len(err.Error()) > 0 && ( // naive
-	fmt.Sprintf("%v", err) == "BUSYGROUP Consumer Group name already exists"))
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
+
+func toDeadLetter(msg redis.XMessage) DeadLetter {
+	letter := DeadLetter{ID: msg.ID}
+	letter.Raw, _ = msg.Values["job"].(string)
+	letter.Error, _ = msg.Values["error"].(string)
+	letter.SourceID, _ = msg.Values["source_id"].(string)
+	if ts, ok := msg.Values["failed_at"].(string); ok {
+		letter.FailedAt, _ = time.Parse(time.RFC3339, ts)
+	}
+	_ = json.Unmarshal([]byte(letter.Raw), &letter.Job)
+	return letter
+}
+
+// Pending returns how many delivered messages the group has not acknowledged yet
+func (s *Streams) Pending(ctx context.Context, group string) (int64, error) {
+	res, err := s.client.XPending(ctx, s.stream, group).Result()
+	if err != nil {
+		return 0, err
+	}
+	return res.Count, nil
 }
@@ -1,10 +1,45 @@
 package queue
 
+import "time"
+
 type EntryMLJob struct {
+	// IdempotencyKey stays the same across redeliveries and retries of a job
+	IdempotencyKey string `json:",omitempty"`
+
 	EntryID   string
 	UserID    string
 	Text      string
 	Persona   string
 	Intensity int
 	History   []string
+
+	// Attempts counts failed deliveries so far; LastError is the most recent failure
+	Attempts  int
+	LastError string `json:",omitempty"`
+}
+
+// Key returns the idempotency key, falling back to the entry ID for jobs
+// published before keys existed
+func (j EntryMLJob) Key() string {
+	if j.IdempotencyKey != "" {
+		return j.IdempotencyKey
+	}
+	return "entry:" + j.EntryID
+}
+
+// Delivery is a job read from the stream that has not been acknowledged yet.
+// It must be passed back to Ack on success or Fail on error.
+type Delivery struct {
+	ID  string
+	Job EntryMLJob
+}
+
+// DeadLetter is a job that exhausted its retries or could not be decoded
+type DeadLetter struct {
+	ID       string
+	SourceID string
+	Job      EntryMLJob
+	Raw      string
+	Error    string
+	FailedAt time.Time
 }
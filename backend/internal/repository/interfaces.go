@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 
 	sqlc "guiltmachine/internal/db/sqlc"
 
@@ -25,18 +28,106 @@ type SessionsRepository interface {
 type EntriesRepository interface {
 	CreateEntry(ctx context.Context, sessionID uuid.UUID, text string, level int32) (sqlc.GuiltEntry, error)
 	ListEntriesBySession(ctx context.Context, sessionID uuid.UUID) ([]sqlc.GuiltEntry, error)
+	// ListEntriesBefore returns up to limit entries written before entryID,
+	// newest first, from its session or, acrossSessions, from any session of
+	// the same user
+	ListEntriesBefore(ctx context.Context, entryID uuid.UUID, acrossSessions bool, limit int32) ([]sqlc.GuiltEntry, error)
 	UpdateRoast(ctx context.Context, entryID uuid.UUID, roastText sql.NullString) error
 	UpdateEntryStatus(ctx context.Context, entryID uuid.UUID, status string) error
 	GetEntry(ctx context.Context, entryID uuid.UUID) (sqlc.GuiltEntry, error)
+	// CreateEntryWithOutbox inserts a pending entry and the outbox message built
+	// from it in one transaction, so neither exists without the other
+	CreateEntryWithOutbox(ctx context.Context, sessionID uuid.UUID, text string, level int32, topic string, payload func(sqlc.GuiltEntry) ([]byte, error)) (sqlc.GuiltEntry, error)
+	// ClaimEntry moves a pending or failed entry to processing under jobKey.
+	// Redeliveries of the job holding the claim succeed again; it reports
+	// false when the entry is completed or claimed by another job.
+	ClaimEntry(ctx context.Context, entryID uuid.UUID, jobKey string) (bool, error)
+	// FinishEntry moves an entry claimed by jobKey to completed or failed.
+	// It reports false when the claim was lost.
+	FinishEntry(ctx context.Context, entryID uuid.UUID, jobKey string, status string) (bool, error)
 }
 
 type ScoresRepository interface {
 	CreateScore(ctx context.Context, sessionID uuid.UUID, entryID *uuid.UUID, score int32, meta any) (sqlc.GuiltScore, error)
 	GetScoreBySession(ctx context.Context, sessionID uuid.UUID) (sqlc.GuiltScore, error)
 	GetScoreByEntry(ctx context.Context, entryID uuid.UUID) (sqlc.GuiltScore, error)
+	// UpsertEntryScore creates or replaces the single score of an entry
+	UpsertEntryScore(ctx context.Context, sessionID uuid.UUID, entryID uuid.UUID, score int32, meta any) (sqlc.GuiltScore, error)
 }
 
 type PreferencesRepository interface {
-	UpsertPreferences(ctx context.Context, userID uuid.UUID, theme *string, notifications bool, metadata any) (sqlc.UserPreference, error)
+	// UpsertPreferences creates or replaces the preferences of pref.UserID
+	// with the settable fields of pref
+	UpsertPreferences(ctx context.Context, pref sqlc.UserPreference) (sqlc.UserPreference, error)
 	GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) (sqlc.UserPreference, error)
+	// UpdatePreferences hands the current preferences of a user, or the
+	// column defaults if there are none, to update and saves the result. The
+	// row stays locked in between so concurrent partial updates do not
+	// overwrite each other; an error from update aborts without saving.
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, update func(*sqlc.UserPreference) error) (sqlc.UserPreference, error)
+}
+
+type TasksRepository interface {
+	// CreateTask inserts a pending task with the settable fields of task and
+	// records the type and payload of event for it in the same transaction
+	CreateTask(ctx context.Context, task sqlc.Task, event sqlc.TaskEvent) (sqlc.Task, error)
+	// GetTask and the other reads never return soft-deleted tasks
+	GetTask(ctx context.Context, id uuid.UUID) (sqlc.Task, error)
+	// ListTasksByUser lists a user's tasks by due date, undated tasks last.
+	// An empty status lists tasks of every status.
+	ListTasksByUser(ctx context.Context, userID uuid.UUID, status string, limit int32, offset int32) ([]sqlc.Task, error)
+	// UpdateTask hands the current task to update and saves the result along
+	// with the event update returns, if any. The row stays locked in between;
+	// an error from update aborts without saving.
+	UpdateTask(ctx context.Context, id uuid.UUID, update func(*sqlc.Task) (*sqlc.TaskEvent, error)) (sqlc.Task, error)
+	// DeleteTask soft-deletes a task and records event for it. It returns
+	// sql.ErrNoRows if the task does not exist or is already deleted.
+	DeleteTask(ctx context.Context, id uuid.UUID, event sqlc.TaskEvent) error
+}
+
+type TaskEventsRepository interface {
+	// ListTaskEventsByUser pages through a user's task events, oldest first.
+	// Empty types match every type; a zero since or until leaves that end of
+	// the time range open.
+	ListTaskEventsByUser(ctx context.Context, userID uuid.UUID, types []string, since, until time.Time, limit int32, offset int32) ([]sqlc.TaskEvent, error)
+}
+
+// UserSignals is the raw behavior of a user in a time range, oldest first
+type UserSignals struct {
+	SessionStarts []time.Time
+	Entries       []sqlc.ListEntrySignalsByUserRow
+	TaskEvents    []sqlc.TaskEvent
+}
+
+type FeatureSnapshotsRepository interface {
+	// ListActiveUsers returns up to limit ids, in order and after after, of
+	// users who started sessions, wrote entries or changed tasks since since
+	ListActiveUsers(ctx context.Context, since time.Time, after uuid.UUID, limit int32) ([]uuid.UUID, error)
+	// GetUserSignals loads what a user did from since up to until
+	GetUserSignals(ctx context.Context, userID uuid.UUID, since, until time.Time) (UserSignals, error)
+	CreateFeatureSnapshot(ctx context.Context, snap sqlc.FeatureSnapshot) (sqlc.FeatureSnapshot, error)
+	// GetLatestFeatureSnapshot returns sql.ErrNoRows for users without snapshots
+	GetLatestFeatureSnapshot(ctx context.Context, userID uuid.UUID) (sqlc.FeatureSnapshot, error)
+	// ListFeatureSnapshotsByUser pages through a user's snapshots, newest first
+	ListFeatureSnapshotsByUser(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]sqlc.FeatureSnapshot, error)
+	// CreateClusterModel stores centroids as the next model version
+	CreateClusterModel(ctx context.Context, centroids json.RawMessage, sampleSize int32) (sqlc.ClusterModel, error)
+	// GetLatestClusterModel returns sql.ErrNoRows until a model was trained
+	GetLatestClusterModel(ctx context.Context) (sqlc.ClusterModel, error)
+}
+
+// ErrUndeliverable marks an outbox message that no retry can publish, such as
+// one with an unknown topic or a payload that does not decode
+var ErrUndeliverable = errors.New("undeliverable outbox message")
+
+type OutboxRepository interface {
+	// RelayBatch claims up to limit unsent messages and hands them to publish
+	// in order; claimed messages are not handed to other relays for a while.
+	// Published messages are marked sent. A failure wrapping ErrUndeliverable
+	// marks its message dead, so it is never handed out again, fails the
+	// entry the message was written for, and the batch goes on; any other
+	// failure is recorded on its row and ends the batch. It returns how many
+	// messages were sent.
+	RelayBatch(ctx context.Context, limit int32, publish func(context.Context, sqlc.Outbox) error) (int, error)
+	DeleteSent(ctx context.Context, before time.Time) (int64, error)
 }
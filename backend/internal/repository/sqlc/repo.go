@@ -1,9 +1,13 @@
 package sqlc
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
@@ -19,6 +23,10 @@ type Repos struct {
 	Entries     repository.EntriesRepository
 	Scores      repository.ScoresRepository
 	Preferences repository.PreferencesRepository
+	Tasks       repository.TasksRepository
+	TaskEvents  repository.TaskEventsRepository
+	Features    repository.FeatureSnapshotsRepository
+	Outbox      repository.OutboxRepository
 }
 
 func New(db dbpkg.DB) *Repos {
@@ -26,9 +34,13 @@ func New(db dbpkg.DB) *Repos {
 	return &Repos{
 		Users:       &usersRepo{q},
 		Sessions:    &sessionsRepo{q},
-		Entries:     &entriesRepo{q},
+		Entries:     &entriesRepo{q: q, db: db},
 		Scores:      &scoresRepo{q},
-		Preferences: &preferencesRepo{q},
+		Preferences: &preferencesRepo{q: q, db: db},
+		Tasks:       &tasksRepo{q: q, db: db},
+		TaskEvents:  &taskEventsRepo{q},
+		Features:    &featureSnapshotsRepo{q},
+		Outbox:      &outboxRepo{q: q, db: db},
 	}
 }
 
@@ -87,7 +99,10 @@ func (r *sessionsRepo) ListSessionsByUser(ctx context.Context, userID uuid.UUID,
 
 // ENTRIES
 
-type entriesRepo struct{ q *sqlc.Queries }
+type entriesRepo struct {
+	q  *sqlc.Queries
+	db dbpkg.DB
+}
 
 func (r *entriesRepo) CreateEntry(ctx context.Context, sessionID uuid.UUID, text string, level int32) (sqlc.GuiltEntry, error) {
 	params := sqlc.CreateEntryParams{
@@ -123,6 +138,32 @@ func (r *entriesRepo) ListEntriesBySession(ctx context.Context, sessionID uuid.U
 			EntryText:  row.EntryText,
 			GuiltLevel: row.GuiltLevel,
 			RoastText:  row.RoastText,
+			Status:     row.Status,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		}
+	}
+	return entries, nil
+}
+
+func (r *entriesRepo) ListEntriesBefore(ctx context.Context, entryID uuid.UUID, acrossSessions bool, limit int32) ([]sqlc.GuiltEntry, error) {
+	rows, err := r.q.ListEntriesBefore(ctx, sqlc.ListEntriesBeforeParams{
+		ID:             entryID,
+		AcrossSessions: acrossSessions,
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]sqlc.GuiltEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = sqlc.GuiltEntry{
+			ID:         row.ID,
+			SessionID:  row.SessionID,
+			EntryText:  row.EntryText,
+			GuiltLevel: row.GuiltLevel,
+			RoastText:  row.RoastText,
+			Status:     row.Status,
 			CreatedAt:  row.CreatedAt,
 			UpdatedAt:  row.UpdatedAt,
 		}
@@ -163,6 +204,137 @@ func (r *entriesRepo) GetEntry(ctx context.Context, entryID uuid.UUID) (sqlc.Gui
 	}, nil
 }
 
+func (r *entriesRepo) CreateEntryWithOutbox(ctx context.Context, sessionID uuid.UUID, text string, level int32, topic string, payload func(sqlc.GuiltEntry) ([]byte, error)) (sqlc.GuiltEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqlc.GuiltEntry{}, err
+	}
+	defer tx.Rollback()
+
+	txRepo := &entriesRepo{q: r.q.WithTx(tx)}
+	e, err := txRepo.CreateEntry(ctx, sessionID, text, level)
+	if err != nil {
+		return sqlc.GuiltEntry{}, err
+	}
+	if err := txRepo.UpdateEntryStatus(ctx, e.ID, "pending"); err != nil {
+		return sqlc.GuiltEntry{}, err
+	}
+	e.Status = sql.NullString{String: "pending", Valid: true}
+
+	body, err := payload(e)
+	if err != nil {
+		return sqlc.GuiltEntry{}, err
+	}
+	params := sqlc.InsertOutboxParams{Topic: topic, Payload: body, EntryID: uuid.NullUUID{UUID: e.ID, Valid: true}}
+	if _, err := txRepo.q.InsertOutbox(ctx, params); err != nil {
+		return sqlc.GuiltEntry{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sqlc.GuiltEntry{}, err
+	}
+	return e, nil
+}
+
+// OUTBOX
+
+// outboxClaim is how long a relay has to publish a batch before other
+// relays may hand its messages out again
+const outboxClaim = time.Minute
+
+type outboxRepo struct {
+	q  *sqlc.Queries
+	db dbpkg.DB
+}
+
+func (r *outboxRepo) RelayBatch(ctx context.Context, limit int32, publish func(context.Context, sqlc.Outbox) error) (int, error) {
+	// claims replace row locks, so no transaction stays open while publishing
+	now := time.Now()
+	msgs, err := r.q.ClaimOutbox(ctx, sqlc.ClaimOutboxParams{ClaimedUntil: now.Add(outboxClaim), Now: now, MaxRows: limit})
+	if err != nil {
+		return 0, err
+	}
+	slices.SortFunc(msgs, func(a, b sqlc.Outbox) int { return cmp.Compare(a.ID, b.ID) })
+
+	sent := 0
+	for i, m := range msgs {
+		pubErr := publish(ctx, m)
+		if errors.Is(pubErr, repository.ErrUndeliverable) {
+			if err := r.markDead(ctx, m, pubErr); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		if pubErr != nil {
+			params := sqlc.MarkOutboxFailedParams{
+				ID:        m.ID,
+				LastError: sql.NullString{String: pubErr.Error(), Valid: true},
+			}
+			if err := r.q.MarkOutboxFailed(ctx, params); err != nil {
+				return sent, err
+			}
+			// hand the rest back rather than leave it to the claims running out
+			for _, rest := range msgs[i+1:] {
+				if err := r.q.ReleaseOutbox(ctx, rest.ID); err != nil {
+					return sent, err
+				}
+			}
+			break
+		}
+		if err := r.q.MarkOutboxSent(ctx, m.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// markDead sets a message aside and fails the entry it was written for,
+// which no job will ever finish
+func (r *outboxRepo) markDead(ctx context.Context, m sqlc.Outbox, cause error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	params := sqlc.MarkOutboxDeadParams{
+		ID:        m.ID,
+		LastError: sql.NullString{String: cause.Error(), Valid: true},
+	}
+	if err := q.MarkOutboxDead(ctx, params); err != nil {
+		return err
+	}
+	if err := q.FailOutboxEntry(ctx, m.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *outboxRepo) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	return r.q.DeleteSentOutbox(ctx, sql.NullTime{Time: before, Valid: true})
+}
+
+func (r *entriesRepo) ClaimEntry(ctx context.Context, entryID uuid.UUID, jobKey string) (bool, error) {
+	params := sqlc.ClaimEntryForProcessingParams{
+		ID:       entryID,
+		MlJobKey: sql.NullString{String: jobKey, Valid: true},
+	}
+	n, err := r.q.ClaimEntryForProcessing(ctx, params)
+	return n > 0, err
+}
+
+func (r *entriesRepo) FinishEntry(ctx context.Context, entryID uuid.UUID, jobKey string, status string) (bool, error) {
+	params := sqlc.FinishEntryProcessingParams{
+		ID:       entryID,
+		MlJobKey: sql.NullString{String: jobKey, Valid: true},
+		Status:   sql.NullString{String: status, Valid: true},
+	}
+	n, err := r.q.FinishEntryProcessing(ctx, params)
+	return n > 0, err
+}
+
 // SCORES
 
 type scoresRepo struct{ q *sqlc.Queries }
@@ -230,29 +402,337 @@ func (r *scoresRepo) GetScoreByEntry(ctx context.Context, entryID uuid.UUID) (sq
 	}, nil
 }
 
-// PREFERENCES
-
-type preferencesRepo struct{ q *sqlc.Queries }
-
-func (r *preferencesRepo) UpsertPreferences(ctx context.Context, userID uuid.UUID, theme *string, notifications bool, metadata any) (sqlc.UserPreference, error) {
-	var ts sql.NullString
-	if theme != nil {
-		ts = sql.NullString{String: *theme, Valid: true}
-	}
+func (r *scoresRepo) UpsertEntryScore(ctx context.Context, sessionID uuid.UUID, entryID uuid.UUID, score int32, meta any) (sqlc.GuiltScore, error) {
 	var rm pqtype.NullRawMessage
-	if metadata != nil {
-		b, _ := json.Marshal(metadata)
+	if meta != nil {
+		b, _ := json.Marshal(meta)
 		rm = pqtype.NullRawMessage{RawMessage: b, Valid: true}
 	}
+	params := sqlc.UpsertEntryScoreParams{
+		SessionID:      sessionID,
+		EntryID:        uuid.NullUUID{UUID: entryID, Valid: true},
+		AggregateScore: score,
+		Meta:           rm,
+	}
+	row, err := r.q.UpsertEntryScore(ctx, params)
+	if err != nil {
+		return sqlc.GuiltScore{}, err
+	}
+	return sqlc.GuiltScore{
+		ID:             row.ID,
+		SessionID:      row.SessionID,
+		EntryID:        row.EntryID,
+		AggregateScore: row.AggregateScore,
+		Meta:           row.Meta,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}, nil
+}
+
+// PREFERENCES
+
+type preferencesRepo struct {
+	q  *sqlc.Queries
+	db dbpkg.DB
+}
+
+func (r *preferencesRepo) UpsertPreferences(ctx context.Context, pref sqlc.UserPreference) (sqlc.UserPreference, error) {
 	params := sqlc.UpsertUserPreferencesParams{
-		UserID:               userID,
-		Theme:                ts,
-		NotificationsEnabled: notifications,
-		Metadata:             rm,
+		UserID:               pref.UserID,
+		Theme:                pref.Theme,
+		NotificationsEnabled: pref.NotificationsEnabled,
+		Metadata:             pref.Metadata,
+		Persona:              pref.Persona,
+		HumorIntensity:       pref.HumorIntensity,
+		Timezone:             pref.Timezone,
+		Language:             pref.Language,
+		WorkStartMinute:      pref.WorkStartMinute,
+		WorkEndMinute:        pref.WorkEndMinute,
+		QuietStartMinute:     pref.QuietStartMinute,
+		QuietEndMinute:       pref.QuietEndMinute,
+		NudgeChannels:        pref.NudgeChannels,
+	}
+	if params.NudgeChannels == nil {
+		params.NudgeChannels = []string{}
 	}
 	return r.q.UpsertUserPreferences(ctx, params)
 }
 
+func (r *preferencesRepo) UpdatePreferences(ctx context.Context, userID uuid.UUID, update func(*sqlc.UserPreference) error) (sqlc.UserPreference, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqlc.UserPreference{}, err
+	}
+	defer tx.Rollback()
+
+	// a missing row is created with the column defaults, so there is always
+	// one to lock
+	txRepo := &preferencesRepo{q: r.q.WithTx(tx)}
+	if err := txRepo.q.EnsureUserPreferences(ctx, userID); err != nil {
+		return sqlc.UserPreference{}, err
+	}
+	pref, err := txRepo.q.GetPreferencesByUserIDForUpdate(ctx, userID)
+	if err != nil {
+		return sqlc.UserPreference{}, err
+	}
+	if err := update(&pref); err != nil {
+		return sqlc.UserPreference{}, err
+	}
+	pref.UserID = userID
+	saved, err := txRepo.UpsertPreferences(ctx, pref)
+	if err != nil {
+		return sqlc.UserPreference{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sqlc.UserPreference{}, err
+	}
+	return saved, nil
+}
+
 func (r *preferencesRepo) GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) (sqlc.UserPreference, error) {
 	return r.q.GetPreferencesByUserID(ctx, userID)
 }
+
+// TASKS
+
+type tasksRepo struct {
+	q  *sqlc.Queries
+	db dbpkg.DB
+}
+
+func (r *tasksRepo) CreateTask(ctx context.Context, task sqlc.Task, event sqlc.TaskEvent) (sqlc.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	params := sqlc.CreateTaskParams{
+		UserID:           task.UserID,
+		Title:            task.Title,
+		Description:      task.Description,
+		DueAt:            task.DueAt,
+		Category:         task.Category,
+		Priority:         task.Priority,
+		EstimatedMinutes: task.EstimatedMinutes,
+	}
+	created, err := q.CreateTask(ctx, params)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	if err := insertTaskEvent(ctx, q, created, event); err != nil {
+		return sqlc.Task{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sqlc.Task{}, err
+	}
+	return created, nil
+}
+
+func (r *tasksRepo) GetTask(ctx context.Context, id uuid.UUID) (sqlc.Task, error) {
+	return r.q.GetTask(ctx, id)
+}
+
+func (r *tasksRepo) ListTasksByUser(ctx context.Context, userID uuid.UUID, status string, limit int32, offset int32) ([]sqlc.Task, error) {
+	params := sqlc.ListTasksByUserParams{
+		UserID: userID,
+		Status: status,
+		Limit:  limit,
+		Offset: offset,
+	}
+	return r.q.ListTasksByUser(ctx, params)
+}
+
+func (r *tasksRepo) UpdateTask(ctx context.Context, id uuid.UUID, update func(*sqlc.Task) (*sqlc.TaskEvent, error)) (sqlc.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	task, err := q.GetTaskForUpdate(ctx, id)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	event, err := update(&task)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+
+	params := sqlc.UpdateTaskParams{
+		ID:               id,
+		Title:            task.Title,
+		Description:      task.Description,
+		Status:           task.Status,
+		DueAt:            task.DueAt,
+		Category:         task.Category,
+		Priority:         task.Priority,
+		EstimatedMinutes: task.EstimatedMinutes,
+		SnoozeCount:      task.SnoozeCount,
+	}
+	task, err = q.UpdateTask(ctx, params)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	if event != nil {
+		if err := insertTaskEvent(ctx, q, task, *event); err != nil {
+			return sqlc.Task{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sqlc.Task{}, err
+	}
+	return task, nil
+}
+
+func (r *tasksRepo) DeleteTask(ctx context.Context, id uuid.UUID, event sqlc.TaskEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+	task, err := q.SoftDeleteTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := insertTaskEvent(ctx, q, task, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertTaskEvent records the type and payload of event for task
+func insertTaskEvent(ctx context.Context, q *sqlc.Queries, task sqlc.Task, event sqlc.TaskEvent) error {
+	payload := event.EventPayload
+	if len(payload) == 0 {
+		payload = json.RawMessage("{}")
+	}
+	params := sqlc.InsertTaskEventParams{
+		TaskID:       task.ID,
+		UserID:       task.UserID,
+		EventType:    event.EventType,
+		EventPayload: payload,
+	}
+	_, err := q.InsertTaskEvent(ctx, params)
+	return err
+}
+
+// TASK EVENTS
+
+type taskEventsRepo struct{ q *sqlc.Queries }
+
+func (r *taskEventsRepo) ListTaskEventsByUser(ctx context.Context, userID uuid.UUID, types []string, since, until time.Time, limit int32, offset int32) ([]sqlc.TaskEvent, error) {
+	params := sqlc.ListTaskEventsByUserParams{
+		UserID:     userID,
+		EventTypes: append([]string{}, types...),
+		Since:      sql.NullTime{Time: since, Valid: !since.IsZero()},
+		Until:      sql.NullTime{Time: until, Valid: !until.IsZero()},
+		Limit:      limit,
+		Offset:     offset,
+	}
+	return r.q.ListTaskEventsByUser(ctx, params)
+}
+
+// FEATURE SNAPSHOTS
+
+type featureSnapshotsRepo struct{ q *sqlc.Queries }
+
+// task events are read in pages of this size
+const signalEventsPage = 1000
+
+func (r *featureSnapshotsRepo) ListActiveUsers(ctx context.Context, since time.Time, after uuid.UUID, limit int32) ([]uuid.UUID, error) {
+	params := sqlc.ListActiveUserIDsParams{
+		Since: since,
+		After: after,
+		Limit: limit,
+	}
+	return r.q.ListActiveUserIDs(ctx, params)
+}
+
+func (r *featureSnapshotsRepo) GetUserSignals(ctx context.Context, userID uuid.UUID, since, until time.Time) (repository.UserSignals, error) {
+	var signals repository.UserSignals
+	var err error
+	signals.SessionStarts, err = r.q.ListSessionStartsByUser(ctx, sqlc.ListSessionStartsByUserParams{
+		UserID: userID,
+		Since:  since,
+		Until:  until,
+	})
+	if err != nil {
+		return repository.UserSignals{}, err
+	}
+	signals.Entries, err = r.q.ListEntrySignalsByUser(ctx, sqlc.ListEntrySignalsByUserParams{
+		UserID: userID,
+		Since:  since,
+		Until:  until,
+	})
+	if err != nil {
+		return repository.UserSignals{}, err
+	}
+
+	params := sqlc.ListTaskEventsByUserParams{
+		UserID:     userID,
+		EventTypes: []string{},
+		Since:      sql.NullTime{Time: since, Valid: true},
+		Until:      sql.NullTime{Time: until, Valid: true},
+		Limit:      signalEventsPage,
+	}
+	for {
+		events, err := r.q.ListTaskEventsByUser(ctx, params)
+		if err != nil {
+			return repository.UserSignals{}, err
+		}
+		signals.TaskEvents = append(signals.TaskEvents, events...)
+		if len(events) < signalEventsPage {
+			return signals, nil
+		}
+		params.Offset += signalEventsPage
+	}
+}
+
+func (r *featureSnapshotsRepo) CreateFeatureSnapshot(ctx context.Context, snap sqlc.FeatureSnapshot) (sqlc.FeatureSnapshot, error) {
+	params := sqlc.CreateFeatureSnapshotParams{
+		UserID:               snap.UserID,
+		ProcrastinationScore: snap.ProcrastinationScore,
+		SnoozeRate:           snap.SnoozeRate,
+		CompletionLatencyAvg: snap.CompletionLatencyAvg,
+		BurstinessIndex:      snap.BurstinessIndex,
+		ModeCluster:          snap.ModeCluster,
+		ClusterVersion:       snap.ClusterVersion,
+		WindowStart:          snap.WindowStart,
+		WindowEnd:            snap.WindowEnd,
+	}
+	return r.q.CreateFeatureSnapshot(ctx, params)
+}
+
+func (r *featureSnapshotsRepo) GetLatestFeatureSnapshot(ctx context.Context, userID uuid.UUID) (sqlc.FeatureSnapshot, error) {
+	return r.q.GetLatestFeatureSnapshot(ctx, userID)
+}
+
+func (r *featureSnapshotsRepo) ListFeatureSnapshotsByUser(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]sqlc.FeatureSnapshot, error) {
+	params := sqlc.ListFeatureSnapshotsByUserParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	}
+	return r.q.ListFeatureSnapshotsByUser(ctx, params)
+}
+
+func (r *featureSnapshotsRepo) CreateClusterModel(ctx context.Context, centroids json.RawMessage, sampleSize int32) (sqlc.ClusterModel, error) {
+	params := sqlc.CreateClusterModelParams{
+		Centroids:  centroids,
+		SampleSize: sampleSize,
+	}
+	return r.q.CreateClusterModel(ctx, params)
+}
+
+func (r *featureSnapshotsRepo) GetLatestClusterModel(ctx context.Context) (sqlc.ClusterModel, error) {
+	return r.q.GetLatestClusterModel(ctx)
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"guiltmachine/internal/auth"
+	"guiltmachine/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrInvalidResourceID = errors.New("invalid resource id")
+)
+
+// Authorizer checks that the authenticated caller owns the resource being touched.
+// Missing resources are reported as ErrPermissionDenied so existence is not leaked.
+type Authorizer struct {
+	sessions repository.SessionsRepository
+	entries  repository.EntriesRepository
+	tasks    repository.TasksRepository
+}
+
+func NewAuthorizer(sessions repository.SessionsRepository, entries repository.EntriesRepository) *Authorizer {
+	return &Authorizer{sessions: sessions, entries: entries}
+}
+
+// SetTasks enables CanAccessTask; without it every task is denied
+func (a *Authorizer) SetTasks(tasks repository.TasksRepository) {
+	a.tasks = tasks
+}
+
+// CanAccessUser allows callers to act only on their own user record
+func (a *Authorizer) CanAccessUser(ctx context.Context, userID string) error {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return ErrInvalidResourceID
+	}
+
+	if uid != caller {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// CanAccessSession allows callers to act only on sessions they own
+func (a *Authorizer) CanAccessSession(ctx context.Context, sessionID string) error {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return ErrInvalidResourceID
+	}
+
+	return a.ownsSession(ctx, caller, sid)
+}
+
+// CanAccessEntry allows callers to act only on entries in sessions they own
+func (a *Authorizer) CanAccessEntry(ctx context.Context, entryID string) error {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	eid, err := uuid.Parse(entryID)
+	if err != nil {
+		return ErrInvalidResourceID
+	}
+
+	e, err := a.entries.GetEntry(ctx, eid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPermissionDenied
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.ownsSession(ctx, caller, e.SessionID)
+}
+
+// CanAccessTask allows callers to act only on their own tasks
+func (a *Authorizer) CanAccessTask(ctx context.Context, taskID string) error {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tid, err := uuid.Parse(taskID)
+	if err != nil {
+		return ErrInvalidResourceID
+	}
+	if a.tasks == nil {
+		return ErrPermissionDenied
+	}
+
+	t, err := a.tasks.GetTask(ctx, tid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPermissionDenied
+	}
+	if err != nil {
+		return err
+	}
+
+	if t.UserID != caller {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func (a *Authorizer) ownsSession(ctx context.Context, caller uuid.UUID, sessionID uuid.UUID) error {
+	sess, err := a.sessions.GetSessionByID(ctx, sessionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPermissionDenied
+	}
+	if err != nil {
+		return err
+	}
+
+	if sess.UserID != caller {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+func callerID(ctx context.Context) (uuid.UUID, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrPermissionDenied
+	}
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, ErrPermissionDenied
+	}
+	return uid, nil
+}
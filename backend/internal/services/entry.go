@@ -5,21 +5,48 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"log"
+	"time"
 
 	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/events"
 	"guiltmachine/internal/ml"
+	"guiltmachine/internal/outbox"
 	"guiltmachine/internal/queue"
 	"guiltmachine/internal/repository"
 
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrEntryBusy means another job holds the entry; the caller should retry later
+	ErrEntryBusy = errors.New("entry is being processed by another job")
+	// ErrWatchUnavailable means no event bus is configured for live updates
+	ErrWatchUnavailable = errors.New("live entry updates not configured")
+	// ErrWatchInterrupted means the event subscription broke; clients should watch again
+	ErrWatchInterrupted = errors.New("entry event subscription closed")
+)
+
 type EntryService struct {
 	repo         repository.EntriesRepository
 	scoresRepo   repository.ScoresRepository
 	orchestrator *ml.HybridOrchestrator
 	prefsService *PreferencesService
+	sessions     repository.SessionsRepository
 	queue        *queue.Producer
+	useOutbox    bool
+	events       events.Bus
+	history      HistoryConfig
+}
+
+// HistoryConfig controls how many earlier entries go into the LLM context
+type HistoryConfig struct {
+	// Entries is how many earlier entries to load; 0 disables history
+	Entries int
+	// AcrossSessions also loads entries from the user's other sessions
+	AcrossSessions bool
+	// MaxChars caps the history text, about four characters per token; 0 means no cap
+	MaxChars int
 }
 
 func NewEntryService(r repository.EntriesRepository) *EntryService {
@@ -44,12 +71,48 @@ func NewEntryServiceWithQueue(r repository.EntriesRepository, scoresRepo reposit
 	}
 }
 
+// NewEntryServiceWithOutbox writes the ML job to the outbox in the same
+// transaction as the entry; an outbox.Relay publishes it to the queue.
+// The orchestrator is optional and only serves StreamRoast.
+func NewEntryServiceWithOutbox(r repository.EntriesRepository, scoresRepo repository.ScoresRepository, orchestrator *ml.HybridOrchestrator, prefsService *PreferencesService) *EntryService {
+	return &EntryService{
+		repo:         r,
+		scoresRepo:   scoresRepo,
+		orchestrator: orchestrator,
+		prefsService: prefsService,
+		useOutbox:    true,
+	}
+}
+
+// SetEventBus makes ML processing publish entry changes and enables the Watch methods
+func (s *EntryService) SetEventBus(bus events.Bus) {
+	s.events = bus
+}
+
+// SetSessions lets the service resolve the user owning an entry, whose
+// preferences pick the persona and humor intensity of the roast
+func (s *EntryService) SetSessions(sessions repository.SessionsRepository) {
+	s.sessions = sessions
+}
+
+// SetHistory makes roasts call back to the user's earlier entries
+func (s *EntryService) SetHistory(cfg HistoryConfig) {
+	s.history = cfg
+}
+
 func (s *EntryService) CreateEntry(ctx context.Context, sessionID string, text string, level int32) (sqlc.GuiltEntry, error) {
 	sid, err := uuid.Parse(sessionID)
 	if err != nil {
 		return sqlc.GuiltEntry{}, errors.New("invalid session_id")
 	}
 
+	if s.useOutbox {
+		p := s.personalize(ctx, sid)
+		return s.repo.CreateEntryWithOutbox(ctx, sid, text, level, outbox.TopicEntryMLJob, func(e sqlc.GuiltEntry) ([]byte, error) {
+			return json.Marshal(newMLJob(e, p))
+		})
+	}
+
 	e, err := s.repo.CreateEntry(ctx, sid, text, level)
 	if err != nil {
 		return sqlc.GuiltEntry{}, err
@@ -57,36 +120,13 @@ func (s *EntryService) CreateEntry(ctx context.Context, sessionID string, text s
 
 	// If queue available, enqueue ML job asynchronously
 	if s.queue != nil {
-		job := queue.EntryMLJob{
-			EntryID:   e.ID.String(),
-			UserID:    sid.String(),
-			Text:      text,
-			Persona:   "roast",
-			Intensity: 3,
-		}
-		_ = s.queue.Enqueue(ctx, job)
+		// pending goes first so it cannot overwrite the status of a worker
+		// that picked the job up right away
 		_ = s.repo.UpdateEntryStatus(ctx, e.ID, "pending")
+		_ = s.queue.Enqueue(ctx, newMLJob(e, s.personalize(ctx, sid)))
 	} else if s.orchestrator != nil {
 		// Fallback to synchronous processing
-		// Default intensity and persona
-		intensity := 5
-		persona := ml.PersonaRoast
-
-		// Try to get user preferences if available
-		if s.prefsService != nil {
-			// Get the user_id from the session
-			// Note: This is a simplified approach; you may need to fetch user_id from session first
-			// For now, using default values
-		}
-
-		// Run hybrid orchestrator
-		output, err := s.orchestrator.Run(ctx, ml.HybridInput{
-			Text:      text,
-			UserID:    sid.String(),
-			Intensity: intensity,
-			Persona:   persona,
-			History:   []string{},
-		})
+		output, err := s.orchestrator.Run(ctx, s.mlInput(ctx, e))
 		if err != nil {
 			// Log error but don't fail entry creation
 			_ = err
@@ -102,7 +142,7 @@ func (s *EntryService) CreateEntry(ctx context.Context, sessionID string, text s
 			// Store the guilt score if scores repository available
 			if s.scoresRepo != nil {
 				score := int32(output.GuiltScore * 100) // Convert to 0-100 scale
-				_, _ = s.scoresRepo.CreateScore(ctx, sid, &e.ID, score, nil)
+				_, _ = s.scoresRepo.UpsertEntryScore(ctx, sid, e.ID, score, scoreMeta(output))
 			}
 		}
 	}
@@ -110,6 +150,19 @@ func (s *EntryService) CreateEntry(ctx context.Context, sessionID string, text s
 	return e, nil
 }
 
+// newMLJob describes the job for an entry. The worker resolves preferences
+// again when it runs, so a persona changed in the meantime still applies.
+func newMLJob(e sqlc.GuiltEntry, p personalization) queue.EntryMLJob {
+	return queue.EntryMLJob{
+		IdempotencyKey: uuid.NewString(),
+		EntryID:        e.ID.String(),
+		UserID:         p.userID,
+		Text:           e.EntryText,
+		Persona:        p.persona.String(),
+		Intensity:      p.intensity,
+	}
+}
+
 func (s *EntryService) ListEntries(ctx context.Context, sessionID string) ([]sqlc.GuiltEntry, error) {
 	sid, err := uuid.Parse(sessionID)
 	if err != nil {
@@ -153,74 +206,355 @@ func (s *EntryService) GetEntryScore(ctx context.Context, entryID string) (int32
 	return score.AggregateScore, nil
 }
 
-func (s *EntryService) ProcessMLJob(ctx context.Context, entryID string) error {
+// ProcessMLJob roasts and scores an entry. Running it again for the same job
+// is safe: the entry is claimed under jobKey first, a completed entry is
+// skipped, and the score is upserted so an entry never has two.
+func (s *EntryService) ProcessMLJob(ctx context.Context, entryID string, jobKey string) error {
+	eid, err := uuid.Parse(entryID)
+	if err != nil {
+		return errors.New("invalid entry_id")
+	}
+
+	// For now, use orchestrator if available, otherwise skip
+	if s.orchestrator == nil {
+		return nil
+	}
+
+	claimed, err := s.repo.ClaimEntry(ctx, eid, jobKey)
+	if err != nil {
+		return err
+	}
+
+	e, err := s.repo.GetEntry(ctx, eid)
+	if err != nil {
+		return err
+	}
+
+	if !claimed {
+		if e.Status.String == "processing" {
+			return ErrEntryBusy
+		}
+		log.Printf("ml job %s: entry %s is already %s, skipping", jobKey, e.ID, e.Status.String)
+		return nil
+	}
+	s.publish(ctx, e, "processing", "", 0)
+
+	out, err := s.orchestrator.Run(ctx, s.mlInput(ctx, e))
+	if err != nil {
+		s.fail(ctx, e, jobKey)
+		return err
+	}
+
+	return s.saveMLOutput(ctx, e, jobKey, out)
+}
+
+// StreamRoast generates the roast of an entry and hands it to emit while the
+// model produces it, then persists it like ProcessMLJob. An entry that is
+// already completed is not regenerated; its stored roast is returned as is.
+func (s *EntryService) StreamRoast(ctx context.Context, entryID string, emit func(string) error) (string, int32, error) {
+	eid, err := uuid.Parse(entryID)
+	if err != nil {
+		return "", 0, errors.New("invalid entry_id")
+	}
+	if s.orchestrator == nil {
+		return "", 0, errors.New("roast generation not configured")
+	}
+
+	// the queued job for this entry finds it completed and skips it
+	jobKey := "stream:" + uuid.NewString()
+	claimed, err := s.repo.ClaimEntry(ctx, eid, jobKey)
+	if err != nil {
+		return "", 0, err
+	}
+
+	e, err := s.repo.GetEntry(ctx, eid)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if !claimed {
+		if e.Status.String != "completed" {
+			return "", 0, ErrEntryBusy
+		}
+		score, _ := s.GetEntryScore(ctx, entryID)
+		return e.RoastText.String, score, nil
+	}
+	s.publish(ctx, e, "processing", "", 0)
+
+	out, err := s.orchestrator.RunStream(ctx, s.mlInput(ctx, e), emit)
+	if err != nil {
+		// a client hanging up fails the entry; the queued job retries it
+		s.fail(context.WithoutCancel(ctx), e, jobKey)
+		return "", 0, err
+	}
+
+	if err := s.saveMLOutput(context.WithoutCancel(ctx), e, jobKey, out); err != nil {
+		return "", 0, err
+	}
+	return out.RoastText, int32(out.GuiltScore * 100), nil
+}
+
+// mlInput builds the orchestrator input for an entry from its owner's preferences
+func (s *EntryService) mlInput(ctx context.Context, e sqlc.GuiltEntry) ml.HybridInput {
+	p := s.personalize(ctx, e.SessionID)
+
+	return ml.HybridInput{
+		Text:       e.EntryText,
+		UserID:     p.userID,
+		Intensity:  p.intensity,
+		Persona:    p.persona,
+		History:    s.entryHistory(ctx, e),
+		GuiltLevel: int(e.GuiltLevel.Int32),
+		At:         e.CreatedAt.In(p.location),
+	}
+}
+
+// personalization is who a roast is for and how they like it
+type personalization struct {
+	userID    string
+	persona   ml.Persona
+	intensity int
+	// location is the user's timezone, UTC when unknown
+	location *time.Location
+}
+
+// personalize resolves the user owning a session and their roast preferences.
+// Lookups only tune the roast, so failures fall back to the defaults.
+func (s *EntryService) personalize(ctx context.Context, sessionID uuid.UUID) personalization {
+	persona, _ := ml.ParsePersona(DefaultPersonalization.Persona)
+	p := personalization{persona: persona, intensity: DefaultPersonalization.HumorIntensity, location: time.UTC}
+	if s.sessions == nil {
+		return p
+	}
+
+	sess, err := s.sessions.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		log.Printf("resolve owner of session %s: %v", sessionID, err)
+		return p
+	}
+	p.userID = sess.UserID.String()
+	if s.prefsService == nil {
+		return p
+	}
+
+	rec, err := s.prefsService.Personalization(ctx, p.userID)
+	if err != nil {
+		log.Printf("load preferences of user %s: %v", p.userID, err)
+		return p
+	}
+	// unknown persona names get the neutral voice
+	p.persona, _ = ml.ParsePersona(rec.Persona)
+	p.intensity = rec.HumorIntensity
+	if loc, err := time.LoadLocation(rec.Timezone); err == nil {
+		p.location = loc
+	}
+	return p
+}
+
+// entryHistory loads the entries written before e with their roasts. History
+// only flavours the roast, so failures are logged and roasting goes on.
+func (s *EntryService) entryHistory(ctx context.Context, e sqlc.GuiltEntry) []string {
+	if s.history.Entries <= 0 {
+		return nil
+	}
+	earlier, err := s.repo.ListEntriesBefore(ctx, e.ID, s.history.AcrossSessions, int32(s.history.Entries))
+	if err != nil {
+		log.Printf("load history for entry %s: %v", e.ID, err)
+		return nil
+	}
+	items := make([]ml.HistoryItem, len(earlier))
+	for i, h := range earlier {
+		items[i] = ml.HistoryItem{Text: h.EntryText, Roast: h.RoastText.String}
+	}
+	return ml.FormatHistory(items, s.history.MaxChars)
+}
+
+// saveMLOutput stores the roast and score of an entry claimed under jobKey and
+// completes it. Errors leave the entry processing under jobKey, so a retry of
+// the same job can claim it again.
+func (s *EntryService) saveMLOutput(ctx context.Context, e sqlc.GuiltEntry, jobKey string, out *ml.HybridOutput) error {
+	roastText := sql.NullString{String: out.RoastText, Valid: true}
+	if err := s.repo.UpdateRoast(ctx, e.ID, roastText); err != nil {
+		return err
+	}
+
+	score := int32(out.GuiltScore * 100) // Convert to 0-100 scale
+	if s.scoresRepo != nil {
+		if _, err := s.scoresRepo.UpsertEntryScore(ctx, e.SessionID, e.ID, score, scoreMeta(out)); err != nil {
+			return err
+		}
+	}
+
+	finished, err := s.repo.FinishEntry(ctx, e.ID, jobKey, "completed")
+	if err != nil {
+		return err
+	}
+	if !finished {
+		log.Printf("ml job %s: lost the claim on entry %s before completing", jobKey, e.ID)
+		return nil
+	}
+	s.publish(ctx, e, "completed", out.RoastText, score)
+
+	return nil
+}
+
+// scoreMeta records how a score was produced: the scoring model version and
+// whether the roast came from a template while the LLM was down
+func scoreMeta(out *ml.HybridOutput) map[string]any {
+	return map[string]any{
+		"model_version": out.ModelVersion,
+		"source":        out.Source,
+		"tags":          out.Tags,
+	}
+}
+
+func (s *EntryService) fail(ctx context.Context, e sqlc.GuiltEntry, jobKey string) {
+	if finished, _ := s.repo.FinishEntry(ctx, e.ID, jobKey, "failed"); finished {
+		s.publish(ctx, e, "failed", "", 0)
+	}
+}
+
+// publish announces an entry change to watchers; the database stays the
+// source of truth, so failures are only logged
+func (s *EntryService) publish(ctx context.Context, e sqlc.GuiltEntry, status string, roast string, score int32) {
+	if s.events == nil {
+		return
+	}
+	ev := events.EntryEvent{
+		EntryID:    e.ID.String(),
+		SessionID:  e.SessionID.String(),
+		Status:     status,
+		RoastText:  roast,
+		GuiltScore: score,
+		At:         time.Now(),
+	}
+	if err := s.events.PublishEntry(ctx, ev); err != nil {
+		log.Printf("publish %s event for entry %s: %v", status, e.ID, err)
+	}
+}
+
+// WatchEntry sends the entry's current state, then every change to it,
+// until the entry completes or fails or ctx is done. A failed entry that is
+// retried later can be watched again.
+func (s *EntryService) WatchEntry(ctx context.Context, entryID string, send func(events.EntryEvent) error) error {
 	eid, err := uuid.Parse(entryID)
 	if err != nil {
 		return errors.New("invalid entry_id")
 	}
+	if s.events == nil {
+		return ErrWatchUnavailable
+	}
 
 	e, err := s.repo.GetEntry(ctx, eid)
 	if err != nil {
 		return err
 	}
 
-	var intensity = 3
-	var persona = ml.PersonaRoast
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// subscribe before reading the snapshot so no change falls in between
+	evs, err := s.events.SubscribeSession(ctx, e.SessionID.String())
+	if err != nil {
+		return err
+	}
 
-	if s.prefsService != nil {
-		prefs, _ := s.prefsService.GetPreferences(ctx, e.SessionID.String())
-		if prefs.Metadata.Valid {
-			// Parse metadata for intensity and persona if available
-			var meta map[string]interface{}
-			_ = json.Unmarshal(prefs.Metadata.RawMessage, &meta)
-			if val, ok := meta["humor_intensity"]; ok {
-				if fval, ok := val.(float64); ok {
-					intensity = int(fval)
-				}
+	snapshot, err := s.snapshot(ctx, eid)
+	if err != nil {
+		return err
+	}
+	if err := send(snapshot); err != nil {
+		return err
+	}
+	if finalStatus(snapshot.Status) {
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-evs:
+			if !ok {
+				return ErrWatchInterrupted
+			}
+			if ev.EntryID != snapshot.EntryID {
+				continue
+			}
+			if err := send(ev); err != nil {
+				return err
 			}
-			if val, ok := meta["persona"]; ok {
-				if sval, ok := val.(string); ok {
-					switch sval {
-					case "roast":
-						persona = ml.PersonaRoast
-					case "coach":
-						persona = ml.PersonaCoach
-					case "chill":
-						persona = ml.PersonaChill
-					default:
-						persona = ml.PersonaNeutral
-					}
-				}
+			if finalStatus(ev.Status) {
+				return nil
 			}
 		}
 	}
+}
 
-	// For now, use orchestrator if available, otherwise skip
-	if s.orchestrator != nil {
-		out, err := s.orchestrator.Run(ctx, ml.HybridInput{
-			Text:      e.EntryText,
-			UserID:    e.SessionID.String(),
-			Intensity: intensity,
-			Persona:   persona,
-		})
-		if err != nil {
-			_ = s.repo.UpdateEntryStatus(ctx, e.ID, "failed")
+// finalStatus reports whether processing of an entry in status has ended
+func finalStatus(status string) bool {
+	return status == "completed" || status == "failed"
+}
+
+// WatchSession sends the current state of every entry in the session, then
+// every change to them, until ctx is done
+func (s *EntryService) WatchSession(ctx context.Context, sessionID string, send func(events.EntryEvent) error) error {
+	sid, err := uuid.Parse(sessionID)
+	if err != nil {
+		return errors.New("invalid session_id")
+	}
+	if s.events == nil {
+		return ErrWatchUnavailable
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	evs, err := s.events.SubscribeSession(ctx, sid.String())
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.repo.ListEntriesBySession(ctx, sid)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		score, _ := s.GetEntryScore(ctx, e.ID.String())
+		if err := send(entryEvent(e, score)); err != nil {
 			return err
 		}
+	}
 
-		// Update roast text
-		roastText := sql.NullString{String: out.RoastText, Valid: true}
-		_ = s.repo.UpdateRoast(ctx, e.ID, roastText)
-
-		// Create score with entry_id
-		if s.scoresRepo != nil {
-			score := int32(out.GuiltScore * 100) // Convert to 0-100 scale
-			_, _ = s.scoresRepo.CreateScore(ctx, e.SessionID, &e.ID, score, nil)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-evs:
+			if !ok {
+				return ErrWatchInterrupted
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
 		}
+	}
+}
 
-		// Mark as completed
-		_ = s.repo.UpdateEntryStatus(ctx, e.ID, "completed")
+func (s *EntryService) snapshot(ctx context.Context, eid uuid.UUID) (events.EntryEvent, error) {
+	e, err := s.repo.GetEntry(ctx, eid)
+	if err != nil {
+		return events.EntryEvent{}, err
 	}
+	score, _ := s.GetEntryScore(ctx, eid.String())
+	return entryEvent(e, score), nil
+}
 
-	return nil
+func entryEvent(e sqlc.GuiltEntry, score int32) events.EntryEvent {
+	return events.EntryEvent{
+		EntryID:    e.ID.String(),
+		SessionID:  e.SessionID.String(),
+		Status:     e.Status.String,
+		RoastText:  e.RoastText.String,
+		GuiltScore: score,
+		At:         e.UpdatedAt,
+	}
 }
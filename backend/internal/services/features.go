@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidInsights wraps every validation failure of an insights request
+var ErrInvalidInsights = errors.New("invalid insights request")
+
+// FeatureConfig controls the feature snapshot batch job
+type FeatureConfig struct {
+	// Window is how far back a snapshot looks; users without activity in it
+	// get no snapshot
+	Window time.Duration
+	// BatchSize is how many users are loaded at a time
+	BatchSize int32
+	// MinTrainUsers is how many active users a run needs to retrain the
+	// behavior mode clusters; smaller runs keep the current model
+	MinTrainUsers int
+}
+
+// DefaultFeatureConfig looks back 30 days
+var DefaultFeatureConfig = FeatureConfig{
+	Window:        30 * 24 * time.Hour,
+	BatchSize:     100,
+	MinTrainUsers: 30,
+}
+
+const (
+	defaultSnapshotListLimit = 30
+	maxSnapshotListLimit     = 365
+)
+
+// SnapshotRun reports a batch run over all active users
+type SnapshotRun struct {
+	Snapshots int
+	Failures  int
+	// ClusterVersion is the cluster model the run assigned modes with,
+	// Trained whether the run trained it
+	ClusterVersion int32
+	Trained        bool
+}
+
+// userFeatures are a user's features waiting for a mode
+type userFeatures struct {
+	userID   uuid.UUID
+	features ml.BehaviorFeatures
+	active   bool
+}
+
+// FeatureService computes behavioral feature snapshots and serves them to
+// the insights screen
+type FeatureService struct {
+	repo repository.FeatureSnapshotsRepository
+	cfg  FeatureConfig
+}
+
+func NewFeatureService(r repository.FeatureSnapshotsRepository, cfg FeatureConfig) *FeatureService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultFeatureConfig.BatchSize
+	}
+	if cfg.MinTrainUsers <= 0 {
+		cfg.MinTrainUsers = DefaultFeatureConfig.MinTrainUsers
+	}
+	return &FeatureService{repo: r, cfg: cfg}
+}
+
+// SnapshotAll snapshots every user active in the window ending at now. It
+// computes everyone's features first, retrains the behavior mode clusters on
+// them when there are at least MinTrainUsers, and then stores the snapshots
+// with the modes of the newest model. A user whose snapshot fails is logged
+// and skipped; only failing to list users or a cancelled ctx ends the run
+// early.
+func (s *FeatureService) SnapshotAll(ctx context.Context, now time.Time) (SnapshotRun, error) {
+	var run SnapshotRun
+	since := now.Add(-s.cfg.Window)
+
+	var users []userFeatures
+	var vectors [][]float64
+	after := uuid.Nil
+	for {
+		ids, err := s.repo.ListActiveUsers(ctx, since, after, s.cfg.BatchSize)
+		if err != nil {
+			return run, err
+		}
+		for _, userID := range ids {
+			if err := ctx.Err(); err != nil {
+				return run, err
+			}
+			u, err := s.features(ctx, userID, since, now)
+			if err != nil {
+				log.Printf("compute features of user %s: %v", userID, err)
+				run.Failures++
+				continue
+			}
+			users = append(users, u)
+			if u.active {
+				vectors = append(vectors, u.features.Vector())
+			}
+		}
+		if int32(len(ids)) < s.cfg.BatchSize {
+			break
+		}
+		after = ids[len(ids)-1]
+	}
+
+	model, err := s.clusterModel(ctx)
+	if err != nil {
+		return run, err
+	}
+	if len(vectors) >= s.cfg.MinTrainUsers {
+		trained, err := s.train(ctx, model, vectors)
+		if err != nil {
+			log.Printf("train cluster model on %d users, keeping version %d: %v", len(vectors), model.Version, err)
+		} else {
+			model, run.Trained = trained, true
+		}
+	}
+	run.ClusterVersion = model.Version
+
+	for _, u := range users {
+		if err := ctx.Err(); err != nil {
+			return run, err
+		}
+		if _, err := s.store(ctx, u, model, since, now); err != nil {
+			log.Printf("snapshot features of user %s: %v", u.userID, err)
+			run.Failures++
+			continue
+		}
+		run.Snapshots++
+	}
+	return run, nil
+}
+
+// SnapshotUser computes a user's features over the window ending at now,
+// assigns the mode with the current cluster model and stores them
+func (s *FeatureService) SnapshotUser(ctx context.Context, userID uuid.UUID, now time.Time) (sqlc.FeatureSnapshot, error) {
+	since := now.Add(-s.cfg.Window)
+	u, err := s.features(ctx, userID, since, now)
+	if err != nil {
+		return sqlc.FeatureSnapshot{}, err
+	}
+	model, err := s.clusterModel(ctx)
+	if err != nil {
+		return sqlc.FeatureSnapshot{}, err
+	}
+	return s.store(ctx, u, model, since, now)
+}
+
+// BehaviorMode is the mode of a user's latest snapshot, assigned online like
+// in GetLatestFeatureSnapshot for users the batch job has not reached yet.
+// Snooze roasts key their tone off it, e.g. with ml.ModeName.
+func (s *FeatureService) BehaviorMode(ctx context.Context, userID uuid.UUID) (int, error) {
+	snap, err := s.latestSnapshot(ctx, userID)
+	if err != nil {
+		return ml.ModeIdle, err
+	}
+	return int(snap.ModeCluster), nil
+}
+
+// GetLatestFeatureSnapshot returns a user's newest snapshot. Users the batch
+// job has not reached yet, such as new ones, get one computed on the spot;
+// it is not stored and has no ID, storing is left to the batch job.
+func (s *FeatureService) GetLatestFeatureSnapshot(ctx context.Context, userID string) (sqlc.FeatureSnapshot, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return sqlc.FeatureSnapshot{}, fmt.Errorf("%w: invalid user_id", ErrInvalidInsights)
+	}
+	return s.latestSnapshot(ctx, uid)
+}
+
+// latestSnapshot is the newest stored snapshot of a user, or one computed
+// now if there is none
+func (s *FeatureService) latestSnapshot(ctx context.Context, userID uuid.UUID) (sqlc.FeatureSnapshot, error) {
+	snap, err := s.repo.GetLatestFeatureSnapshot(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.computeSnapshot(ctx, userID, time.Now())
+	}
+	return snap, err
+}
+
+// computeSnapshot is the snapshot SnapshotUser would store, without storing it
+func (s *FeatureService) computeSnapshot(ctx context.Context, userID uuid.UUID, now time.Time) (sqlc.FeatureSnapshot, error) {
+	since := now.Add(-s.cfg.Window)
+	u, err := s.features(ctx, userID, since, now)
+	if err != nil {
+		return sqlc.FeatureSnapshot{}, err
+	}
+	model, err := s.clusterModel(ctx)
+	if err != nil {
+		return sqlc.FeatureSnapshot{}, err
+	}
+	snap := snapshotOf(u, model, since, now)
+	snap.CreatedAt = now
+	return snap, nil
+}
+
+// ListFeatureSnapshots pages through a user's snapshots, newest first
+func (s *FeatureService) ListFeatureSnapshots(ctx context.Context, userID string, limit, offset int32) ([]sqlc.FeatureSnapshot, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user_id", ErrInvalidInsights)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset must be >= 0", ErrInvalidInsights)
+	}
+	switch {
+	case limit <= 0:
+		limit = defaultSnapshotListLimit
+	case limit > maxSnapshotListLimit:
+		limit = maxSnapshotListLimit
+	}
+	return s.repo.ListFeatureSnapshotsByUser(ctx, uid, limit, offset)
+}
+
+func (s *FeatureService) features(ctx context.Context, userID uuid.UUID, since, until time.Time) (userFeatures, error) {
+	signals, err := s.repo.GetUserSignals(ctx, userID, since, until)
+	if err != nil {
+		return userFeatures{}, err
+	}
+	in := behaviorSignals(signals)
+	return userFeatures{
+		userID:   userID,
+		features: ml.ComputeBehaviorFeatures(in),
+		active:   len(in.Activity) > 0,
+	}, nil
+}
+
+// store saves u with the mode model assigns it
+func (s *FeatureService) store(ctx context.Context, u userFeatures, model *ml.ClusterModel, since, until time.Time) (sqlc.FeatureSnapshot, error) {
+	return s.repo.CreateFeatureSnapshot(ctx, snapshotOf(u, model, since, until))
+}
+
+// snapshotOf is u with the mode model assigns it; inactive users stay idle
+func snapshotOf(u userFeatures, model *ml.ClusterModel, since, until time.Time) sqlc.FeatureSnapshot {
+	f := u.features
+	if u.active {
+		f.ModeCluster = model.Mode(f)
+	}
+	return sqlc.FeatureSnapshot{
+		UserID:               u.userID,
+		ProcrastinationScore: f.ProcrastinationScore,
+		SnoozeRate:           f.SnoozeRate,
+		CompletionLatencyAvg: f.CompletionLatencyAvg,
+		BurstinessIndex:      f.BurstinessIndex,
+		ModeCluster:          int32(f.ModeCluster),
+		ClusterVersion:       model.Version,
+		WindowStart:          since,
+		WindowEnd:            until,
+	}
+}
+
+func (s *FeatureService) clusterModel(ctx context.Context) (*ml.ClusterModel, error) {
+	row, err := s.repo.GetLatestClusterModel(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ml.DefaultClusterModel(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ml.ParseCentroids(row.Version, row.Centroids)
+}
+
+// train fits a model starting from prev and stores it as the next version
+func (s *FeatureService) train(ctx context.Context, prev *ml.ClusterModel, vectors [][]float64) (*ml.ClusterModel, error) {
+	model, err := ml.TrainClusterModel(prev, vectors)
+	if err != nil {
+		return nil, err
+	}
+	centroids, err := json.Marshal(model.Centroids)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.repo.CreateClusterModel(ctx, centroids, int32(len(vectors)))
+	if err != nil {
+		return nil, err
+	}
+	model.Version = row.Version
+	return model, nil
+}
+
+// behaviorSignals reads the model's inputs out of the stored rows. Entries
+// not scored yet count as activity only.
+func behaviorSignals(u repository.UserSignals) ml.BehaviorSignals {
+	var s ml.BehaviorSignals
+	s.Activity = append(s.Activity, u.SessionStarts...)
+	for _, e := range u.Entries {
+		s.Activity = append(s.Activity, e.CreatedAt)
+		if e.AggregateScore.Valid {
+			s.GuiltScores = append(s.GuiltScores, float64(e.AggregateScore.Int32)/100)
+		}
+	}
+	for _, e := range u.TaskEvents {
+		s.Activity = append(s.Activity, e.CreatedAt)
+		switch e.EventType {
+		case TaskEventSnooze:
+			s.Snoozes++
+		case TaskEventComplete:
+			var p TaskCompletedPayload
+			if err := json.Unmarshal(e.EventPayload, &p); err != nil {
+				log.Printf("skip complete event %s: %v", e.ID, err)
+				continue
+			}
+			s.CompletionMinutes = append(s.CompletionMinutes, float64(max(p.OpenMinutes, 0)))
+			if p.OverdueMinutes != nil {
+				s.DatedCompletions++
+				if *p.OverdueMinutes > 0 {
+					s.OverdueCompletions++
+				}
+			}
+		}
+	}
+	return s
+}
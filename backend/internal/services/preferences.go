@@ -2,54 +2,476 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"slices"
+	"sync/atomic"
+	"time"
+	_ "time/tzdata" // timezone validation must not depend on the host's zoneinfo
 
 	cacheDomain "guiltmachine/internal/cache/domain"
 	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/ml"
 	"guiltmachine/internal/repository"
 
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/sync/singleflight"
 )
 
+// ErrInvalidPreferences wraps every validation failure of a preferences write
+var ErrInvalidPreferences = errors.New("invalid preferences")
+
+// DefaultPersonalization applies to users who have not set persona or humor intensity
+var DefaultPersonalization = cacheDomain.PreferencesRecord{
+	HumorIntensity: 3,
+	Persona:        "roast",
+	Timezone:       "UTC",
+}
+
+// nudge channels a user can opt into
+const (
+	NudgePush  = "push"
+	NudgeEmail = "email"
+	NudgeSMS   = "sms"
+	NudgeInApp = "in_app"
+)
+
+// PreferencesPaths are the field names UpdatePreferences accepts, the same as
+// the fields of the Preferences proto message
+var PreferencesPaths = []string{
+	"theme",
+	"notifications",
+	"persona",
+	"humor_intensity",
+	"timezone",
+	"work_hours",
+	"quiet_hours",
+	"language",
+	"nudge_channels",
+}
+
+// metadataPath updates the free-form metadata; it is not part of the public mask
+const metadataPath = "metadata"
+
+const minutesPerDay = 24 * 60
+
+var languageTag = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
 type PreferencesService struct {
 	repo  repository.PreferencesRepository
 	cache *cacheDomain.PreferencesCache
+	// concurrent cache misses for a user share one database read
+	loads singleflight.Group
+	// changes counts the preference changes seen in any process, so loads
+	// that overlap one do not leave what they read in the cache
+	changes atomic.Uint64
 }
 
 func NewPreferencesService(r repository.PreferencesRepository, c *cacheDomain.PreferencesCache) *PreferencesService {
 	return &PreferencesService{repo: r, cache: c}
 }
 
+// UpsertPreferences sets theme, notifications and the free-form metadata.
+// Persona, humor_intensity, timezone and language keys in metadata set the
+// typed fields instead; other typed fields keep their values.
 func (s *PreferencesService) UpsertPreferences(ctx context.Context, userID string, theme *string, notifications bool, metadata string) (sqlc.UserPreference, error) {
-	uid, err := uuid.Parse(userID)
+	prefs := sqlc.UserPreference{NotificationsEnabled: notifications}
+	if theme != nil {
+		prefs.Theme = sql.NullString{String: *theme, Valid: true}
+	}
+	return s.upsertWithMetadata(ctx, userID, prefs, []string{"theme", "notifications"}, metadata)
+}
+
+// ReplacePreferences sets all typed fields from prefs and the free-form
+// metadata. Typed keys in metadata are dropped, the typed fields win.
+func (s *PreferencesService) ReplacePreferences(ctx context.Context, userID string, prefs sqlc.UserPreference, metadata string) (sqlc.UserPreference, error) {
+	return s.upsertWithMetadata(ctx, userID, prefs, PreferencesPaths, metadata)
+}
+
+// UpdatePreferences copies the fields named in paths from prefs and keeps the
+// others. A named field left empty in prefs is reset to its default.
+func (s *PreferencesService) UpdatePreferences(ctx context.Context, userID string, prefs sqlc.UserPreference, paths []string) (sqlc.UserPreference, error) {
+	if len(paths) == 0 {
+		return sqlc.UserPreference{}, fmt.Errorf("%w: update_mask required", ErrInvalidPreferences)
+	}
+	for _, path := range paths {
+		if !slices.Contains(PreferencesPaths, path) {
+			return sqlc.UserPreference{}, fmt.Errorf("%w: unknown field %q in update_mask", ErrInvalidPreferences, path)
+		}
+	}
+	return s.update(ctx, userID, prefs, paths)
+}
+
+func (s *PreferencesService) upsertWithMetadata(ctx context.Context, userID string, prefs sqlc.UserPreference, paths []string, metadata string) (sqlc.UserPreference, error) {
+	typed, typedPaths, extras, err := splitMetadata(metadata)
 	if err != nil {
-		return sqlc.UserPreference{}, errors.New("invalid user_id")
+		return sqlc.UserPreference{}, err
+	}
+	for _, path := range typedPaths {
+		if !slices.Contains(paths, path) {
+			copyPreference(&prefs, typed, path)
+			paths = append(paths, path)
+		}
 	}
+	prefs.Metadata = extras
+	return s.update(ctx, userID, prefs, append(paths, metadataPath))
+}
 
-	var decoded any
-	if metadata != "" {
-		_ = json.Unmarshal([]byte(metadata), &decoded)
+func (s *PreferencesService) update(ctx context.Context, userID string, prefs sqlc.UserPreference, paths []string) (sqlc.UserPreference, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return sqlc.UserPreference{}, fmt.Errorf("%w: invalid user_id", ErrInvalidPreferences)
 	}
 
-	pref, err := s.repo.UpsertPreferences(ctx, uid, theme, notifications, decoded)
+	pref, err := s.repo.UpdatePreferences(ctx, uid, func(cur *sqlc.UserPreference) error {
+		for _, path := range paths {
+			copyPreference(cur, prefs, path)
+		}
+		normalizePreferences(cur)
+		return validatePreferences(*cur)
+	})
 	if err != nil {
 		return sqlc.UserPreference{}, err
 	}
 
+	// the next roast must not see the old persona. Writing the record
+	// instead could race another update and cache the older one.
+	if s.cache != nil {
+		s.changed(userID)
+		if err := s.cache.InvalidatePreferences(ctx, userID); err != nil {
+			log.Printf("invalidate cached preferences of %s: %v", userID, err)
+		}
+	}
+
 	return pref, nil
 }
 
+// GetPreferences reads through the preferences cache. Users without
+// preferences get sql.ErrNoRows, which is cached as well.
 func (s *PreferencesService) GetPreferences(ctx context.Context, userID string) (sqlc.UserPreference, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return sqlc.UserPreference{}, errors.New("invalid user_id")
 	}
+	if s.cache == nil {
+		return s.repo.GetPreferencesByUserID(ctx, uid)
+	}
+
+	rec, ok, err := s.cache.GetPreferences(ctx, userID)
+	if err != nil {
+		log.Printf("read cached preferences of %s: %v", userID, err)
+	}
+	if ok {
+		return preferenceOf(uid, rec)
+	}
+
+	// the load outlives a caller that gives up, others may be waiting on it
+	loaded := s.loads.DoChan(userID, func() (any, error) {
+		return s.loadPreferences(context.WithoutCancel(ctx), uid)
+	})
+	select {
+	case <-ctx.Done():
+		return sqlc.UserPreference{}, ctx.Err()
+	case res := <-loaded:
+		if res.Err != nil {
+			return sqlc.UserPreference{}, res.Err
+		}
+		return preferenceOf(uid, res.Val.(cacheDomain.PreferencesRecord))
+	}
+}
 
+// loadPreferences reads a user's preferences from the database into the cache
+func (s *PreferencesService) loadPreferences(ctx context.Context, uid uuid.UUID) (cacheDomain.PreferencesRecord, error) {
+	userID := uid.String()
+	seen := s.changes.Load()
 	pref, err := s.repo.GetPreferencesByUserID(ctx, uid)
+	rec := cacheDomain.PreferencesRecord{}
+	switch {
+	case err == nil:
+		rec = preferencesRecord(pref)
+	case !errors.Is(err, sql.ErrNoRows):
+		return cacheDomain.PreferencesRecord{}, err
+	}
+
+	// what we read may predate a change made since; only fill an empty cache
+	// and take the record back out if a change raced the fill
+	if s.changes.Load() != seen {
+		return rec, nil
+	}
+	if _, err := s.cache.FillPreferences(ctx, userID, rec); err != nil {
+		log.Printf("cache preferences of %s: %v", userID, err)
+	}
+	if s.changes.Load() != seen {
+		s.evict(ctx, userID)
+	}
+	return rec, nil
+}
+
+// ListenInvalidations follows preference changes made by any process until
+// ctx is done. Reads after a change do not join a load started before it,
+// and records this process filled from such a load are dropped.
+func (s *PreferencesService) ListenInvalidations(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.ListenInvalidations(ctx, func(userID string) {
+		s.changed(userID)
+		s.evict(ctx, userID)
+	})
+}
+
+// changed makes loads of userID's preferences in flight stale
+func (s *PreferencesService) changed(userID string) {
+	s.changes.Add(1)
+	s.loads.Forget(userID)
+}
+
+// evict drops a cached record that may be stale without announcing a change
+func (s *PreferencesService) evict(ctx context.Context, userID string) {
+	if err := s.cache.EvictPreferences(ctx, userID); err != nil {
+		log.Printf("evict cached preferences of %s: %v", userID, err)
+	}
+}
+
+// Personalization returns the persona and humor intensity roasts for a user
+// should use; users without preferences get DefaultPersonalization
+func (s *PreferencesService) Personalization(ctx context.Context, userID string) (cacheDomain.PreferencesRecord, error) {
+	pref, err := s.GetPreferences(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultPersonalization, nil
+	}
 	if err != nil {
-		return sqlc.UserPreference{}, err
+		return DefaultPersonalization, err
+	}
+	return preferencesRecord(pref), nil
+}
+
+// preferencesRecord is the cached form of pref
+func preferencesRecord(pref sqlc.UserPreference) cacheDomain.PreferencesRecord {
+	rec := cacheDomain.PreferencesRecord{
+		Found:            true,
+		ID:               pref.ID.String(),
+		Notifications:    pref.NotificationsEnabled,
+		HumorIntensity:   int(pref.HumorIntensity),
+		Persona:          pref.Persona,
+		Timezone:         pref.Timezone,
+		Language:         pref.Language,
+		WorkStartMinute:  nullInt32Ptr(pref.WorkStartMinute),
+		WorkEndMinute:    nullInt32Ptr(pref.WorkEndMinute),
+		QuietStartMinute: nullInt32Ptr(pref.QuietStartMinute),
+		QuietEndMinute:   nullInt32Ptr(pref.QuietEndMinute),
+		NudgeChannels:    append([]string{}, pref.NudgeChannels...),
+		CreatedAt:        pref.CreatedAt,
+		UpdatedAt:        pref.UpdatedAt,
+	}
+	if pref.Theme.Valid {
+		theme := pref.Theme.String
+		rec.Theme = &theme
 	}
+	if pref.Metadata.Valid {
+		rec.Metadata = append(json.RawMessage(nil), pref.Metadata.RawMessage...)
+	}
+	return rec
+}
 
+// preferenceOf turns a cached record back into the stored preferences, or
+// sql.ErrNoRows for a user without any
+func preferenceOf(uid uuid.UUID, rec cacheDomain.PreferencesRecord) (sqlc.UserPreference, error) {
+	if !rec.Found {
+		return sqlc.UserPreference{}, sql.ErrNoRows
+	}
+	id, err := uuid.Parse(rec.ID)
+	if err != nil {
+		return sqlc.UserPreference{}, fmt.Errorf("cached preferences of %s: %w", uid, err)
+	}
+	pref := sqlc.UserPreference{
+		ID:                   id,
+		UserID:               uid,
+		NotificationsEnabled: rec.Notifications,
+		CreatedAt:            rec.CreatedAt,
+		UpdatedAt:            rec.UpdatedAt,
+		Persona:              rec.Persona,
+		HumorIntensity:       int32(rec.HumorIntensity),
+		Timezone:             rec.Timezone,
+		Language:             rec.Language,
+		WorkStartMinute:      ptrNullInt32(rec.WorkStartMinute),
+		WorkEndMinute:        ptrNullInt32(rec.WorkEndMinute),
+		QuietStartMinute:     ptrNullInt32(rec.QuietStartMinute),
+		QuietEndMinute:       ptrNullInt32(rec.QuietEndMinute),
+		NudgeChannels:        append([]string{}, rec.NudgeChannels...),
+	}
+	if rec.Theme != nil {
+		pref.Theme = sql.NullString{String: *rec.Theme, Valid: true}
+	}
+	if rec.Metadata != nil {
+		pref.Metadata = pqtype.NullRawMessage{RawMessage: append(json.RawMessage(nil), rec.Metadata...), Valid: true}
+	}
 	return pref, nil
 }
+
+func nullInt32Ptr(n sql.NullInt32) *int32 {
+	if !n.Valid {
+		return nil
+	}
+	v := n.Int32
+	return &v
+}
+
+func ptrNullInt32(p *int32) sql.NullInt32 {
+	if p == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *p, Valid: true}
+}
+
+func copyPreference(dst *sqlc.UserPreference, src sqlc.UserPreference, path string) {
+	switch path {
+	case "theme":
+		dst.Theme = src.Theme
+	case "notifications":
+		dst.NotificationsEnabled = src.NotificationsEnabled
+	case "persona":
+		dst.Persona = src.Persona
+	case "humor_intensity":
+		dst.HumorIntensity = src.HumorIntensity
+	case "timezone":
+		dst.Timezone = src.Timezone
+	case "work_hours":
+		dst.WorkStartMinute, dst.WorkEndMinute = src.WorkStartMinute, src.WorkEndMinute
+	case "quiet_hours":
+		dst.QuietStartMinute, dst.QuietEndMinute = src.QuietStartMinute, src.QuietEndMinute
+	case "language":
+		dst.Language = src.Language
+	case "nudge_channels":
+		dst.NudgeChannels = append([]string{}, src.NudgeChannels...)
+	case metadataPath:
+		dst.Metadata = src.Metadata
+	}
+}
+
+// normalizePreferences fills in defaults for empty fields and drops
+// duplicate nudge channels
+func normalizePreferences(p *sqlc.UserPreference) {
+	if p.Theme.Valid && p.Theme.String == "" {
+		p.Theme = sql.NullString{}
+	}
+	if p.Persona == "" {
+		p.Persona = DefaultPersonalization.Persona
+	}
+	if p.Timezone == "" {
+		p.Timezone = DefaultPersonalization.Timezone
+	}
+	if p.Language == "" {
+		p.Language = "en"
+	}
+	channels := []string{}
+	for _, c := range p.NudgeChannels {
+		if !slices.Contains(channels, c) {
+			channels = append(channels, c)
+		}
+	}
+	p.NudgeChannels = channels
+}
+
+func validatePreferences(p sqlc.UserPreference) error {
+	if _, ok := ml.ParsePersona(p.Persona); !ok {
+		return fmt.Errorf("%w: unknown persona %q", ErrInvalidPreferences, p.Persona)
+	}
+	if p.HumorIntensity < 0 || p.HumorIntensity > 10 {
+		return fmt.Errorf("%w: humor_intensity must be between 0 and 10", ErrInvalidPreferences)
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "Local" {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidPreferences, p.Timezone)
+	}
+	if !languageTag.MatchString(p.Language) {
+		return fmt.Errorf("%w: language %q is not a BCP 47 tag", ErrInvalidPreferences, p.Language)
+	}
+	if err := validateMinuteRange("work_hours", p.WorkStartMinute, p.WorkEndMinute); err != nil {
+		return err
+	}
+	if err := validateMinuteRange("quiet_hours", p.QuietStartMinute, p.QuietEndMinute); err != nil {
+		return err
+	}
+	for _, c := range p.NudgeChannels {
+		switch c {
+		case NudgePush, NudgeEmail, NudgeSMS, NudgeInApp:
+		default:
+			return fmt.Errorf("%w: unknown nudge channel %q", ErrInvalidPreferences, c)
+		}
+	}
+	return nil
+}
+
+func validateMinuteRange(name string, start, end sql.NullInt32) error {
+	if !start.Valid && !end.Valid {
+		return nil
+	}
+	if start.Valid != end.Valid {
+		return fmt.Errorf("%w: %s needs both start and end", ErrInvalidPreferences, name)
+	}
+	for _, m := range []int32{start.Int32, end.Int32} {
+		if m < 0 || m >= minutesPerDay {
+			return fmt.Errorf("%w: %s must be within a day", ErrInvalidPreferences, name)
+		}
+	}
+	if start.Int32 == end.Int32 {
+		return fmt.Errorf("%w: %s must not be empty", ErrInvalidPreferences, name)
+	}
+	return nil
+}
+
+// splitMetadata parses metadata JSON and moves the keys that have typed
+// fields out of it. It returns those fields in typed with their paths, and
+// the remaining metadata.
+func splitMetadata(metadata string) (typed sqlc.UserPreference, paths []string, extras pqtype.NullRawMessage, err error) {
+	if metadata == "" {
+		return typed, nil, extras, nil
+	}
+	if !json.Valid([]byte(metadata)) {
+		return typed, nil, extras, fmt.Errorf("%w: metadata_json is not valid JSON", ErrInvalidPreferences)
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal([]byte(metadata), &fields) != nil {
+		// not an object, nothing to move
+		return typed, nil, pqtype.NullRawMessage{RawMessage: json.RawMessage(metadata), Valid: true}, nil
+	}
+
+	var intensity float64
+	targets := map[string]any{
+		"persona":         &typed.Persona,
+		"humor_intensity": &intensity,
+		"timezone":        &typed.Timezone,
+		"language":        &typed.Language,
+	}
+	for _, key := range []string{"persona", "humor_intensity", "timezone", "language"} {
+		raw, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, targets[key]); err != nil {
+			return typed, nil, extras, fmt.Errorf("%w: metadata_json %s has the wrong type", ErrInvalidPreferences, key)
+		}
+		delete(fields, key)
+		paths = append(paths, key)
+	}
+	if intensity != math.Trunc(intensity) || intensity < 0 || intensity > 10 {
+		return typed, nil, extras, fmt.Errorf("%w: humor_intensity must be a whole number between 0 and 10", ErrInvalidPreferences)
+	}
+	typed.HumorIntensity = int32(intensity)
+
+	if len(fields) == 0 {
+		return typed, paths, extras, nil
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return typed, nil, extras, err
+	}
+	return typed, paths, pqtype.NullRawMessage{RawMessage: b, Valid: true}, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"guiltmachine/internal/auth"
 	cacheDomain "guiltmachine/internal/cache/domain"
@@ -34,8 +35,9 @@ func (s *SessionService) SetJWTManager(jwt *auth.JWTManager) {
 
 // CreateSessionResult holds the result of creating a session
 type CreateSessionResult struct {
-	Session sqlc.GuiltSession
-	JWT     string
+	Session      sqlc.GuiltSession
+	JWT          string
+	RefreshToken string
 }
 
 func (s *SessionService) CreateSession(ctx context.Context, userID string, notes *string) (sqlc.GuiltSession, error) {
@@ -68,11 +70,12 @@ func (s *SessionService) CreateSessionWithJWT(ctx context.Context, userID string
 
 	// Issue JWT if manager is configured
 	if s.jwt != nil {
-		token, err := s.jwt.Issue(userID, sess.ID.String())
+		pair, err := s.jwt.IssuePair(userID, sess.ID.String())
 		if err != nil {
 			return CreateSessionResult{}, err
 		}
-		result.JWT = token
+		result.JWT = pair.AccessToken
+		result.RefreshToken = pair.RefreshToken
 	}
 
 	return result, nil
@@ -89,6 +92,13 @@ func (s *SessionService) EndSession(ctx context.Context, id string) (sqlc.GuiltS
 		return sqlc.GuiltSession{}, err
 	}
 
+	// Kill every outstanding token for the session
+	if s.cache != nil && s.jwt != nil {
+		if err := s.cache.RevokeSession(ctx, sid.String(), s.jwt.RefreshTTL()); err != nil {
+			return sqlc.GuiltSession{}, err
+		}
+	}
+
 	return sess, nil
 }
 
@@ -119,3 +129,48 @@ func (s *SessionService) ListSessionsByUser(ctx context.Context, userID string,
 
 	return sessions, nil
 }
+
+// RefreshToken exchanges a refresh token for a new access/refresh pair.
+// Refresh tokens are single use: presenting one twice revokes the session.
+func (s *SessionService) RefreshToken(ctx context.Context, refreshToken string) (auth.TokenPair, error) {
+	if s.jwt == nil || s.cache == nil {
+		return auth.TokenPair{}, errors.New("token refresh not configured")
+	}
+
+	claims, err := s.jwt.VerifyRefresh(refreshToken)
+	if err != nil {
+		return auth.TokenPair{}, auth.ErrInvalidToken
+	}
+
+	revoked, err := s.cache.IsSessionRevoked(ctx, claims.SessionID)
+	if err != nil {
+		return auth.TokenPair{}, err
+	}
+	if revoked {
+		return auth.TokenPair{}, auth.ErrRevokedToken
+	}
+
+	sid, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return auth.TokenPair{}, auth.ErrInvalidToken
+	}
+	sess, err := s.repo.GetSessionByID(ctx, sid)
+	if err != nil {
+		return auth.TokenPair{}, auth.ErrRevokedToken
+	}
+	if sess.EndTime.Valid || sess.UserID.String() != claims.UserID {
+		return auth.TokenPair{}, auth.ErrRevokedToken
+	}
+
+	firstUse, err := s.cache.MarkRefreshTokenUsed(ctx, claims.TokenID, time.Until(claims.ExpiresAt))
+	if err != nil {
+		return auth.TokenPair{}, err
+	}
+	if !firstUse {
+		// A replayed refresh token means it leaked; end the whole session
+		_ = s.cache.RevokeSession(ctx, claims.SessionID, s.jwt.RefreshTTL())
+		return auth.TokenPair{}, auth.ErrRevokedToken
+	}
+
+	return s.jwt.IssuePair(claims.UserID, claims.SessionID)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTask wraps every validation failure of a task request
+var ErrInvalidTask = errors.New("invalid task")
+
+// task statuses, as stored in tasks.status
+const (
+	TaskPending   = "pending"
+	TaskCompleted = "completed"
+	TaskSnoozed   = "snoozed"
+	TaskDeleted   = "deleted"
+)
+
+// TaskPaths are the field names UpdateTask accepts, the same as the fields
+// of the Task proto message. Status changes have their own calls.
+var TaskPaths = []string{
+	"title",
+	"description",
+	"due_at",
+	"category",
+	"priority",
+	"estimated_minutes",
+}
+
+const (
+	maxTaskTitle       = 200
+	maxTaskDescription = 4000
+	maxTaskCategory    = 64
+	maxTaskPriority    = 3
+	// a week of work; anything longer should be split into several tasks
+	maxTaskEstimate = 7 * 24 * 60
+
+	defaultTaskListLimit = 50
+	maxTaskListLimit     = 200
+)
+
+// TaskService manages tasks. Every change is recorded in the task event log
+// in the same transaction.
+type TaskService struct {
+	repo         repository.TasksRepository
+	events       repository.TaskEventsRepository
+	snooze       SnoozeConfig
+	orchestrator *ml.HybridOrchestrator
+	prefs        *PreferencesService
+	features     *FeatureService
+}
+
+func NewTaskService(r repository.TasksRepository, events repository.TaskEventsRepository) *TaskService {
+	return &TaskService{repo: r, events: events, snooze: DefaultSnoozeConfig}
+}
+
+// CreateTask adds a pending task for userID with the settable fields of task
+func (s *TaskService) CreateTask(ctx context.Context, userID string, task sqlc.Task) (sqlc.Task, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return sqlc.Task{}, fmt.Errorf("%w: invalid user_id", ErrInvalidTask)
+	}
+
+	normalizeTask(&task)
+	if err := validateTask(task); err != nil {
+		return sqlc.Task{}, err
+	}
+	task.UserID = uid
+
+	event, err := taskCreatedEvent(task)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	return s.repo.CreateTask(ctx, task, event)
+}
+
+// GetTask returns sql.ErrNoRows for missing and deleted tasks alike
+func (s *TaskService) GetTask(ctx context.Context, id string) (sqlc.Task, error) {
+	tid, err := parseTaskID(id)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	return s.repo.GetTask(ctx, tid)
+}
+
+// ListTasks pages through a user's tasks by due date. An empty status lists
+// every task that is not deleted.
+func (s *TaskService) ListTasks(ctx context.Context, userID string, status string, limit, offset int32) ([]sqlc.Task, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user_id", ErrInvalidTask)
+	}
+	if status != "" && !slices.Contains([]string{TaskPending, TaskCompleted, TaskSnoozed}, status) {
+		return nil, fmt.Errorf("%w: cannot list tasks with status %q", ErrInvalidTask, status)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset must be >= 0", ErrInvalidTask)
+	}
+	switch {
+	case limit <= 0:
+		limit = defaultTaskListLimit
+	case limit > maxTaskListLimit:
+		limit = maxTaskListLimit
+	}
+
+	return s.repo.ListTasksByUser(ctx, uid, status, limit, offset)
+}
+
+// UpdateTask copies the fields named in paths from task and keeps the
+// others. A named field left empty in task is cleared.
+func (s *TaskService) UpdateTask(ctx context.Context, id string, task sqlc.Task, paths []string) (sqlc.Task, error) {
+	tid, err := parseTaskID(id)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	if len(paths) == 0 {
+		return sqlc.Task{}, fmt.Errorf("%w: update_mask required", ErrInvalidTask)
+	}
+	for _, path := range paths {
+		if !slices.Contains(TaskPaths, path) {
+			return sqlc.Task{}, fmt.Errorf("%w: unknown field %q in update_mask", ErrInvalidTask, path)
+		}
+	}
+
+	return s.repo.UpdateTask(ctx, tid, func(cur *sqlc.Task) (*sqlc.TaskEvent, error) {
+		before := *cur
+		for _, path := range paths {
+			copyTaskField(cur, task, path)
+		}
+		normalizeTask(cur)
+		if err := validateTask(*cur); err != nil {
+			return nil, err
+		}
+		return taskModifiedEvent(before, *cur)
+	})
+}
+
+// CompleteTask marks a task completed; completing it again changes nothing
+func (s *TaskService) CompleteTask(ctx context.Context, id string) (sqlc.Task, error) {
+	tid, err := parseTaskID(id)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	return s.repo.UpdateTask(ctx, tid, func(cur *sqlc.Task) (*sqlc.TaskEvent, error) {
+		if cur.Status == TaskCompleted {
+			return nil, nil
+		}
+		event, err := taskCompletedEvent(*cur, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		cur.Status = TaskCompleted
+		return &event, nil
+	})
+}
+
+// DeleteTask soft-deletes a task. The row is kept for analytics but no
+// longer readable through the service.
+func (s *TaskService) DeleteTask(ctx context.Context, id string) error {
+	tid, err := parseTaskID(id)
+	if err != nil {
+		return err
+	}
+	event, err := newTaskEvent(TaskEventDelete, struct{}{})
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTask(ctx, tid, event)
+}
+
+// helpers
+
+func parseTaskID(id string) (uuid.UUID, error) {
+	tid, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid task_id", ErrInvalidTask)
+	}
+	return tid, nil
+}
+
+func copyTaskField(dst *sqlc.Task, src sqlc.Task, path string) {
+	switch path {
+	case "title":
+		dst.Title = src.Title
+	case "description":
+		dst.Description = src.Description
+	case "due_at":
+		dst.DueAt = src.DueAt
+	case "category":
+		dst.Category = src.Category
+	case "priority":
+		dst.Priority = src.Priority
+	case "estimated_minutes":
+		dst.EstimatedMinutes = src.EstimatedMinutes
+	}
+}
+
+// normalizeTask trims text fields, stores blank ones as NULL and lowercases
+// the category so clustering sees "Work" and "work" as one
+func normalizeTask(t *sqlc.Task) {
+	t.Title = strings.TrimSpace(t.Title)
+	t.Description = trimmedNullString(t.Description)
+	t.Category = trimmedNullString(t.Category)
+	t.Category.String = strings.ToLower(t.Category.String)
+	if t.DueAt.Valid {
+		t.DueAt.Time = t.DueAt.Time.UTC()
+	}
+}
+
+func trimmedNullString(s sql.NullString) sql.NullString {
+	v := strings.TrimSpace(s.String)
+	return sql.NullString{String: v, Valid: s.Valid && v != ""}
+}
+
+func validateTask(t sqlc.Task) error {
+	if t.Title == "" {
+		return fmt.Errorf("%w: title required", ErrInvalidTask)
+	}
+	if utf8.RuneCountInString(t.Title) > maxTaskTitle {
+		return fmt.Errorf("%w: title longer than %d characters", ErrInvalidTask, maxTaskTitle)
+	}
+	if utf8.RuneCountInString(t.Description.String) > maxTaskDescription {
+		return fmt.Errorf("%w: description longer than %d characters", ErrInvalidTask, maxTaskDescription)
+	}
+	if utf8.RuneCountInString(t.Category.String) > maxTaskCategory {
+		return fmt.Errorf("%w: category longer than %d characters", ErrInvalidTask, maxTaskCategory)
+	}
+	if t.Priority < 0 || t.Priority > maxTaskPriority {
+		return fmt.Errorf("%w: priority must be between 0 and %d", ErrInvalidTask, maxTaskPriority)
+	}
+	if t.EstimatedMinutes.Valid && (t.EstimatedMinutes.Int32 <= 0 || t.EstimatedMinutes.Int32 > maxTaskEstimate) {
+		return fmt.Errorf("%w: estimated_minutes must be between 1 and %d", ErrInvalidTask, maxTaskEstimate)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+
+	"github.com/google/uuid"
+)
+
+// task event types, as stored in task_events.event_type
+const (
+	TaskEventCreate   = "create"
+	TaskEventModify   = "modify"
+	TaskEventSnooze   = "snooze"
+	TaskEventComplete = "complete"
+	TaskEventDelete   = "delete"
+)
+
+// TaskEventTypes are the event types ListTaskEvents filters by
+var TaskEventTypes = []string{
+	TaskEventCreate,
+	TaskEventModify,
+	TaskEventSnooze,
+	TaskEventComplete,
+	TaskEventDelete,
+}
+
+const (
+	defaultTaskEventLimit = 100
+	maxTaskEventLimit     = 500
+)
+
+// TaskCreatedPayload is the payload of create events. The description is
+// left out; modify events note when it changes.
+type TaskCreatedPayload struct {
+	Title            string     `json:"title"`
+	Category         string     `json:"category,omitempty"`
+	Priority         int16      `json:"priority"`
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	EstimatedMinutes *int32     `json:"estimated_minutes,omitempty"`
+}
+
+// TaskModifiedPayload is the payload of modify events: the old and new
+// value of each changed field, by TaskPaths name. Cleared fields are null.
+type TaskModifiedPayload struct {
+	Changes map[string]TaskFieldChange `json:"changes"`
+}
+
+type TaskFieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// TaskCompletedPayload is the payload of complete events. OverdueMinutes is
+// negative for tasks finished early and missing for tasks without due_at.
+type TaskCompletedPayload struct {
+	PreviousStatus string     `json:"previous_status"`
+	DueAt          *time.Time `json:"due_at,omitempty"`
+	OverdueMinutes *int64     `json:"overdue_minutes,omitempty"`
+	OpenMinutes    int64      `json:"open_minutes"`
+}
+
+// ListTaskEvents pages through a user's task events, oldest first. Empty
+// types match every type; a zero since or until leaves that end open.
+func (s *TaskService) ListTaskEvents(ctx context.Context, userID string, types []string, since, until time.Time, limit, offset int32) ([]sqlc.TaskEvent, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user_id", ErrInvalidTask)
+	}
+	for _, t := range types {
+		if !slices.Contains(TaskEventTypes, t) {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalidTask, t)
+		}
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrInvalidTask)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset must be >= 0", ErrInvalidTask)
+	}
+	switch {
+	case limit <= 0:
+		limit = defaultTaskEventLimit
+	case limit > maxTaskEventLimit:
+		limit = maxTaskEventLimit
+	}
+
+	return s.events.ListTaskEventsByUser(ctx, uid, types, since, until, limit, offset)
+}
+
+// event builders; the repository fills in the task and user
+
+func newTaskEvent(eventType string, payload any) (sqlc.TaskEvent, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return sqlc.TaskEvent{}, err
+	}
+	return sqlc.TaskEvent{EventType: eventType, EventPayload: b}, nil
+}
+
+func taskCreatedEvent(t sqlc.Task) (sqlc.TaskEvent, error) {
+	return newTaskEvent(TaskEventCreate, TaskCreatedPayload{
+		Title:            t.Title,
+		Category:         t.Category.String,
+		Priority:         t.Priority,
+		DueAt:            nullTimePtr(t.DueAt),
+		EstimatedMinutes: nullInt32Ptr(t.EstimatedMinutes),
+	})
+}
+
+// taskModifiedEvent returns nil when no field changed
+func taskModifiedEvent(before, after sqlc.Task) (*sqlc.TaskEvent, error) {
+	changes := map[string]TaskFieldChange{}
+	if before.Title != after.Title {
+		changes["title"] = TaskFieldChange{before.Title, after.Title}
+	}
+	if before.Description != after.Description {
+		changes["description"] = TaskFieldChange{nullStringValue(before.Description), nullStringValue(after.Description)}
+	}
+	if before.DueAt.Valid != after.DueAt.Valid || !before.DueAt.Time.Equal(after.DueAt.Time) {
+		changes["due_at"] = TaskFieldChange{nullTimeValue(before.DueAt), nullTimeValue(after.DueAt)}
+	}
+	if before.Category != after.Category {
+		changes["category"] = TaskFieldChange{nullStringValue(before.Category), nullStringValue(after.Category)}
+	}
+	if before.Priority != after.Priority {
+		changes["priority"] = TaskFieldChange{before.Priority, after.Priority}
+	}
+	if before.EstimatedMinutes != after.EstimatedMinutes {
+		changes["estimated_minutes"] = TaskFieldChange{nullInt32Value(before.EstimatedMinutes), nullInt32Value(after.EstimatedMinutes)}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	event, err := newTaskEvent(TaskEventModify, TaskModifiedPayload{Changes: changes})
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func taskCompletedEvent(before sqlc.Task, now time.Time) (sqlc.TaskEvent, error) {
+	payload := TaskCompletedPayload{
+		PreviousStatus: before.Status,
+		DueAt:          nullTimePtr(before.DueAt),
+		OpenMinutes:    int64(now.Sub(before.CreatedAt) / time.Minute),
+	}
+	if before.DueAt.Valid {
+		overdue := int64(now.Sub(before.DueAt.Time) / time.Minute)
+		payload.OverdueMinutes = &overdue
+	}
+	return newTaskEvent(TaskEventComplete, payload)
+}
+
+func nullStringValue(ns sql.NullString) any {
+	if !ns.Valid {
+		return nil
+	}
+	return ns.String
+}
+
+func nullTimeValue(nt sql.NullTime) any {
+	if !nt.Valid {
+		return nil
+	}
+	return nt.Time.UTC()
+}
+
+func nullInt32Value(ni sql.NullInt32) any {
+	if !ni.Valid {
+		return nil
+	}
+	return ni.Int32
+}
+
+func nullTimePtr(nt sql.NullTime) *time.Time {
+	if !nt.Valid {
+		return nil
+	}
+	t := nt.Time.UTC()
+	return &t
+}
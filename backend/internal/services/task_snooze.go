@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	cacheDomain "guiltmachine/internal/cache/domain"
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/ml"
+)
+
+// ErrSnoozeLimit means a task has been snoozed as often as SnoozeConfig allows
+var ErrSnoozeLimit = errors.New("snooze limit reached")
+
+// SnoozeConfig limits snoozing and sets when a snooze earns a roast
+type SnoozeConfig struct {
+	// MaxSnoozes is how often a task can be snoozed; 0 means no limit
+	MaxSnoozes int32
+	// MinDuration and MaxDuration bound a snooze by duration
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	// RoastAfter is the snooze count from which every snooze is roasted, one
+	// humor intensity level hotter per further snooze; 0 disables roasts
+	RoastAfter int32
+}
+
+// DefaultSnoozeConfig applies until SetSnooze is called
+var DefaultSnoozeConfig = SnoozeConfig{
+	MaxSnoozes:  10,
+	MinDuration: 5 * time.Minute,
+	MaxDuration: 30 * 24 * time.Hour,
+	RoastAfter:  3,
+}
+
+// the work day of users who have not set work hours starts at 9:00
+const defaultWorkStartMinute = 9 * 60
+
+const maxHumorIntensity = 10
+
+// Snooze says how far to push a task's due date: by For, or with
+// NextWorkHours to the next start of the user's work hours. Either counts
+// from the later of now and the current due date, so a snooze never moves a
+// task closer.
+type Snooze struct {
+	For           time.Duration
+	NextWorkHours bool
+}
+
+// SnoozeResult is a snoozed task and, past SnoozeConfig.RoastAfter snoozes,
+// the roast it earned
+type SnoozeResult struct {
+	Task  sqlc.Task
+	Roast *ml.HybridOutput
+}
+
+// TaskSnoozedPayload is the payload of a snooze event
+type TaskSnoozedPayload struct {
+	PreviousStatus string     `json:"previous_status"`
+	PreviousDueAt  *time.Time `json:"previous_due_at"`
+	DueAt          time.Time  `json:"due_at"`
+	// SnoozedMinutes is how far the due date moved, from now for tasks that
+	// were undated or overdue
+	SnoozedMinutes int64 `json:"snoozed_minutes"`
+	NextWorkHours  bool  `json:"next_work_hours"`
+	SnoozeCount    int32 `json:"snooze_count"`
+}
+
+// SetSnooze replaces DefaultSnoozeConfig
+func (s *TaskService) SetSnooze(cfg SnoozeConfig) {
+	s.snooze = cfg
+}
+
+// SetOrchestrator makes repeated snoozes roasted by orchestrator
+func (s *TaskService) SetOrchestrator(orchestrator *ml.HybridOrchestrator) {
+	s.orchestrator = orchestrator
+}
+
+// SetPreferences lets snoozes use the owner's work hours, timezone, persona
+// and humor intensity; without it everyone gets DefaultPersonalization
+func (s *TaskService) SetPreferences(prefs *PreferencesService) {
+	s.prefs = prefs
+}
+
+// SetFeatures tunes snooze roasts to the owner's behavior mode; without it
+// every owner is treated as idle
+func (s *TaskService) SetFeatures(features *FeatureService) {
+	s.features = features
+}
+
+// SnoozeTask pushes a task's due date, marks it snoozed and counts the
+// snooze. Completed tasks cannot be snoozed; a task snoozed MaxSnoozes times
+// gets ErrSnoozeLimit. A roast that fails is logged and the snooze stands.
+func (s *TaskService) SnoozeTask(ctx context.Context, id string, snooze Snooze) (SnoozeResult, error) {
+	tid, err := parseTaskID(id)
+	if err != nil {
+		return SnoozeResult{}, err
+	}
+	if err := s.validateSnooze(snooze); err != nil {
+		return SnoozeResult{}, err
+	}
+
+	// the owner's preferences place the work hours; loaded before the task
+	// is locked to keep the transaction short
+	task, err := s.repo.GetTask(ctx, tid)
+	if err != nil {
+		return SnoozeResult{}, err
+	}
+	p := s.personalization(ctx, task.UserID.String())
+
+	now := time.Now()
+	task, err = s.repo.UpdateTask(ctx, tid, func(cur *sqlc.Task) (*sqlc.TaskEvent, error) {
+		if cur.Status == TaskCompleted {
+			return nil, fmt.Errorf("%w: completed tasks cannot be snoozed", ErrInvalidTask)
+		}
+		if s.snooze.MaxSnoozes > 0 && cur.SnoozeCount >= s.snooze.MaxSnoozes {
+			return nil, fmt.Errorf("%w: task snoozed %d times", ErrSnoozeLimit, cur.SnoozeCount)
+		}
+
+		from := now
+		if cur.DueAt.Valid && cur.DueAt.Time.After(now) {
+			from = cur.DueAt.Time
+		}
+		due := from.Add(snooze.For)
+		if snooze.NextWorkHours {
+			due = nextWorkStart(from, p)
+		}
+
+		payload := TaskSnoozedPayload{
+			PreviousStatus: cur.Status,
+			PreviousDueAt:  nullTimePtr(cur.DueAt),
+			DueAt:          due.UTC(),
+			SnoozedMinutes: int64(due.Sub(from) / time.Minute),
+			NextWorkHours:  snooze.NextWorkHours,
+			SnoozeCount:    cur.SnoozeCount + 1,
+		}
+		event, err := newTaskEvent(TaskEventSnooze, payload)
+		if err != nil {
+			return nil, err
+		}
+		cur.Status = TaskSnoozed
+		cur.DueAt.Time, cur.DueAt.Valid = due.UTC(), true
+		cur.SnoozeCount++
+		return &event, nil
+	})
+	if err != nil {
+		return SnoozeResult{}, err
+	}
+
+	return SnoozeResult{Task: task, Roast: s.snoozeRoast(ctx, task, p, now)}, nil
+}
+
+func (s *TaskService) validateSnooze(snooze Snooze) error {
+	if snooze.NextWorkHours {
+		if snooze.For != 0 {
+			return fmt.Errorf("%w: snooze by a duration or to the next work hours, not both", ErrInvalidTask)
+		}
+		return nil
+	}
+	if snooze.For < s.snooze.MinDuration || snooze.For > s.snooze.MaxDuration {
+		return fmt.Errorf("%w: snooze duration must be between %s and %s", ErrInvalidTask, s.snooze.MinDuration, s.snooze.MaxDuration)
+	}
+	return nil
+}
+
+// personalization loads the preferences of a task's owner. They only place
+// the work hours and tune the roast, so failures fall back to the defaults.
+func (s *TaskService) personalization(ctx context.Context, userID string) cacheDomain.PreferencesRecord {
+	if s.prefs == nil {
+		return DefaultPersonalization
+	}
+	rec, err := s.prefs.Personalization(ctx, userID)
+	if err != nil {
+		log.Printf("load preferences of user %s: %v", userID, err)
+	}
+	return rec
+}
+
+// snoozeRoast roasts a task snoozed at least RoastAfter times, hotter with
+// every snooze beyond that and for owners in the procrastinating mode,
+// milder for steady ones. It returns nil when no roast is due or the
+// orchestrator fails.
+func (s *TaskService) snoozeRoast(ctx context.Context, task sqlc.Task, p cacheDomain.PreferencesRecord, at time.Time) *ml.HybridOutput {
+	if s.orchestrator == nil || s.snooze.RoastAfter <= 0 || task.SnoozeCount < s.snooze.RoastAfter {
+		return nil
+	}
+
+	// unknown persona names get the neutral voice
+	persona, _ := ml.ParsePersona(p.Persona)
+	escalation := int(task.SnoozeCount-s.snooze.RoastAfter) + modeEscalation(s.behaviorMode(ctx, task))
+	out, err := s.orchestrator.Run(ctx, ml.HybridInput{
+		Text:      fmt.Sprintf("I snoozed %q again. That is snooze number %d for this task.", task.Title, task.SnoozeCount),
+		UserID:    task.UserID.String(),
+		Intensity: max(min(p.HumorIntensity+escalation, maxHumorIntensity), 0),
+		Persona:   persona,
+		// the snooze count stands in for the guilt level users rate their entries with
+		GuiltLevel: min(int(task.SnoozeCount), maxHumorIntensity),
+		At:         at,
+	})
+	if err != nil {
+		log.Printf("roast snooze %d of task %s: %v", task.SnoozeCount, task.ID, err)
+		return nil
+	}
+	return out
+}
+
+// nextWorkStart is the first start of the work day after t in the user's
+// timezone. Users without work hours start at defaultWorkStartMinute, and
+// an unknown timezone counts as UTC.
+func nextWorkStart(t time.Time, p cacheDomain.PreferencesRecord) time.Time {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	startMinute := defaultWorkStartMinute
+	if p.WorkStartMinute != nil {
+		startMinute = int(*p.WorkStartMinute)
+	}
+
+	local := t.In(loc)
+	for day := 0; ; day++ {
+		start := time.Date(local.Year(), local.Month(), local.Day()+day, 0, startMinute, 0, 0, loc)
+		if start.After(t) {
+			return start
+		}
+	}
+}
+
+// behaviorMode is the behavior mode of a task's owner. It only sets the tone
+// of a roast, so failures count as idle.
+func (s *TaskService) behaviorMode(ctx context.Context, task sqlc.Task) int {
+	if s.features == nil {
+		return ml.ModeIdle
+	}
+	mode, err := s.features.BehaviorMode(ctx, task.UserID)
+	if err != nil {
+		log.Printf("behavior mode of user %s: %v", task.UserID, err)
+	}
+	return mode
+}
+
+// modeEscalation shifts the humor intensity of a snooze roast: snoozing is
+// the pattern of procrastinating users and out of character for steady ones
+func modeEscalation(mode int) int {
+	switch mode {
+	case ml.ModeProcrastinating:
+		return 1
+	case ml.ModeSteady:
+		return -1
+	default:
+		return 0
+	}
+}
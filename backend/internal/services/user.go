@@ -2,30 +2,47 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
+	"strings"
 
 	"github.com/google/uuid"
+	"guiltmachine/internal/auth"
 	"guiltmachine/internal/db/sqlc"
 	"guiltmachine/internal/repository"
 )
 
 type UserService struct {
-	repo repository.UsersRepository
+	repo     repository.UsersRepository
+	sessions repository.SessionsRepository
+	jwt      *auth.JWTManager
 }
 
 func NewUserService(r repository.UsersRepository) *UserService {
 	return &UserService{repo: r}
 }
 
+// NewUserServiceWithAuth creates a UserService that can log users in
+func NewUserServiceWithAuth(r repository.UsersRepository, sessions repository.SessionsRepository, jwt *auth.JWTManager) *UserService {
+	return &UserService{repo: r, sessions: sessions, jwt: jwt}
+}
+
 var emailRegex = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
 
-func (s *UserService) CreateUser(ctx context.Context, email string, passwordHash string) (sqlc.User, error) {
+// CreateUser registers a user, hashing the plaintext password server-side
+func (s *UserService) CreateUser(ctx context.Context, email string, password string) (sqlc.User, error) {
+	email = normalizeEmail(email)
 	if !emailRegex.MatchString(email) {
 		return sqlc.User{}, errors.New("invalid email format")
 	}
 
-	u, err := s.repo.CreateUser(ctx, email, passwordHash)
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return sqlc.User{}, err
+	}
+
+	u, err := s.repo.CreateUser(ctx, email, hash)
 	if err != nil {
 		return sqlc.User{}, err
 	}
@@ -46,3 +63,46 @@ func (s *UserService) GetUser(ctx context.Context, id string) (sqlc.User, error)
 
 	return u, nil
 }
+
+// LoginResult holds the result of a successful login
+type LoginResult struct {
+	User         sqlc.User
+	Session      sqlc.GuiltSession
+	JWT          string
+	RefreshToken string
+}
+
+// Login verifies the credentials, opens a session and issues a JWT for it
+func (s *UserService) Login(ctx context.Context, email string, password string) (LoginResult, error) {
+	if s.sessions == nil || s.jwt == nil {
+		return LoginResult{}, errors.New("login not configured")
+	}
+
+	u, err := s.repo.GetUserByEmail(ctx, normalizeEmail(email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return LoginResult{}, auth.RejectPassword(password)
+	}
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	if err := auth.CheckPassword(u.PasswordHash, password); err != nil {
+		return LoginResult{}, err
+	}
+
+	sess, err := s.sessions.CreateSession(ctx, u.ID, nil)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	pair, err := s.jwt.IssuePair(u.ID.String(), sess.ID.String())
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{User: u, Session: sess, JWT: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
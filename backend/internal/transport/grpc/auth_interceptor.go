@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"guiltmachine/internal/auth"
+	cacheDomain "guiltmachine/internal/cache/domain"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -14,13 +15,14 @@ import (
 
 // publicMethods lists gRPC methods that don't require authentication
 var publicMethods = map[string]bool{
-	"/guiltmachine.v1.UserService/CreateUser":       true,
-	"/guiltmachine.v1.UserService/GetUser":          true,
-	"/guiltmachine.v1.SessionService/CreateSession": true,
+	"/guiltmachine.v1.UserService/CreateUser":      true,
+	"/guiltmachine.v1.UserService/Login":           true,
+	"/guiltmachine.v1.SessionService/RefreshToken": true,
 }
 
-// AuthInterceptor creates a unary interceptor for JWT authentication
-func AuthInterceptor(jwtManager *auth.JWTManager) grpc.UnaryServerInterceptor {
+// AuthInterceptor creates a unary interceptor for JWT authentication.
+// If sessions is non-nil, tokens belonging to revoked sessions are rejected.
+func AuthInterceptor(jwtManager *auth.JWTManager, sessions *cacheDomain.SessionCache) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -33,7 +35,7 @@ func AuthInterceptor(jwtManager *auth.JWTManager) grpc.UnaryServerInterceptor {
 		}
 
 		// Extract and validate token
-		userID, sessionID, err := validateToken(ctx, jwtManager)
+		userID, sessionID, err := validateToken(ctx, jwtManager, sessions)
 		if err != nil {
 			return nil, err
 		}
@@ -47,7 +49,7 @@ func AuthInterceptor(jwtManager *auth.JWTManager) grpc.UnaryServerInterceptor {
 }
 
 // AuthStreamInterceptor creates a stream interceptor for JWT authentication
-func AuthStreamInterceptor(jwtManager *auth.JWTManager) grpc.StreamServerInterceptor {
+func AuthStreamInterceptor(jwtManager *auth.JWTManager, sessions *cacheDomain.SessionCache) grpc.StreamServerInterceptor {
 	return func(
 		srv interface{},
 		stream grpc.ServerStream,
@@ -60,7 +62,7 @@ func AuthStreamInterceptor(jwtManager *auth.JWTManager) grpc.StreamServerInterce
 		}
 
 		// Extract and validate token
-		userID, sessionID, err := validateToken(stream.Context(), jwtManager)
+		userID, sessionID, err := validateToken(stream.Context(), jwtManager, sessions)
 		if err != nil {
 			return err
 		}
@@ -76,7 +78,7 @@ func AuthStreamInterceptor(jwtManager *auth.JWTManager) grpc.StreamServerInterce
 }
 
 // validateToken extracts and validates JWT from context metadata
-func validateToken(ctx context.Context, jwtManager *auth.JWTManager) (string, string, error) {
+func validateToken(ctx context.Context, jwtManager *auth.JWTManager, sessions *cacheDomain.SessionCache) (string, string, error) {
 	token, err := extractToken(ctx)
 	if err != nil {
 		return "", "", status.Error(codes.Unauthenticated, "missing authorization token")
@@ -87,6 +89,16 @@ func validateToken(ctx context.Context, jwtManager *auth.JWTManager) (string, st
 		return "", "", status.Error(codes.Unauthenticated, "invalid or expired token")
 	}
 
+	if sessions != nil {
+		revoked, err := sessions.IsSessionRevoked(ctx, sessionID)
+		if err != nil {
+			return "", "", status.Error(codes.Unavailable, "token revocation check failed")
+		}
+		if revoked {
+			return "", "", status.Error(codes.Unauthenticated, "token has been revoked")
+		}
+	}
+
 	return userID, sessionID, nil
 }
 
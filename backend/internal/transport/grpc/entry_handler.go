@@ -2,7 +2,10 @@ package grpc
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
+	"guiltmachine/internal/events"
 	v1 "guiltmachine/internal/proto/gen"
 	"guiltmachine/internal/services"
 
@@ -122,6 +125,88 @@ func (h *EntryHandler) GetEntry(ctx context.Context, req *v1.GetEntryRequest) (*
 	}, nil
 }
 
+// StreamRoast sends the roast as it is generated, then a final message with
+// the persisted text and score
+func (h *EntryHandler) StreamRoast(req *v1.StreamRoastRequest, stream v1.EntryService_StreamRoastServer) error {
+	if req.EntryId == "" {
+		return status.Error(codes.InvalidArgument, "entry_id required")
+	}
+
+	roast, score, err := h.svc.StreamRoast(stream.Context(), req.EntryId, func(delta string) error {
+		return stream.Send(&v1.StreamRoastResponse{Delta: delta})
+	})
+	if err != nil {
+		return streamError(err)
+	}
+
+	return stream.Send(&v1.StreamRoastResponse{
+		Done:       true,
+		RoastText:  roast,
+		GuiltScore: score,
+	})
+}
+
+// WatchEntry pushes status, roast and score changes of one entry
+func (h *EntryHandler) WatchEntry(req *v1.WatchEntryRequest, stream v1.EntryService_WatchEntryServer) error {
+	if req.EntryId == "" {
+		return status.Error(codes.InvalidArgument, "entry_id required")
+	}
+
+	err := h.svc.WatchEntry(stream.Context(), req.EntryId, func(ev events.EntryEvent) error {
+		return stream.Send(toEntryEvent(ev))
+	})
+	if err != nil {
+		return streamError(err)
+	}
+	return nil
+}
+
+// WatchSession pushes status, roast and score changes of every entry in a session
+func (h *EntryHandler) WatchSession(req *v1.WatchSessionRequest, stream v1.EntryService_WatchSessionServer) error {
+	if req.SessionId == "" {
+		return status.Error(codes.InvalidArgument, "session_id required")
+	}
+
+	err := h.svc.WatchSession(stream.Context(), req.SessionId, func(ev events.EntryEvent) error {
+		return stream.Send(toEntryEvent(ev))
+	})
+	if err != nil {
+		return streamError(err)
+	}
+	return nil
+}
+
+func toEntryEvent(ev events.EntryEvent) *v1.EntryEvent {
+	return &v1.EntryEvent{
+		EntryId:    ev.EntryID,
+		SessionId:  ev.SessionID,
+		Status:     ev.Status,
+		RoastText:  ev.RoastText,
+		GuiltScore: ev.GuiltScore,
+		UpdatedAt:  timestamppb.New(ev.At),
+	}
+}
+
+// streamError maps service errors of the streaming RPCs to gRPC status codes
+func streamError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrEntryBusy):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, services.ErrWatchUnavailable):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, services.ErrWatchInterrupted):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "entry not found")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
 func nullableText(v string) string {
 	return v
 }
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"guiltmachine/internal/db/sqlc"
+	v1 "guiltmachine/internal/proto/gen"
+	"guiltmachine/internal/services"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type InsightsHandler struct {
+	v1.UnimplementedInsightsServiceServer
+	svc *services.FeatureService
+}
+
+func NewInsightsHandler(svc *services.FeatureService) *InsightsHandler {
+	return &InsightsHandler{svc: svc}
+}
+
+func (h *InsightsHandler) GetFeatureSnapshot(ctx context.Context, req *v1.GetFeatureSnapshotRequest) (*v1.GetFeatureSnapshotResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+
+	snap, err := h.svc.GetLatestFeatureSnapshot(ctx, req.UserId)
+	if err != nil {
+		return nil, insightsError(err)
+	}
+
+	return &v1.GetFeatureSnapshotResponse{Snapshot: featureSnapshotToProto(snap)}, nil
+}
+
+func (h *InsightsHandler) ListFeatureSnapshots(ctx context.Context, req *v1.ListFeatureSnapshotsRequest) (*v1.ListFeatureSnapshotsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+
+	snaps, err := h.svc.ListFeatureSnapshots(ctx, req.UserId, req.Limit, req.Offset)
+	if err != nil {
+		return nil, insightsError(err)
+	}
+
+	items := make([]*v1.FeatureSnapshot, 0, len(snaps))
+	for _, snap := range snaps {
+		items = append(items, featureSnapshotToProto(snap))
+	}
+
+	return &v1.ListFeatureSnapshotsResponse{Snapshots: items}, nil
+}
+
+// helpers
+
+func insightsError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidInsights):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "no feature snapshot yet")
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func featureSnapshotToProto(s sqlc.FeatureSnapshot) *v1.FeatureSnapshot {
+	// snapshots computed on request are not stored and have no ID
+	snapshotID := ""
+	if s.ID != uuid.Nil {
+		snapshotID = s.ID.String()
+	}
+	return &v1.FeatureSnapshot{
+		SnapshotId:           snapshotID,
+		UserId:               s.UserID.String(),
+		ProcrastinationScore: s.ProcrastinationScore,
+		SnoozeRate:           s.SnoozeRate,
+		CompletionLatencyAvg: s.CompletionLatencyAvg,
+		BurstinessIndex:      s.BurstinessIndex,
+		ModeCluster:          s.ModeCluster,
+		// mode_cluster holds the mode its cluster is labelled with
+		Mode:           v1.BehaviorMode(s.ModeCluster),
+		WindowStart:    timestamppb.New(s.WindowStart),
+		WindowEnd:      timestamppb.New(s.WindowEnd),
+		CreatedAt:      timestamppb.New(s.CreatedAt),
+		ClusterVersion: s.ClusterVersion,
+	}
+}
@@ -0,0 +1,188 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"guiltmachine/internal/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resourcePolicy authorizes a request against the resource it references
+type resourcePolicy func(ctx context.Context, authz *services.Authorizer, req interface{}) error
+
+// resourcePolicies maps every authenticated method to its ownership check.
+// Methods missing from both this map and publicMethods are denied.
+var resourcePolicies = map[string]resourcePolicy{
+	"/guiltmachine.v1.UserService/GetUser": ownsUser,
+
+	"/guiltmachine.v1.SessionService/CreateSession":      ownsUser,
+	"/guiltmachine.v1.SessionService/EndSession":         ownsSessionByID,
+	"/guiltmachine.v1.SessionService/GetSession":         ownsSessionByID,
+	"/guiltmachine.v1.SessionService/ListSessionsByUser": ownsUser,
+
+	"/guiltmachine.v1.EntryService/CreateEntry":  ownsSession,
+	"/guiltmachine.v1.EntryService/ListEntries":  ownsSession,
+	"/guiltmachine.v1.EntryService/GetEntry":     ownsEntry,
+	"/guiltmachine.v1.EntryService/StreamRoast":  ownsEntry,
+	"/guiltmachine.v1.EntryService/WatchEntry":   ownsEntry,
+	"/guiltmachine.v1.EntryService/WatchSession": ownsSession,
+
+	"/guiltmachine.v1.ScoreService/CreateScore": ownsSession,
+	"/guiltmachine.v1.ScoreService/GetScore":    ownsSession,
+
+	"/guiltmachine.v1.PreferencesService/UpsertPreferences": ownsUser,
+	"/guiltmachine.v1.PreferencesService/GetPreferences":    ownsUser,
+	"/guiltmachine.v1.PreferencesService/UpdatePreferences": ownsUser,
+
+	"/guiltmachine.v1.TaskService/CreateTask":     ownsUser,
+	"/guiltmachine.v1.TaskService/ListTasks":      ownsUser,
+	"/guiltmachine.v1.TaskService/GetTask":        ownsTask,
+	"/guiltmachine.v1.TaskService/UpdateTask":     ownsTask,
+	"/guiltmachine.v1.TaskService/CompleteTask":   ownsTask,
+	"/guiltmachine.v1.TaskService/DeleteTask":     ownsTask,
+	"/guiltmachine.v1.TaskService/SnoozeTask":     ownsTask,
+	"/guiltmachine.v1.TaskService/ListTaskEvents": ownsUser,
+
+	"/guiltmachine.v1.InsightsService/GetFeatureSnapshot":   ownsUser,
+	"/guiltmachine.v1.InsightsService/ListFeatureSnapshots": ownsUser,
+
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      authenticatedOnly,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": authenticatedOnly,
+}
+
+// PolicyInterceptor creates a unary interceptor enforcing resource ownership.
+// It must run after AuthInterceptor so the caller is in the context.
+func PolicyInterceptor(authz *services.Authorizer) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		if err := authorize(ctx, authz, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// PolicyStreamInterceptor creates a stream interceptor enforcing resource ownership
+// on the first message received from the client
+func PolicyStreamInterceptor(authz *services.Authorizer) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if publicMethods[info.FullMethod] {
+			return handler(srv, stream)
+		}
+
+		if _, ok := resourcePolicies[info.FullMethod]; !ok {
+			return status.Error(codes.PermissionDenied, "no access policy for method")
+		}
+
+		return handler(srv, &policyStream{
+			ServerStream: stream,
+			authz:        authz,
+			method:       info.FullMethod,
+		})
+	}
+}
+
+func authorize(ctx context.Context, authz *services.Authorizer, method string, req interface{}) error {
+	policy, ok := resourcePolicies[method]
+	if !ok {
+		return status.Error(codes.PermissionDenied, "no access policy for method")
+	}
+
+	err := policy(ctx, authz, req)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, services.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, services.ErrInvalidResourceID):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, "authorization check failed")
+	}
+}
+
+// policyStream authorizes the first inbound message before handing it to the handler
+type policyStream struct {
+	grpc.ServerStream
+	authz      *services.Authorizer
+	method     string
+	authorized bool
+}
+
+func (s *policyStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.authorized {
+		if err := authorize(s.Context(), s.authz, s.method, m); err != nil {
+			return err
+		}
+		s.authorized = true
+	}
+	return nil
+}
+
+// policies
+
+func ownsUser(ctx context.Context, authz *services.Authorizer, req interface{}) error {
+	r, ok := req.(interface{ GetUserId() string })
+	if !ok {
+		return services.ErrPermissionDenied
+	}
+	return authz.CanAccessUser(ctx, r.GetUserId())
+}
+
+func ownsSession(ctx context.Context, authz *services.Authorizer, req interface{}) error {
+	r, ok := req.(interface{ GetSessionId() string })
+	if !ok {
+		return services.ErrPermissionDenied
+	}
+	return authz.CanAccessSession(ctx, r.GetSessionId())
+}
+
+// ownsSessionByID covers session RPCs that name the session in an `id` field
+func ownsSessionByID(ctx context.Context, authz *services.Authorizer, req interface{}) error {
+	r, ok := req.(interface{ GetId() string })
+	if !ok {
+		return services.ErrPermissionDenied
+	}
+	return authz.CanAccessSession(ctx, r.GetId())
+}
+
+func ownsEntry(ctx context.Context, authz *services.Authorizer, req interface{}) error {
+	r, ok := req.(interface{ GetEntryId() string })
+	if !ok {
+		return services.ErrPermissionDenied
+	}
+	return authz.CanAccessEntry(ctx, r.GetEntryId())
+}
+
+func ownsTask(ctx context.Context, authz *services.Authorizer, req interface{}) error {
+	r, ok := req.(interface{ GetTaskId() string })
+	if !ok {
+		return services.ErrPermissionDenied
+	}
+	return authz.CanAccessTask(ctx, r.GetTaskId())
+}
+
+func authenticatedOnly(ctx context.Context, authz *services.Authorizer, req interface{}) error {
+	return nil
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
+	"guiltmachine/internal/db/sqlc"
 	v1 "guiltmachine/internal/proto/gen"
 	"guiltmachine/internal/services"
-	"github.com/sqlc-dev/pqtype"
 
+	"github.com/sqlc-dev/pqtype"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -22,20 +26,46 @@ func NewPreferencesHandler(svc *services.PreferencesService) *PreferencesHandler
 	return &PreferencesHandler{svc: svc}
 }
 
+var personaNames = map[v1.Persona]string{
+	v1.Persona_PERSONA_UNSPECIFIED: "",
+	v1.Persona_PERSONA_NEUTRAL:     "neutral",
+	v1.Persona_PERSONA_ROAST:       "roast",
+	v1.Persona_PERSONA_COACH:       "coach",
+	v1.Persona_PERSONA_CHILL:       "chill",
+}
+
+var nudgeChannelNames = map[v1.NudgeChannel]string{
+	v1.NudgeChannel_NUDGE_CHANNEL_PUSH:   services.NudgePush,
+	v1.NudgeChannel_NUDGE_CHANNEL_EMAIL:  services.NudgeEmail,
+	v1.NudgeChannel_NUDGE_CHANNEL_SMS:    services.NudgeSMS,
+	v1.NudgeChannel_NUDGE_CHANNEL_IN_APP: services.NudgeInApp,
+}
+
 func (h *PreferencesHandler) UpsertPreferences(ctx context.Context, req *v1.UpsertPreferencesRequest) (*v1.UpsertPreferencesResponse, error) {
 	if req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id required")
 	}
 
-	var themePtr *string
-	if req.Theme != "" {
-		t := req.Theme
-		themePtr = &t
+	var (
+		pref sqlc.UserPreference
+		err  error
+	)
+	if req.Preferences != nil {
+		typed, perr := preferencesFromProto(req.Preferences)
+		if perr != nil {
+			return nil, status.Error(codes.InvalidArgument, perr.Error())
+		}
+		pref, err = h.svc.ReplacePreferences(ctx, req.UserId, typed, req.MetadataJson)
+	} else {
+		var themePtr *string
+		if req.Theme != "" {
+			t := req.Theme
+			themePtr = &t
+		}
+		pref, err = h.svc.UpsertPreferences(ctx, req.UserId, themePtr, req.Notifications, req.MetadataJson)
 	}
-
-	pref, err := h.svc.UpsertPreferences(ctx, req.UserId, themePtr, req.Notifications, req.MetadataJson)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, preferencesError(err)
 	}
 
 	return &v1.UpsertPreferencesResponse{
@@ -43,6 +73,7 @@ func (h *PreferencesHandler) UpsertPreferences(ctx context.Context, req *v1.Upse
 		Theme:         nullableStringPref(pref.Theme),
 		Notifications: pref.NotificationsEnabled,
 		MetadataJson:  rawMetaPref(pref.Metadata),
+		Preferences:   preferencesToProto(pref),
 	}, nil
 }
 
@@ -61,11 +92,137 @@ func (h *PreferencesHandler) GetPreferences(ctx context.Context, req *v1.GetPref
 		Theme:         nullableStringPref(pref.Theme),
 		Notifications: pref.NotificationsEnabled,
 		MetadataJson:  rawMetaPref(pref.Metadata),
+		Preferences:   preferencesToProto(pref),
+	}, nil
+}
+
+func (h *PreferencesHandler) UpdatePreferences(ctx context.Context, req *v1.UpdatePreferencesRequest) (*v1.UpdatePreferencesResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask required")
+	}
+
+	// an unset preferences message resets every named field
+	typed, err := preferencesFromProto(req.Preferences)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	pref, err := h.svc.UpdatePreferences(ctx, req.UserId, typed, req.UpdateMask.Paths)
+	if err != nil {
+		return nil, preferencesError(err)
+	}
+
+	return &v1.UpdatePreferencesResponse{
+		UserId:      pref.UserID.String(),
+		Preferences: preferencesToProto(pref),
 	}, nil
 }
 
 // helpers
 
+func preferencesError(err error) error {
+	if errors.Is(err, services.ErrInvalidPreferences) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func preferencesFromProto(p *v1.Preferences) (sqlc.UserPreference, error) {
+	pref := sqlc.UserPreference{
+		NotificationsEnabled: p.GetNotifications(),
+		HumorIntensity:       p.GetHumorIntensity(),
+		Timezone:             p.GetTimezone(),
+		Language:             p.GetLanguage(),
+		NudgeChannels:        []string{},
+	}
+	if p.GetTheme() != "" {
+		pref.Theme = sql.NullString{String: p.GetTheme(), Valid: true}
+	}
+
+	persona, ok := personaNames[p.GetPersona()]
+	if !ok {
+		return sqlc.UserPreference{}, fmt.Errorf("unknown persona %d", p.GetPersona())
+	}
+	pref.Persona = persona
+
+	var err error
+	if pref.WorkStartMinute, pref.WorkEndMinute, err = timeRangeFromProto(p.GetWorkHours()); err != nil {
+		return sqlc.UserPreference{}, fmt.Errorf("work_hours: %w", err)
+	}
+	if pref.QuietStartMinute, pref.QuietEndMinute, err = timeRangeFromProto(p.GetQuietHours()); err != nil {
+		return sqlc.UserPreference{}, fmt.Errorf("quiet_hours: %w", err)
+	}
+
+	for _, c := range p.GetNudgeChannels() {
+		name, ok := nudgeChannelNames[c]
+		if !ok {
+			return sqlc.UserPreference{}, fmt.Errorf("unknown nudge channel %d", c)
+		}
+		pref.NudgeChannels = append(pref.NudgeChannels, name)
+	}
+	return pref, nil
+}
+
+func preferencesToProto(pref sqlc.UserPreference) *v1.Preferences {
+	p := &v1.Preferences{
+		Theme:          nullableStringPref(pref.Theme),
+		Notifications:  pref.NotificationsEnabled,
+		HumorIntensity: pref.HumorIntensity,
+		Timezone:       pref.Timezone,
+		WorkHours:      timeRangeToProto(pref.WorkStartMinute, pref.WorkEndMinute),
+		QuietHours:     timeRangeToProto(pref.QuietStartMinute, pref.QuietEndMinute),
+		Language:       pref.Language,
+	}
+	for persona, name := range personaNames {
+		if name != "" && name == pref.Persona {
+			p.Persona = persona
+		}
+	}
+	for _, name := range pref.NudgeChannels {
+		for channel, n := range nudgeChannelNames {
+			if n == name {
+				p.NudgeChannels = append(p.NudgeChannels, channel)
+			}
+		}
+	}
+	return p
+}
+
+// timeRangeFromProto parses "HH:MM" bounds into minutes after midnight
+func timeRangeFromProto(r *v1.TimeRange) (start, end sql.NullInt32, err error) {
+	if r == nil {
+		return start, end, nil
+	}
+	if start, err = minuteOfDay(r.Start); err != nil {
+		return start, end, err
+	}
+	if end, err = minuteOfDay(r.End); err != nil {
+		return start, end, err
+	}
+	return start, end, nil
+}
+
+func minuteOfDay(hhmm string) (sql.NullInt32, error) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return sql.NullInt32{}, fmt.Errorf("%q is not HH:MM", hhmm)
+	}
+	return sql.NullInt32{Int32: int32(t.Hour()*60 + t.Minute()), Valid: true}, nil
+}
+
+func timeRangeToProto(start, end sql.NullInt32) *v1.TimeRange {
+	if !start.Valid || !end.Valid {
+		return nil
+	}
+	return &v1.TimeRange{
+		Start: fmt.Sprintf("%02d:%02d", start.Int32/60, start.Int32%60),
+		End:   fmt.Sprintf("%02d:%02d", end.Int32/60, end.Int32%60),
+	}
+}
+
 func nullableStringPref(ns sql.NullString) string {
 	if ns.Valid {
 		return ns.String
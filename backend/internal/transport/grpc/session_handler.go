@@ -3,6 +3,9 @@ package grpc
 import (
 	"context"
 	"database/sql"
+	"errors"
+
+	"guiltmachine/internal/auth"
 
 	v1 "guiltmachine/internal/proto/gen/v1"
 	"guiltmachine/internal/services"
@@ -37,11 +40,12 @@ func (h *SessionHandler) CreateSession(ctx context.Context, req *v1.CreateSessio
 	}
 
 	return &v1.CreateSessionResponse{
-		Id:        result.Session.ID.String(),
-		UserId:    result.Session.UserID.String(),
-		Notes:     nullableStringSession(result.Session.Notes),
-		CreatedAt: timestamppb.New(result.Session.StartTime),
-		Jwt:       result.JWT,
+		Id:           result.Session.ID.String(),
+		UserId:       result.Session.UserID.String(),
+		Notes:        nullableStringSession(result.Session.Notes),
+		CreatedAt:    timestamppb.New(result.Session.StartTime),
+		Jwt:          result.JWT,
+		RefreshToken: result.RefreshToken,
 	}, nil
 }
 
@@ -109,6 +113,26 @@ func (h *SessionHandler) ListSessionsByUser(ctx context.Context, req *v1.ListSes
 	}, nil
 }
 
+func (h *SessionHandler) RefreshToken(ctx context.Context, req *v1.RefreshTokenRequest) (*v1.RefreshTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token required")
+	}
+
+	pair, err := h.svc.RefreshToken(ctx, req.RefreshToken)
+	if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrRevokedToken) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "token refresh failed")
+	}
+
+	return &v1.RefreshTokenResponse{
+		Jwt:          pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    timestamppb.New(pair.AccessExpiresAt),
+	}, nil
+}
+
 // helpers
 
 func nullableStringSession(ns sql.NullString) string {
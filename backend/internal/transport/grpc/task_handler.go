@@ -0,0 +1,270 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	v1 "guiltmachine/internal/proto/gen"
+	"guiltmachine/internal/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TaskHandler struct {
+	v1.UnimplementedTaskServiceServer
+	svc *services.TaskService
+}
+
+func NewTaskHandler(svc *services.TaskService) *TaskHandler {
+	return &TaskHandler{svc: svc}
+}
+
+var taskStatusNames = map[v1.TaskStatus]string{
+	v1.TaskStatus_TASK_STATUS_UNSPECIFIED: "",
+	v1.TaskStatus_TASK_STATUS_PENDING:     services.TaskPending,
+	v1.TaskStatus_TASK_STATUS_COMPLETED:   services.TaskCompleted,
+	v1.TaskStatus_TASK_STATUS_SNOOZED:     services.TaskSnoozed,
+}
+
+var taskEventTypeNames = map[v1.TaskEventType]string{
+	v1.TaskEventType_TASK_EVENT_TYPE_CREATE:   services.TaskEventCreate,
+	v1.TaskEventType_TASK_EVENT_TYPE_MODIFY:   services.TaskEventModify,
+	v1.TaskEventType_TASK_EVENT_TYPE_SNOOZE:   services.TaskEventSnooze,
+	v1.TaskEventType_TASK_EVENT_TYPE_COMPLETE: services.TaskEventComplete,
+	v1.TaskEventType_TASK_EVENT_TYPE_DELETE:   services.TaskEventDelete,
+}
+
+func (h *TaskHandler) CreateTask(ctx context.Context, req *v1.CreateTaskRequest) (*v1.CreateTaskResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+	if req.Task == nil {
+		return nil, status.Error(codes.InvalidArgument, "task required")
+	}
+
+	t, err := h.svc.CreateTask(ctx, req.UserId, taskFromProto(req.Task))
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	return &v1.CreateTaskResponse{Task: taskToProto(t)}, nil
+}
+
+func (h *TaskHandler) GetTask(ctx context.Context, req *v1.GetTaskRequest) (*v1.GetTaskResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	t, err := h.svc.GetTask(ctx, req.TaskId)
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	return &v1.GetTaskResponse{Task: taskToProto(t)}, nil
+}
+
+func (h *TaskHandler) ListTasks(ctx context.Context, req *v1.ListTasksRequest) (*v1.ListTasksResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+	taskStatus, ok := taskStatusNames[req.Status]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown status %d", req.Status)
+	}
+
+	tasks, err := h.svc.ListTasks(ctx, req.UserId, taskStatus, req.Limit, req.Offset)
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	items := make([]*v1.Task, 0, len(tasks))
+	for _, t := range tasks {
+		items = append(items, taskToProto(t))
+	}
+
+	return &v1.ListTasksResponse{Tasks: items}, nil
+}
+
+func (h *TaskHandler) UpdateTask(ctx context.Context, req *v1.UpdateTaskRequest) (*v1.UpdateTaskResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id required")
+	}
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask required")
+	}
+
+	// an unset task message clears every named field
+	t, err := h.svc.UpdateTask(ctx, req.TaskId, taskFromProto(req.Task), req.UpdateMask.Paths)
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	return &v1.UpdateTaskResponse{Task: taskToProto(t)}, nil
+}
+
+func (h *TaskHandler) CompleteTask(ctx context.Context, req *v1.CompleteTaskRequest) (*v1.CompleteTaskResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	t, err := h.svc.CompleteTask(ctx, req.TaskId)
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	return &v1.CompleteTaskResponse{Task: taskToProto(t)}, nil
+}
+
+func (h *TaskHandler) DeleteTask(ctx context.Context, req *v1.DeleteTaskRequest) (*v1.DeleteTaskResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id required")
+	}
+
+	if err := h.svc.DeleteTask(ctx, req.TaskId); err != nil {
+		return nil, taskError(err)
+	}
+
+	return &v1.DeleteTaskResponse{}, nil
+}
+
+func (h *TaskHandler) SnoozeTask(ctx context.Context, req *v1.SnoozeTaskRequest) (*v1.SnoozeTaskResponse, error) {
+	if req.TaskId == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id required")
+	}
+	if req.Duration != nil {
+		if err := req.Duration.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid duration: %v", err)
+		}
+	}
+
+	res, err := h.svc.SnoozeTask(ctx, req.TaskId, services.Snooze{
+		For:           req.Duration.AsDuration(),
+		NextWorkHours: req.NextWorkHours,
+	})
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	resp := &v1.SnoozeTaskResponse{Task: taskToProto(res.Task)}
+	if res.Roast != nil {
+		resp.RoastText = res.Roast.RoastText
+	}
+	return resp, nil
+}
+
+func (h *TaskHandler) ListTaskEvents(ctx context.Context, req *v1.ListTaskEventsRequest) (*v1.ListTaskEventsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id required")
+	}
+	types := make([]string, 0, len(req.Types))
+	for _, t := range req.Types {
+		name, ok := taskEventTypeNames[t]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type %d", t)
+		}
+		types = append(types, name)
+	}
+	var since, until time.Time
+	if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+	if req.Until != nil {
+		until = req.Until.AsTime()
+	}
+
+	events, err := h.svc.ListTaskEvents(ctx, req.UserId, types, since, until, req.Limit, req.Offset)
+	if err != nil {
+		return nil, taskError(err)
+	}
+
+	items := make([]*v1.TaskEvent, 0, len(events))
+	for _, e := range events {
+		item := &v1.TaskEvent{
+			EventId:     e.ID.String(),
+			TaskId:      e.TaskID.String(),
+			UserId:      e.UserID.String(),
+			PayloadJson: string(e.EventPayload),
+			CreatedAt:   timestamppb.New(e.CreatedAt),
+		}
+		for t, name := range taskEventTypeNames {
+			if name == e.EventType {
+				item.Type = t
+			}
+		}
+		items = append(items, item)
+	}
+
+	return &v1.ListTaskEventsResponse{Events: items}, nil
+}
+
+// helpers
+
+func taskError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidTask):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, services.ErrSnoozeLimit):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "task not found")
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// taskFromProto reads the settable fields of t; output-only fields are ignored
+func taskFromProto(t *v1.Task) sqlc.Task {
+	task := sqlc.Task{
+		Title: t.GetTitle(),
+		// clamped so out of range priorities fail validation instead of wrapping
+		Priority: int16(min(max(t.GetPriority(), -1), 1<<15-1)),
+	}
+	if t.GetDescription() != "" {
+		task.Description = sql.NullString{String: t.GetDescription(), Valid: true}
+	}
+	if t.GetDueAt() != nil {
+		task.DueAt = sql.NullTime{Time: t.GetDueAt().AsTime(), Valid: true}
+	}
+	if t.GetCategory() != "" {
+		task.Category = sql.NullString{String: t.GetCategory(), Valid: true}
+	}
+	if t.GetEstimatedMinutes() != 0 {
+		task.EstimatedMinutes = sql.NullInt32{Int32: t.GetEstimatedMinutes(), Valid: true}
+	}
+	return task
+}
+
+func taskToProto(t sqlc.Task) *v1.Task {
+	task := &v1.Task{
+		TaskId:           t.ID.String(),
+		UserId:           t.UserID.String(),
+		Title:            t.Title,
+		Description:      nullableStringTask(t.Description),
+		Category:         nullableStringTask(t.Category),
+		Priority:         int32(t.Priority),
+		EstimatedMinutes: t.EstimatedMinutes.Int32,
+		SnoozeCount:      t.SnoozeCount,
+		CreatedAt:        timestamppb.New(t.CreatedAt),
+		UpdatedAt:        timestamppb.New(t.UpdatedAt),
+	}
+	for s, name := range taskStatusNames {
+		if name != "" && name == t.Status {
+			task.Status = s
+		}
+	}
+	if t.DueAt.Valid {
+		task.DueAt = timestamppb.New(t.DueAt.Time)
+	}
+	return task
+}
+
+func nullableStringTask(ns sql.NullString) string {
+	if ns.Valid {
+		return ns.String
+	}
+	return ""
+}
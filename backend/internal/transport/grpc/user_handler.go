@@ -2,6 +2,9 @@ package grpc
 
 import (
 	"context"
+	"errors"
+
+	"guiltmachine/internal/auth"
 	v1 "guiltmachine/internal/proto/gen"
 	"guiltmachine/internal/services"
 
@@ -20,11 +23,11 @@ func NewUserHandler(svc *services.UserService) *UserHandler {
 }
 
 func (h *UserHandler) CreateUser(ctx context.Context, req *v1.CreateUserRequest) (*v1.CreateUserResponse, error) {
-	if req.Email == "" || req.PasswordHash == "" {
-		return nil, status.Error(codes.InvalidArgument, "email and password_hash required")
+	if req.Email == "" || req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password required")
 	}
 
-	u, err := h.svc.CreateUser(ctx, req.Email, req.PasswordHash)
+	u, err := h.svc.CreateUser(ctx, req.Email, req.Password)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -53,3 +56,24 @@ func (h *UserHandler) GetUser(ctx context.Context, req *v1.GetUserRequest) (*v1.
 		UpdatedAt: timestamppb.New(u.UpdatedAt),
 	}, nil
 }
+
+func (h *UserHandler) Login(ctx context.Context, req *v1.LoginRequest) (*v1.LoginResponse, error) {
+	if req.Email == "" || req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password required")
+	}
+
+	result, err := h.svc.Login(ctx, req.Email, req.Password)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "login failed")
+	}
+
+	return &v1.LoginResponse{
+		UserId:       result.User.ID.String(),
+		SessionId:    result.Session.ID.String(),
+		Jwt:          result.JWT,
+		RefreshToken: result.RefreshToken,
+	}, nil
+}
@@ -0,0 +1,17 @@
+CREATE TABLE jobs (
+    id BIGSERIAL PRIMARY KEY,
+    queue TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_by TEXT,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jobs_ready ON jobs(queue, run_at) WHERE status = 'queued';
+CREATE INDEX idx_jobs_leased ON jobs(queue, locked_until) WHERE status = 'running';
+CREATE INDEX idx_jobs_dead ON jobs(queue, updated_at) WHERE status = 'dead';
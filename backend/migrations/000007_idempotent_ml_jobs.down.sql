@@ -0,0 +1,6 @@
+ALTER TABLE guilt_entries DROP COLUMN IF EXISTS ml_attempts;
+ALTER TABLE guilt_entries DROP COLUMN IF EXISTS ml_job_key;
+ALTER TABLE guilt_entries DROP CONSTRAINT IF EXISTS guilt_entries_status_check;
+
+ALTER TABLE guilt_scores DROP CONSTRAINT IF EXISTS guilt_scores_entry_id_key;
+CREATE INDEX idx_guilt_scores_entry_id ON guilt_scores(entry_id);
@@ -0,0 +1,18 @@
+-- Keep only the newest score per entry before enforcing uniqueness
+DELETE FROM guilt_scores s
+USING guilt_scores newer
+WHERE s.entry_id IS NOT NULL
+  AND s.entry_id = newer.entry_id
+  AND (s.created_at, s.id) < (newer.created_at, newer.id);
+
+DROP INDEX IF EXISTS idx_guilt_scores_entry_id;
+ALTER TABLE guilt_scores ADD CONSTRAINT guilt_scores_entry_id_key UNIQUE (entry_id);
+
+-- Entry status becomes a guarded state machine: pending -> processing -> completed | failed
+UPDATE guilt_entries SET status = 'pending' WHERE status IS NULL;
+ALTER TABLE guilt_entries ADD CONSTRAINT guilt_entries_status_check
+    CHECK (status IN ('pending', 'processing', 'completed', 'failed'));
+
+-- Idempotency key of the job that last claimed the entry, and how often it was claimed
+ALTER TABLE guilt_entries ADD COLUMN ml_job_key TEXT;
+ALTER TABLE guilt_entries ADD COLUMN ml_attempts INTEGER NOT NULL DEFAULT 0;
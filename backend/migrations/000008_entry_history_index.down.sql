@@ -0,0 +1,2 @@
+CREATE INDEX idx_guilt_entries_session_id ON guilt_entries(session_id);
+DROP INDEX IF EXISTS idx_guilt_entries_session_created;
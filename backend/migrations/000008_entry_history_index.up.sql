@@ -0,0 +1,3 @@
+-- history lookups read the latest entries of a session before a given one
+CREATE INDEX idx_guilt_entries_session_created ON guilt_entries(session_id, created_at DESC);
+DROP INDEX IF EXISTS idx_guilt_entries_session_id;
@@ -0,0 +1,23 @@
+-- fold the settings personalization reads back into metadata; work hours,
+-- quiet hours and nudge channels had no metadata form and are dropped
+UPDATE user_preferences SET metadata = (
+    CASE WHEN jsonb_typeof(metadata) = 'object' THEN metadata ELSE '{}'::jsonb END
+) || jsonb_build_object(
+    'persona', persona,
+    'humor_intensity', humor_intensity,
+    'timezone', timezone,
+    'language', language
+);
+
+ALTER TABLE user_preferences
+    DROP CONSTRAINT IF EXISTS user_preferences_quiet_hours_check,
+    DROP CONSTRAINT IF EXISTS user_preferences_work_hours_check,
+    DROP COLUMN IF EXISTS nudge_channels,
+    DROP COLUMN IF EXISTS quiet_end_minute,
+    DROP COLUMN IF EXISTS quiet_start_minute,
+    DROP COLUMN IF EXISTS work_end_minute,
+    DROP COLUMN IF EXISTS work_start_minute,
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS humor_intensity,
+    DROP COLUMN IF EXISTS persona;
@@ -0,0 +1,46 @@
+-- typed personalization settings, previously free-form keys in metadata
+ALTER TABLE user_preferences
+    ADD COLUMN persona TEXT NOT NULL DEFAULT 'roast'
+        CHECK (persona IN ('neutral', 'roast', 'coach', 'chill')),
+    ADD COLUMN humor_intensity INTEGER NOT NULL DEFAULT 3
+        CHECK (humor_intensity BETWEEN 0 AND 10),
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC',
+    ADD COLUMN language TEXT NOT NULL DEFAULT 'en',
+    -- local times as minutes after midnight; end before start wraps midnight
+    ADD COLUMN work_start_minute INTEGER CHECK (work_start_minute BETWEEN 0 AND 1439),
+    ADD COLUMN work_end_minute INTEGER CHECK (work_end_minute BETWEEN 0 AND 1439),
+    ADD COLUMN quiet_start_minute INTEGER CHECK (quiet_start_minute BETWEEN 0 AND 1439),
+    ADD COLUMN quiet_end_minute INTEGER CHECK (quiet_end_minute BETWEEN 0 AND 1439),
+    ADD COLUMN nudge_channels TEXT[] NOT NULL DEFAULT '{}'
+        CHECK (nudge_channels <@ ARRAY['push', 'email', 'sms', 'in_app']),
+    ADD CONSTRAINT user_preferences_work_hours_check
+        CHECK ((work_start_minute IS NULL) = (work_end_minute IS NULL)),
+    ADD CONSTRAINT user_preferences_quiet_hours_check
+        CHECK ((quiet_start_minute IS NULL) = (quiet_end_minute IS NULL));
+
+-- carry over the metadata keys personalization used to read; invalid values
+-- keep the column default
+UPDATE user_preferences SET
+    persona = CASE
+        WHEN metadata->>'persona' IN ('neutral', 'roast', 'coach', 'chill') THEN metadata->>'persona'
+        ELSE persona
+    END,
+    humor_intensity = CASE
+        WHEN jsonb_typeof(metadata->'humor_intensity') = 'number'
+            THEN LEAST(10, GREATEST(0, trunc((metadata->>'humor_intensity')::numeric)))::integer
+        ELSE humor_intensity
+    END,
+    timezone = CASE
+        WHEN metadata->>'timezone' IN (SELECT name FROM pg_timezone_names) THEN metadata->>'timezone'
+        ELSE timezone
+    END,
+    language = CASE
+        WHEN metadata->>'language' ~ '^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$' THEN metadata->>'language'
+        ELSE language
+    END
+WHERE jsonb_typeof(metadata) = 'object';
+
+-- the columns are authoritative now; drop the keys so they cannot drift
+UPDATE user_preferences
+SET metadata = NULLIF(metadata - ARRAY['persona', 'humor_intensity', 'timezone', 'language'], '{}'::jsonb)
+WHERE jsonb_typeof(metadata) = 'object';
@@ -0,0 +1,20 @@
+CREATE TABLE tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT NOT NULL CHECK (btrim(title) <> ''),
+    description TEXT,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'completed', 'snoozed', 'deleted')),
+    due_at TIMESTAMPTZ,
+    category TEXT,
+    priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 3),
+    estimated_minutes INTEGER CHECK (estimated_minutes > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    -- soft-deleted tasks keep their row for analytics but always read as deleted
+    CONSTRAINT tasks_deleted_status CHECK ((deleted_at IS NULL) = (status <> 'deleted'))
+);
+
+-- task lists: a user's live tasks by due date
+CREATE INDEX idx_tasks_user_due ON tasks(user_id, due_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,25 @@
+-- append-only log of task behavior for procrastination analytics
+CREATE TABLE task_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL
+        CHECK (event_type IN ('create', 'modify', 'snooze', 'complete', 'delete')),
+    event_payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- event history pages: a user's events in time order
+CREATE INDEX idx_task_events_user_created ON task_events(user_id, created_at, id);
+CREATE INDEX idx_task_events_task_id ON task_events(task_id);
+
+-- rows are only ever added; deletes stay allowed so users and tasks can be erased
+CREATE FUNCTION task_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'task_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_events_no_update
+    BEFORE UPDATE ON task_events
+    FOR EACH ROW EXECUTE FUNCTION task_events_append_only();
@@ -0,0 +1,4 @@
+-- how often each task was snoozed; snooze limits and roasts read it without
+-- counting the task's events
+ALTER TABLE tasks
+    ADD COLUMN snooze_count INTEGER NOT NULL DEFAULT 0 CHECK (snooze_count >= 0);
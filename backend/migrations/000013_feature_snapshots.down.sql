@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_task_events_created;
+DROP INDEX IF EXISTS idx_guilt_entries_created;
+DROP INDEX IF EXISTS idx_guilt_sessions_start_time;
+DROP TABLE IF EXISTS feature_snapshots;
@@ -0,0 +1,24 @@
+-- behavioral features computed per user by the batch job; rows are never
+-- updated, each run adds a snapshot so trends can be charted
+CREATE TABLE feature_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    procrastination_score DOUBLE PRECISION NOT NULL CHECK (procrastination_score BETWEEN 0 AND 1),
+    snooze_rate DOUBLE PRECISION NOT NULL CHECK (snooze_rate BETWEEN 0 AND 1),
+    completion_latency_avg DOUBLE PRECISION NOT NULL CHECK (completion_latency_avg >= 0),
+    burstiness_index DOUBLE PRECISION NOT NULL CHECK (burstiness_index BETWEEN -1 AND 1),
+    mode_cluster INTEGER NOT NULL,
+    -- the activity the features were computed from
+    window_start TIMESTAMPTZ NOT NULL,
+    window_end TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (window_start < window_end)
+);
+
+-- insights: a user's snapshots, newest first
+CREATE INDEX idx_feature_snapshots_user_created ON feature_snapshots(user_id, created_at DESC);
+
+-- the batch job finds active users by recent activity
+CREATE INDEX idx_guilt_sessions_start_time ON guilt_sessions(start_time);
+CREATE INDEX idx_guilt_entries_created ON guilt_entries(created_at);
+CREATE INDEX idx_task_events_created ON task_events(created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE feature_snapshots DROP COLUMN IF EXISTS cluster_version;
+DROP TABLE IF EXISTS cluster_models;
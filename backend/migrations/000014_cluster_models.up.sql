@@ -0,0 +1,14 @@
+-- k-means centroids of the behavior modes; the batch job adds a version each
+-- time it retrains and assigns users with the newest one
+CREATE TABLE cluster_models (
+    version SERIAL PRIMARY KEY,
+    centroids JSONB NOT NULL,
+    -- how many users the centroids were trained on
+    sample_size INTEGER NOT NULL CHECK (sample_size > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- the model version that assigned mode_cluster; 0 is the model built into
+-- the binary and has no row
+ALTER TABLE feature_snapshots
+    ADD COLUMN cluster_version INTEGER NOT NULL DEFAULT 0 CHECK (cluster_version >= 0);
@@ -0,0 +1,6 @@
+-- messages that can never be published (unknown topic, undecodable payload)
+-- are set aside instead of blocking every message after them
+ALTER TABLE outbox ADD COLUMN dead_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_outbox_unsent;
+CREATE INDEX idx_outbox_unsent ON outbox(id) WHERE sent_at IS NULL AND dead_at IS NULL;
@@ -0,0 +1,6 @@
+-- relays claim messages for a while instead of holding row locks while they
+-- publish; the claims of a relay that dies mid-batch run out
+ALTER TABLE outbox ADD COLUMN claimed_until TIMESTAMPTZ;
+
+-- the entry a message was written for, failed when the message goes dead
+ALTER TABLE outbox ADD COLUMN entry_id UUID REFERENCES guilt_entries(id) ON DELETE SET NULL;
@@ -1,6 +1,7 @@
 package auth_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -103,3 +104,80 @@ func TestJWTManager_EmptyClaims(t *testing.T) {
 		t.Fatal("expected error for empty claims")
 	}
 }
+
+func TestJWTManager_IssuePair(t *testing.T) {
+	m := auth.NewJWTManagerWithRefresh("test-secret", 15*time.Minute, 24*time.Hour)
+
+	pair, err := m.IssuePair("user-123", "session-456")
+	if err != nil {
+		t.Fatalf("failed to issue pair: %v", err)
+	}
+	if pair.AccessToken == "" || pair.RefreshToken == "" {
+		t.Fatal("expected both tokens to be set")
+	}
+	if !pair.RefreshExpiresAt.After(pair.AccessExpiresAt) {
+		t.Errorf("expected refresh expiry %v after access expiry %v", pair.RefreshExpiresAt, pair.AccessExpiresAt)
+	}
+
+	claims, err := m.VerifyRefresh(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("failed to verify refresh token: %v", err)
+	}
+	if claims.UserID != "user-123" || claims.SessionID != "session-456" {
+		t.Errorf("unexpected refresh claims: %+v", claims)
+	}
+	if claims.TokenID == "" {
+		t.Error("expected refresh token to carry a jti")
+	}
+}
+
+func TestJWTManager_TokenTypesNotInterchangeable(t *testing.T) {
+	m := auth.NewJWTManagerWithRefresh("test-secret", 15*time.Minute, 24*time.Hour)
+
+	pair, err := m.IssuePair("user-123", "session-456")
+	if err != nil {
+		t.Fatalf("failed to issue pair: %v", err)
+	}
+
+	// A refresh token must not authenticate RPCs
+	if _, _, err := m.Verify(pair.RefreshToken); err == nil {
+		t.Fatal("expected refresh token to be rejected as access token")
+	}
+
+	// An access token must not be exchangeable for new tokens
+	if _, err := m.VerifyRefresh(pair.AccessToken); err == nil {
+		t.Fatal("expected access token to be rejected as refresh token")
+	}
+}
+
+func TestJWTManager_UniqueTokenIDs(t *testing.T) {
+	m := auth.NewJWTManager("test-secret", time.Hour)
+
+	p1, _ := m.IssuePair("user-123", "session-456")
+	p2, _ := m.IssuePair("user-123", "session-456")
+
+	c1, err := m.VerifyRefresh(p1.RefreshToken)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	c2, err := m.VerifyRefresh(p2.RefreshToken)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if c1.TokenID == c2.TokenID {
+		t.Fatal("expected distinct jti per refresh token")
+	}
+}
+
+func TestJWTManager_ExpiredRefreshToken(t *testing.T) {
+	m := auth.NewJWTManagerWithRefresh("test-secret", time.Hour, -time.Hour)
+
+	pair, err := m.IssuePair("user-123", "session-456")
+	if err != nil {
+		t.Fatalf("failed to issue pair: %v", err)
+	}
+
+	if _, err := m.VerifyRefresh(pair.RefreshToken); !errors.Is(err, auth.ErrExpiredToken) {
+		t.Fatalf("expected ErrExpiredToken, got %v", err)
+	}
+}
@@ -0,0 +1,318 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestKeySet_GeneratesKeyWhenEmpty(t *testing.T) {
+	dir := t.TempDir()
+
+	ks, err := auth.LoadKeySet(dir)
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	if ks.Active() == nil {
+		t.Fatal("expected an active signing key")
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if len(files) != 1 {
+		t.Fatalf("expected generated key on disk, got %v", files)
+	}
+
+	// Reloading from disk must yield the same active key
+	again, err := auth.LoadKeySet(dir)
+	if err != nil {
+		t.Fatalf("failed to reload key set: %v", err)
+	}
+	if again.Active().ID != ks.Active().ID {
+		t.Errorf("expected active kid %s, got %s", ks.Active().ID, again.Active().ID)
+	}
+}
+
+func TestJWTManagerWithKeys_IssueAndVerify(t *testing.T) {
+	ks, err := auth.LoadKeySet(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	m := auth.NewJWTManagerWithKeys(ks, time.Hour, 24*time.Hour)
+
+	token, err := m.Issue("user-123", "session-456")
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("failed to decode token: %v", err)
+	}
+	if parsed.Method.Alg() != "EdDSA" {
+		t.Errorf("expected EdDSA, got %s", parsed.Method.Alg())
+	}
+	if parsed.Header["kid"] != ks.Active().ID {
+		t.Errorf("expected kid %s, got %v", ks.Active().ID, parsed.Header["kid"])
+	}
+
+	userID, sessionID, err := m.Verify(token)
+	if err != nil {
+		t.Fatalf("failed to verify token: %v", err)
+	}
+	if userID != "user-123" || sessionID != "session-456" {
+		t.Errorf("unexpected claims %s/%s", userID, sessionID)
+	}
+}
+
+func TestJWTManagerWithKeys_Rotation(t *testing.T) {
+	ks, err := auth.LoadKeySet(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	m := auth.NewJWTManagerWithKeys(ks, time.Hour, 24*time.Hour)
+
+	oldKid := ks.Active().ID
+	oldToken, _ := m.Issue("user-123", "session-456")
+
+	newKey, err := ks.Rotate()
+	if err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	if newKey.ID == oldKid {
+		t.Fatal("expected a new kid after rotation")
+	}
+
+	// Tokens signed before the rotation keep verifying
+	if _, _, err := m.Verify(oldToken); err != nil {
+		t.Fatalf("expected pre-rotation token to verify: %v", err)
+	}
+
+	// Pruning the retired key invalidates its tokens
+	removed, err := ks.Prune(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != oldKid {
+		t.Fatalf("expected %s to be pruned, got %v", oldKid, removed)
+	}
+	if _, _, err := m.Verify(oldToken); err == nil {
+		t.Fatal("expected token from pruned key to be rejected")
+	}
+
+	// The active key survives pruning
+	if ks.Active() == nil || ks.Active().ID != newKey.ID {
+		t.Fatal("expected active key to survive pruning")
+	}
+}
+
+func TestKeyRotator_RotatesWhenDue(t *testing.T) {
+	ks, err := auth.LoadKeySet(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	first := ks.Active().ID
+
+	r := auth.NewKeyRotator(ks, time.Hour, 48*time.Hour)
+
+	if err := r.RotateIfDue(time.Now()); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	if ks.Active().ID != first {
+		t.Fatal("did not expect rotation before interval elapsed")
+	}
+
+	if err := r.RotateIfDue(time.Now().Add(2 * time.Hour)); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	if ks.Active().ID == first {
+		t.Fatal("expected rotation after interval elapsed")
+	}
+	if _, ok := ks.Key(first); !ok {
+		t.Fatal("expected retired key to be kept for verification")
+	}
+}
+
+func TestKeySet_LoadsRSAAndPublicOnlyKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa keygen failed: %v", err)
+	}
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	if err := os.WriteFile(filepath.Join(dir, "rsa-signing.pem"), pkcs1, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pubDER, _ := x509.MarshalPKIXPublicKey(&other.PublicKey)
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	if err := os.WriteFile(filepath.Join(dir, "retired.pub.pem"), pub, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ks, err := auth.LoadKeySet(dir)
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	if ks.Active().ID != "rsa-signing" {
+		t.Fatalf("expected rsa-signing to be active, got %s", ks.Active().ID)
+	}
+
+	m := auth.NewJWTManagerWithKeys(ks, time.Hour, time.Hour)
+	token, err := m.Issue("user-123", "session-456")
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	parsed, _, _ := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if parsed.Method.Alg() != "RS256" {
+		t.Errorf("expected RS256, got %s", parsed.Method.Alg())
+	}
+	if _, _, err := m.Verify(token); err != nil {
+		t.Fatalf("failed to verify RS256 token: %v", err)
+	}
+
+	retired, ok := ks.Key("retired")
+	if !ok || retired.Private != nil {
+		t.Fatal("expected public-only retired key")
+	}
+}
+
+func TestJWTManagerWithKeys_RejectsHMACTokens(t *testing.T) {
+	ks, err := auth.LoadKeySet(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	m := auth.NewJWTManagerWithKeys(ks, time.Hour, time.Hour)
+
+	// An HS256 token claiming the active kid must not verify
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-123",
+		"sid": "session-456",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	forged.Header["kid"] = ks.Active().ID
+	token, err := forged.SignedString([]byte("guessed-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := m.Verify(token); err == nil {
+		t.Fatal("expected HS256 token to be rejected")
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	ks, err := auth.LoadKeySet(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	if _, err := ks.Rotate(); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+
+	raw, err := ks.JWKS()
+	if err != nil {
+		t.Fatalf("jwks failed: %v", err)
+	}
+
+	var doc struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("invalid jwks json: %v", err)
+	}
+	if len(doc.Keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(doc.Keys))
+	}
+	for _, k := range doc.Keys {
+		if k["kty"] != "OKP" || k["crv"] != "Ed25519" || k["alg"] != "EdDSA" || k["x"] == "" || k["kid"] == "" {
+			t.Errorf("unexpected jwk: %v", k)
+		}
+		if _, hasD := k["d"]; hasD {
+			t.Error("jwks must not expose private key material")
+		}
+	}
+}
+
+func TestKeySet_ReloadsForUnknownKid(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := auth.LoadKeySet(dir)
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	// another replica sharing the directory
+	replica, err := auth.LoadKeySet(dir)
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	m := auth.NewJWTManagerWithKeys(ks, time.Hour, time.Hour)
+	other := auth.NewJWTManagerWithKeys(replica, time.Hour, time.Hour)
+
+	if _, err := replica.Rotate(); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	token, _ := other.Issue("user-123", "session-456")
+	if _, _, err := m.Verify(token); err != nil {
+		t.Fatalf("expected a token of a key rotated elsewhere to verify: %v", err)
+	}
+
+	// a second miss right after is not worth another read of the directory
+	if _, err := replica.Rotate(); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	token, _ = other.Issue("user-123", "session-456")
+	if _, _, err := m.Verify(token); !errors.Is(err, auth.ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey within the reload interval, got %v", err)
+	}
+}
+
+func TestKeySet_ActiveKeyIsNewest(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := auth.LoadKeySet(dir)
+	if err != nil {
+		t.Fatalf("failed to load key set: %v", err)
+	}
+	rotated := ks.Active().ID
+
+	// a key provisioned by hand dates from its file, not its name
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(priv)
+	path := filepath.Join(dir, "zz-manual.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if ks.Active().ID != rotated {
+		t.Fatalf("expected the rotated key to stay active, got %s", ks.Active().ID)
+	}
+
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if ks.Active().ID != "zz-manual" {
+		t.Fatalf("expected the newer manual key to be active, got %s", ks.Active().ID)
+	}
+}
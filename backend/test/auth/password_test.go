@@ -0,0 +1,55 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+
+	"guiltmachine/internal/auth"
+)
+
+func TestHashPassword_RoundTrip(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	if hash == "correct horse battery" {
+		t.Fatal("expected hash to differ from plaintext")
+	}
+
+	if err := auth.CheckPassword(hash, "correct horse battery"); err != nil {
+		t.Fatalf("expected password to match: %v", err)
+	}
+}
+
+func TestHashPassword_WrongPassword(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse battery")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	err = auth.CheckPassword(hash, "wrong horse battery")
+	if !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestHashPassword_TooShort(t *testing.T) {
+	_, err := auth.HashPassword("short")
+	if !errors.Is(err, auth.ErrWeakPassword) {
+		t.Fatalf("expected ErrWeakPassword, got %v", err)
+	}
+}
+
+func TestHashPassword_Salted(t *testing.T) {
+	h1, _ := auth.HashPassword("same-password")
+	h2, _ := auth.HashPassword("same-password")
+	if h1 == h2 {
+		t.Fatal("expected different hashes for the same password")
+	}
+}
+
+func TestRejectPassword(t *testing.T) {
+	if err := auth.RejectPassword("anything"); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
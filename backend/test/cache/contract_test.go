@@ -0,0 +1,120 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cache "guiltmachine/internal/cache"
+)
+
+// runCacheContract checks the semantics every cache.Cache shares. wait lets
+// time pass for TTLs; keys are prefixed so a shared Redis is left clean.
+func runCacheContract(t *testing.T, c cache.Cache, prefix string, wait func(time.Duration)) {
+	ctx := context.Background()
+	key := func(name string) string {
+		k := prefix + name
+		t.Cleanup(func() { _ = c.Del(ctx, k) })
+		return k
+	}
+
+	t.Run("values", func(t *testing.T) {
+		k := key("value")
+		if _, err := c.Get(ctx, k); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		_ = c.Set(ctx, k, []byte("a"), 0)
+		_ = c.Set(ctx, k, []byte("b"), 0)
+		if v, err := c.Get(ctx, k); err != nil || string(v) != "b" {
+			t.Fatalf("expected overwritten value, got %q (%v)", v, err)
+		}
+		_ = c.Del(ctx, k)
+		if _, err := c.Get(ctx, k); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound after delete, got %v", err)
+		}
+		if err := c.Del(ctx, k); err != nil {
+			t.Fatalf("expected deleting a missing key to succeed, got %v", err)
+		}
+	})
+
+	t.Run("setnx", func(t *testing.T) {
+		k := key("setnx")
+		if ok, err := c.SetNX(ctx, k, []byte("a"), time.Minute); err != nil || !ok {
+			t.Fatalf("expected SetNX of a missing key to store it, got %v (%v)", ok, err)
+		}
+		if ok, err := c.SetNX(ctx, k, []byte("b"), time.Minute); err != nil || ok {
+			t.Fatalf("expected SetNX of an existing key to do nothing, got %v (%v)", ok, err)
+		}
+		if v, err := c.Get(ctx, k); err != nil || string(v) != "a" {
+			t.Fatalf("expected the first value, got %q (%v)", v, err)
+		}
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		short, long := key("short"), key("long")
+		_ = c.Set(ctx, short, []byte("x"), 100*time.Millisecond)
+		_ = c.Set(ctx, long, []byte("x"), time.Minute)
+		wait(300 * time.Millisecond)
+		if _, err := c.Get(ctx, short); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("expected expired key to be gone, got %v", err)
+		}
+		if _, err := c.Get(ctx, long); err != nil {
+			t.Fatalf("expected unexpired key, got %v", err)
+		}
+	})
+
+	t.Run("counters", func(t *testing.T) {
+		k := key("counter")
+		for want := int64(1); want <= 3; want++ {
+			if n, err := c.Incr(ctx, k, time.Minute); err != nil || n != want {
+				t.Fatalf("expected %d, got %d (%v)", want, n, err)
+			}
+		}
+		if v, _ := c.Get(ctx, k); string(v) != "3" {
+			t.Fatalf("expected the counter readable as a value, got %q", v)
+		}
+		text := key("text")
+		_ = c.Set(ctx, text, []byte("not a number"), 0)
+		if _, err := c.Incr(ctx, text, 0); err == nil {
+			t.Fatalf("expected Incr of a non-integer to fail")
+		}
+	})
+
+	t.Run("lists", func(t *testing.T) {
+		k := key("list")
+		if items, err := c.Range(ctx, k); err != nil || len(items) != 0 {
+			t.Fatalf("expected an empty range for a missing list, got %v (%v)", items, err)
+		}
+		for i, v := range []string{"a", "b", "c"} {
+			if n, err := c.Push(ctx, k, []byte(v), time.Minute); err != nil || n != int64(i+1) {
+				t.Fatalf("expected length %d, got %d (%v)", i+1, n, err)
+			}
+		}
+		items, err := c.Range(ctx, k)
+		if err != nil || len(items) != 3 || string(items[0]) != "a" || string(items[2]) != "c" {
+			t.Fatalf("expected items in push order, got %q (%v)", items, err)
+		}
+		if _, err := c.Get(ctx, k); err == nil {
+			t.Fatalf("expected Get of a list to fail")
+		}
+	})
+
+	t.Run("sets", func(t *testing.T) {
+		k := key("set")
+		if n, err := c.Scard(ctx, k); err != nil || n != 0 {
+			t.Fatalf("expected zero for a missing set, got %d (%v)", n, err)
+		}
+		for _, v := range []string{"a", "b", "a"} {
+			if err := c.Sadd(ctx, k, []byte(v), time.Minute); err != nil {
+				t.Fatalf("Sadd failed: %v", err)
+			}
+		}
+		if n, _ := c.Scard(ctx, k); n != 2 {
+			t.Fatalf("expected duplicates counted once, got %d", n)
+		}
+		if _, err := c.Push(ctx, k, []byte("x"), 0); err == nil {
+			t.Fatalf("expected Push onto a set to fail")
+		}
+	})
+}
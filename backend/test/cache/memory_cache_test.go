@@ -0,0 +1,81 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cache "guiltmachine/internal/cache"
+	"guiltmachine/internal/cache/memory"
+)
+
+// clock is a manual time source for expiry tests
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestMemoryCache(t *testing.T) {
+	clk := &clock{now: time.Unix(1700000000, 0)}
+	runCacheContract(t, memory.NewMemoryCacheWithClock(clk.Now), "", clk.Advance)
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{now: time.Unix(1700000000, 0)}
+	c := memory.NewMemoryCacheWithClock(clk.Now)
+
+	// counters keep their expiry unless a ttl is given, like INCR
+	_, _ = c.Incr(ctx, "hits", time.Minute)
+	clk.Advance(40 * time.Second)
+	_, _ = c.Incr(ctx, "hits", 0)
+	clk.Advance(30 * time.Second)
+	if _, err := c.Get(ctx, "hits"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected the counter to expire on its first ttl, got %v", err)
+	}
+
+	// a push with a ttl extends the whole list
+	_, _ = c.Push(ctx, "recent", []byte("a"), time.Minute)
+	clk.Advance(40 * time.Second)
+	_, _ = c.Push(ctx, "recent", []byte("b"), time.Minute)
+	clk.Advance(40 * time.Second)
+	if items, _ := c.Range(ctx, "recent"); len(items) != 2 {
+		t.Fatalf("expected the refreshed list to survive, got %d items", len(items))
+	}
+
+	// a set without ttl replaces an expiring value for good
+	_ = c.Set(ctx, "k", []byte("v"), time.Second)
+	_ = c.Set(ctx, "k", []byte("v"), 0)
+	clk.Advance(time.Hour)
+	if _, err := c.Get(ctx, "k"); err != nil {
+		t.Fatalf("expected a value without ttl to stay, got %v", err)
+	}
+}
+
+func TestMemoryCacheCopiesValues(t *testing.T) {
+	ctx := context.Background()
+	c := memory.NewMemoryCache()
+
+	v := []byte("abc")
+	_ = c.Set(ctx, "k", v, 0)
+	v[0] = 'x'
+	got, _ := c.Get(ctx, "k")
+	got[1] = 'y'
+	if again, _ := c.Get(ctx, "k"); string(again) != "abc" {
+		t.Fatalf("expected stored values to be isolated from callers, got %q", again)
+	}
+}
@@ -51,4 +51,6 @@ func TestRedisIntegration(t *testing.T) {
 	if err != cache.ErrNotFound {
 		t.Fatalf("unexpected error: %v", err)
 	}
+
+	runCacheContract(t, r, "integration:contract:", time.Sleep)
 }
@@ -0,0 +1,180 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cache "guiltmachine/internal/cache"
+	"guiltmachine/internal/cache/memory"
+)
+
+func TestTieredCacheContract(t *testing.T) {
+	clk := &clock{now: time.Unix(1700000000, 0)}
+	l1 := memory.NewMemoryCacheWithClock(clk.Now)
+	l2 := memory.NewMemoryCacheWithClock(clk.Now)
+	runCacheContract(t, cache.NewTieredCache(l1, l2, time.Minute), "", clk.Advance)
+}
+
+func TestTieredCacheServesHotKeysLocally(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{now: time.Unix(1700000000, 0)}
+	l1 := memory.NewMemoryCacheWithClock(clk.Now)
+	l2 := memory.NewMemoryCacheWithClock(clk.Now)
+	tiers := cache.NewTieredCache(l1, l2, time.Minute)
+
+	_ = l2.Set(ctx, "k", []byte("v1"), 0)
+	if v, _ := tiers.Get(ctx, "k"); string(v) != "v1" {
+		t.Fatalf("expected a miss to read L2, got %q", v)
+	}
+
+	// a change behind the tiers' back is not seen until the L1 copy expires
+	_ = l2.Set(ctx, "k", []byte("v2"), 0)
+	if v, _ := tiers.Get(ctx, "k"); string(v) != "v1" {
+		t.Fatalf("expected the L1 copy, got %q", v)
+	}
+	clk.Advance(2 * time.Minute)
+	if v, _ := tiers.Get(ctx, "k"); string(v) != "v2" {
+		t.Fatalf("expected the expired L1 copy to be refreshed, got %q", v)
+	}
+
+	// L1 copies never outlive the value in L2
+	_ = tiers.Set(ctx, "short", []byte("v"), 10*time.Second)
+	clk.Advance(20 * time.Second)
+	if _, err := tiers.Get(ctx, "short"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected the short-lived value to expire in both tiers, got %v", err)
+	}
+}
+
+func TestTieredCacheInvalidatesOtherProcesses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l2 := memory.NewMemoryCache()
+	invalidations := cache.NewMemoryInvalidations()
+
+	newProcess := func() *cache.TieredCache {
+		tiers := cache.NewTieredCache(memory.NewMemoryCache(), l2, time.Hour)
+		go tiers.ListenInvalidations(ctx, invalidations)
+		return tiers
+	}
+	a, b := newProcess(), newProcess()
+	time.Sleep(10 * time.Millisecond) // subscriptions are set up asynchronously
+
+	_ = a.Set(ctx, "k", []byte("v1"), 0)
+	if v, _ := b.Get(ctx, "k"); string(v) != "v1" {
+		t.Fatalf("expected b to read the shared value, got %q", v)
+	}
+
+	// writers announce their changes
+	_ = a.Set(ctx, "k", []byte("v2"), 0)
+	_ = invalidations.PublishInvalidation(ctx, "k")
+	waitFor(t, func() bool {
+		v, _ := b.Get(ctx, "k")
+		return string(v) == "v2"
+	})
+
+	_ = a.Del(ctx, "k")
+	_ = invalidations.PublishInvalidation(ctx, "k")
+	waitFor(t, func() bool {
+		_, err := b.Get(ctx, "k")
+		return errors.Is(err, cache.ErrNotFound)
+	})
+}
+
+// slowGetCache holds Gets until release is closed
+type slowGetCache struct {
+	cache.Cache
+	reading chan struct{}
+	release chan struct{}
+}
+
+func (c *slowGetCache) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := c.Cache.Get(ctx, key)
+	close(c.reading)
+	<-c.release
+	return v, err
+}
+
+func TestTieredCacheSkipsFillEvictedDuringRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l1 := memory.NewMemoryCache()
+	l2 := &slowGetCache{Cache: memory.NewMemoryCache(), reading: make(chan struct{}), release: make(chan struct{})}
+	tiers := cache.NewTieredCache(l1, l2, time.Hour)
+	invalidations := cache.NewMemoryInvalidations()
+	go tiers.ListenInvalidations(ctx, invalidations)
+	time.Sleep(10 * time.Millisecond) // subscriptions are set up asynchronously
+	_ = l2.Cache.Set(ctx, "k", []byte("v1"), 0)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = tiers.Get(ctx, "k")
+	}()
+	<-l2.reading
+	// another process changes the value and announces it mid-read
+	_ = l2.Cache.Set(ctx, "k", []byte("v2"), 0)
+	_ = invalidations.PublishInvalidation(ctx, "k")
+	time.Sleep(10 * time.Millisecond)
+	close(l2.release)
+	<-done
+
+	if _, err := l1.Get(ctx, "k"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("expected the stale read to leave L1 empty, got %v", err)
+	}
+}
+
+// flakyInvalidations hands out subscriptions the test can break
+type flakyInvalidations struct {
+	*cache.MemoryInvalidations
+	subscribed chan context.CancelFunc
+}
+
+func (f *flakyInvalidations) SubscribeInvalidations(ctx context.Context) (<-chan string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	f.subscribed <- cancel
+	return f.MemoryInvalidations.SubscribeInvalidations(ctx)
+}
+
+func TestTieredCacheFlushesAfterResubscribing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l1, l2 := memory.NewMemoryCache(), memory.NewMemoryCache()
+	tiers := cache.NewTieredCache(l1, l2, time.Hour)
+	inv := &flakyInvalidations{MemoryInvalidations: cache.NewMemoryInvalidations(), subscribed: make(chan context.CancelFunc, 1)}
+	go tiers.ListenInvalidations(ctx, inv)
+	breakSubscription := <-inv.subscribed
+
+	_ = l2.Set(ctx, "k", []byte("v1"), 0)
+	if v, _ := tiers.Get(ctx, "k"); string(v) != "v1" {
+		t.Fatalf("expected the shared value, got %q", v)
+	}
+
+	// the change is announced while the subscription is down
+	breakSubscription()
+	time.Sleep(10 * time.Millisecond)
+	_ = l2.Set(ctx, "k", []byte("v2"), 0)
+	_ = inv.PublishInvalidation(ctx, "k")
+
+	select {
+	case <-inv.subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the listener to subscribe again")
+	}
+	waitFor(t, func() bool {
+		v, _ := tiers.Get(ctx, "k")
+		return string(v) == "v2"
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,84 @@
+package events_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/events"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// testBus checks the contract every events.Bus implementation must meet
+func testBus(t *testing.T, bus events.Bus) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	session := uuid.NewString()
+	other := uuid.NewString()
+
+	evs, err := bus.SubscribeSession(ctx, session)
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	for _, ev := range []events.EntryEvent{
+		{EntryID: "e1", SessionID: other, Status: "processing"},
+		{EntryID: "e1", SessionID: session, Status: "processing"},
+		{EntryID: "e1", SessionID: session, Status: "completed", RoastText: "nice one", GuiltScore: 42},
+	} {
+		if err := bus.PublishEntry(ctx, ev); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+	}
+
+	next := func() events.EntryEvent {
+		t.Helper()
+		select {
+		case ev := <-evs:
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for event")
+			return events.EntryEvent{}
+		}
+	}
+
+	if ev := next(); ev.SessionID != session || ev.Status != "processing" {
+		t.Fatalf("expected own processing event first, got %+v", ev)
+	}
+	if ev := next(); ev.Status != "completed" || ev.RoastText != "nice one" || ev.GuiltScore != 42 {
+		t.Fatalf("unexpected completed event %+v", ev)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-evs:
+		if ok {
+			t.Fatalf("expected no further events")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("subscription not closed after cancel")
+	}
+}
+
+func TestMemoryBus(t *testing.T) {
+	testBus(t, events.NewMemoryBus())
+}
+
+func TestRedisBus(t *testing.T) {
+	url := os.Getenv("TEST_REDIS_URL")
+	if url == "" {
+		t.Fatal("TEST_REDIS_URL not set")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("invalid TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	testBus(t, events.NewRedisBus(client))
+}
@@ -0,0 +1,886 @@
+// Package fakes provides in-memory repository implementations so service and
+// transport tests can run without Postgres.
+package fakes
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	sqlc "guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// ErrDuplicateEmail mirrors the unique constraint on users.email
+var ErrDuplicateEmail = errors.New("duplicate email")
+
+// Repos bundles one fake per repository interface, sharing a single store
+type Repos struct {
+	Users       *UsersRepo
+	Sessions    *SessionsRepo
+	Entries     *EntriesRepo
+	Scores      *ScoresRepo
+	Preferences *PreferencesRepo
+	Tasks       *TasksRepo
+	TaskEvents  *TaskEventsRepo
+	Features    *FeatureSnapshotsRepo
+	Outbox      *OutboxRepo
+}
+
+func NewRepos() *Repos {
+	s := &store{
+		users:    map[uuid.UUID]sqlc.User{},
+		sessions: map[uuid.UUID]sqlc.GuiltSession{},
+		entries:  map[uuid.UUID]sqlc.GuiltEntry{},
+		prefs:    map[uuid.UUID]sqlc.UserPreference{},
+		tasks:    map[uuid.UUID]sqlc.Task{},
+	}
+	return &Repos{
+		Users:       &UsersRepo{s},
+		Sessions:    &SessionsRepo{s},
+		Entries:     &EntriesRepo{s},
+		Scores:      &ScoresRepo{s},
+		Preferences: &PreferencesRepo{s},
+		Tasks:       &TasksRepo{s},
+		TaskEvents:  &TaskEventsRepo{s},
+		Features:    &FeatureSnapshotsRepo{s},
+		Outbox:      &OutboxRepo{s: s},
+	}
+}
+
+type store struct {
+	mu       sync.Mutex
+	users    map[uuid.UUID]sqlc.User
+	sessions map[uuid.UUID]sqlc.GuiltSession
+	entries  map[uuid.UUID]sqlc.GuiltEntry
+	scores   []sqlc.GuiltScore
+	prefs    map[uuid.UUID]sqlc.UserPreference
+	tasks    map[uuid.UUID]sqlc.Task
+	events   []sqlc.TaskEvent
+	// snapshots in the order they were created
+	snapshots []sqlc.FeatureSnapshot
+	clusters  []sqlc.ClusterModel
+	outbox    []sqlc.Outbox
+}
+
+var (
+	_ repository.UsersRepository            = (*UsersRepo)(nil)
+	_ repository.SessionsRepository         = (*SessionsRepo)(nil)
+	_ repository.EntriesRepository          = (*EntriesRepo)(nil)
+	_ repository.ScoresRepository           = (*ScoresRepo)(nil)
+	_ repository.PreferencesRepository      = (*PreferencesRepo)(nil)
+	_ repository.TasksRepository            = (*TasksRepo)(nil)
+	_ repository.TaskEventsRepository       = (*TaskEventsRepo)(nil)
+	_ repository.FeatureSnapshotsRepository = (*FeatureSnapshotsRepo)(nil)
+	_ repository.OutboxRepository           = (*OutboxRepo)(nil)
+)
+
+// USERS
+
+type UsersRepo struct{ s *store }
+
+func (r *UsersRepo) CreateUser(ctx context.Context, email string, passwordHash string) (sqlc.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, u := range r.s.users {
+		if u.Email == email {
+			return sqlc.User{}, ErrDuplicateEmail
+		}
+	}
+	now := time.Now()
+	u := sqlc.User{ID: uuid.New(), Email: email, PasswordHash: passwordHash, CreatedAt: now, UpdatedAt: now}
+	r.s.users[u.ID] = u
+	return u, nil
+}
+
+func (r *UsersRepo) GetUserByID(ctx context.Context, id uuid.UUID) (sqlc.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	u, ok := r.s.users[id]
+	if !ok {
+		return sqlc.User{}, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func (r *UsersRepo) GetUserByEmail(ctx context.Context, email string) (sqlc.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, u := range r.s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return sqlc.User{}, sql.ErrNoRows
+}
+
+// SESSIONS
+
+type SessionsRepo struct{ s *store }
+
+func (r *SessionsRepo) CreateSession(ctx context.Context, userID uuid.UUID, notes *string) (sqlc.GuiltSession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.users[userID]; !ok {
+		return sqlc.GuiltSession{}, sql.ErrNoRows
+	}
+	var ns sql.NullString
+	if notes != nil {
+		ns = sql.NullString{String: *notes, Valid: true}
+	}
+	now := time.Now()
+	sess := sqlc.GuiltSession{ID: uuid.New(), UserID: userID, StartTime: now, Notes: ns, CreatedAt: now, UpdatedAt: now}
+	r.s.sessions[sess.ID] = sess
+	return sess, nil
+}
+
+func (r *SessionsRepo) EndSession(ctx context.Context, sessionID uuid.UUID) (sqlc.GuiltSession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	sess, ok := r.s.sessions[sessionID]
+	if !ok {
+		return sqlc.GuiltSession{}, sql.ErrNoRows
+	}
+	sess.EndTime = sql.NullTime{Time: time.Now(), Valid: true}
+	r.s.sessions[sessionID] = sess
+	return sess, nil
+}
+
+func (r *SessionsRepo) GetSessionByID(ctx context.Context, id uuid.UUID) (sqlc.GuiltSession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	sess, ok := r.s.sessions[id]
+	if !ok {
+		return sqlc.GuiltSession{}, sql.ErrNoRows
+	}
+	return sess, nil
+}
+
+func (r *SessionsRepo) ListSessionsByUser(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]sqlc.GuiltSession, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.GuiltSession
+	for _, sess := range r.s.sessions {
+		if sess.UserID == userID {
+			out = append(out, sess)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartTime.After(out[j].StartTime) })
+	return page(out, limit, offset), nil
+}
+
+// ENTRIES
+
+type EntriesRepo struct{ s *store }
+
+func (r *EntriesRepo) CreateEntry(ctx context.Context, sessionID uuid.UUID, text string, level int32) (sqlc.GuiltEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.sessions[sessionID]; !ok {
+		return sqlc.GuiltEntry{}, sql.ErrNoRows
+	}
+	now := time.Now()
+	e := sqlc.GuiltEntry{
+		ID:         uuid.New(),
+		SessionID:  sessionID,
+		EntryText:  text,
+		GuiltLevel: sql.NullInt32{Int32: level, Valid: true},
+		Status:     sql.NullString{String: "pending", Valid: true},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	r.s.entries[e.ID] = e
+	return e, nil
+}
+
+func (r *EntriesRepo) CreateEntryWithOutbox(ctx context.Context, sessionID uuid.UUID, text string, level int32, topic string, payload func(sqlc.GuiltEntry) ([]byte, error)) (sqlc.GuiltEntry, error) {
+	e, err := r.CreateEntry(ctx, sessionID, text, level)
+	if err != nil {
+		return sqlc.GuiltEntry{}, err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	body, err := payload(e)
+	if err != nil {
+		// roll back the insert
+		delete(r.s.entries, e.ID)
+		return sqlc.GuiltEntry{}, err
+	}
+	r.s.outbox = append(r.s.outbox, sqlc.Outbox{
+		ID:        int64(len(r.s.outbox) + 1),
+		Topic:     topic,
+		Payload:   body,
+		CreatedAt: time.Now(),
+		EntryID:   uuid.NullUUID{UUID: e.ID, Valid: true},
+	})
+	return e, nil
+}
+
+func (r *EntriesRepo) ListEntriesBySession(ctx context.Context, sessionID uuid.UUID) ([]sqlc.GuiltEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.GuiltEntry
+	for _, e := range r.s.entries {
+		if e.SessionID == sessionID {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (r *EntriesRepo) ListEntriesBefore(ctx context.Context, entryID uuid.UUID, acrossSessions bool, limit int32) ([]sqlc.GuiltEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	cur, ok := r.s.entries[entryID]
+	if !ok {
+		return nil, nil
+	}
+	owner := r.s.sessions[cur.SessionID].UserID
+	var out []sqlc.GuiltEntry
+	for _, e := range r.s.entries {
+		if !e.CreatedAt.Before(cur.CreatedAt) {
+			continue
+		}
+		if e.SessionID == cur.SessionID || (acrossSessions && r.s.sessions[e.SessionID].UserID == owner) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	if len(out) > int(limit) {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *EntriesRepo) UpdateRoast(ctx context.Context, entryID uuid.UUID, roastText sql.NullString) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, ok := r.s.entries[entryID]
+	if !ok {
+		return nil
+	}
+	e.RoastText = roastText
+	r.s.entries[entryID] = e
+	return nil
+}
+
+func (r *EntriesRepo) UpdateEntryStatus(ctx context.Context, entryID uuid.UUID, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, ok := r.s.entries[entryID]
+	if !ok {
+		return nil
+	}
+	e.Status = sql.NullString{String: status, Valid: true}
+	r.s.entries[entryID] = e
+	return nil
+}
+
+func (r *EntriesRepo) GetEntry(ctx context.Context, entryID uuid.UUID) (sqlc.GuiltEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, ok := r.s.entries[entryID]
+	if !ok {
+		return sqlc.GuiltEntry{}, sql.ErrNoRows
+	}
+	return e, nil
+}
+
+func (r *EntriesRepo) ClaimEntry(ctx context.Context, entryID uuid.UUID, jobKey string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, ok := r.s.entries[entryID]
+	if !ok {
+		return false, nil
+	}
+	switch e.Status.String {
+	case "pending", "failed", "":
+	case "processing":
+		if e.MlJobKey.String != jobKey {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+	e.Status = sql.NullString{String: "processing", Valid: true}
+	e.MlJobKey = sql.NullString{String: jobKey, Valid: true}
+	e.MlAttempts++
+	r.s.entries[entryID] = e
+	return true, nil
+}
+
+func (r *EntriesRepo) FinishEntry(ctx context.Context, entryID uuid.UUID, jobKey string, status string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, ok := r.s.entries[entryID]
+	if !ok || e.Status.String != "processing" || e.MlJobKey.String != jobKey {
+		return false, nil
+	}
+	e.Status = sql.NullString{String: status, Valid: true}
+	r.s.entries[entryID] = e
+	return true, nil
+}
+
+// OUTBOX
+
+type OutboxRepo struct{ s *store }
+
+// outboxClaim matches the claim of the sqlc repository
+const outboxClaim = time.Minute
+
+func (r *OutboxRepo) RelayBatch(ctx context.Context, limit int32, publish func(context.Context, sqlc.Outbox) error) (int, error) {
+	r.s.mu.Lock()
+	now := time.Now()
+	var batch []sqlc.Outbox
+	for i := range r.s.outbox {
+		m := &r.s.outbox[i]
+		if m.SentAt.Valid || m.DeadAt.Valid || (m.ClaimedUntil.Valid && !m.ClaimedUntil.Time.Before(now)) || int32(len(batch)) >= limit {
+			continue
+		}
+		m.ClaimedUntil = sql.NullTime{Time: now.Add(outboxClaim), Valid: true}
+		batch = append(batch, *m)
+	}
+	r.s.mu.Unlock()
+
+	sent := 0
+	for i, m := range batch {
+		pubErr := publish(ctx, m)
+		dead := errors.Is(pubErr, repository.ErrUndeliverable)
+
+		r.s.mu.Lock()
+		row := &r.s.outbox[m.ID-1]
+		row.ClaimedUntil = sql.NullTime{}
+		if pubErr != nil {
+			row.Attempts++
+			row.LastError = sql.NullString{String: pubErr.Error(), Valid: true}
+			if dead {
+				row.DeadAt = sql.NullTime{Time: time.Now(), Valid: true}
+				if e, ok := r.s.entries[row.EntryID.UUID]; ok && row.EntryID.Valid && e.Status.String == "pending" {
+					e.Status = sql.NullString{String: "failed", Valid: true}
+					r.s.entries[e.ID] = e
+				}
+			} else {
+				for _, rest := range batch[i+1:] {
+					r.s.outbox[rest.ID-1].ClaimedUntil = sql.NullTime{}
+				}
+			}
+		} else {
+			row.SentAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+		r.s.mu.Unlock()
+
+		if dead {
+			continue
+		}
+		if pubErr != nil {
+			break
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (r *OutboxRepo) DeleteSent(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for i := range r.s.outbox {
+		m := &r.s.outbox[i]
+		// rows are tombstoned rather than removed so IDs keep indexing the slice
+		if m.SentAt.Valid && m.SentAt.Time.Before(before) && m.Payload != nil {
+			m.Payload = nil
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Pending returns the outbox messages that are still to be sent
+func (r *OutboxRepo) Pending() []sqlc.Outbox {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.Outbox
+	for _, m := range r.s.outbox {
+		if !m.SentAt.Valid && !m.DeadAt.Valid {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// Dead returns the outbox messages set aside as undeliverable
+func (r *OutboxRepo) Dead() []sqlc.Outbox {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.Outbox
+	for _, m := range r.s.outbox {
+		if m.DeadAt.Valid {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// SCORES
+
+type ScoresRepo struct{ s *store }
+
+func (r *ScoresRepo) CreateScore(ctx context.Context, sessionID uuid.UUID, entryID *uuid.UUID, score int32, meta any) (sqlc.GuiltScore, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.sessions[sessionID]; !ok {
+		return sqlc.GuiltScore{}, sql.ErrNoRows
+	}
+	now := time.Now()
+	sc := sqlc.GuiltScore{
+		ID:             uuid.New(),
+		SessionID:      sessionID,
+		AggregateScore: score,
+		Meta:           rawMessage(meta),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if entryID != nil {
+		sc.EntryID = uuid.NullUUID{UUID: *entryID, Valid: true}
+	}
+	r.s.scores = append(r.s.scores, sc)
+	return sc, nil
+}
+
+func (r *ScoresRepo) GetScoreBySession(ctx context.Context, sessionID uuid.UUID) (sqlc.GuiltScore, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := len(r.s.scores) - 1; i >= 0; i-- {
+		if r.s.scores[i].SessionID == sessionID {
+			return r.s.scores[i], nil
+		}
+	}
+	return sqlc.GuiltScore{}, sql.ErrNoRows
+}
+
+func (r *ScoresRepo) GetScoreByEntry(ctx context.Context, entryID uuid.UUID) (sqlc.GuiltScore, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := len(r.s.scores) - 1; i >= 0; i-- {
+		if r.s.scores[i].EntryID.Valid && r.s.scores[i].EntryID.UUID == entryID {
+			return r.s.scores[i], nil
+		}
+	}
+	return sqlc.GuiltScore{}, sql.ErrNoRows
+}
+
+// UpsertEntryScore mirrors the unique constraint on guilt_scores.entry_id
+func (r *ScoresRepo) UpsertEntryScore(ctx context.Context, sessionID uuid.UUID, entryID uuid.UUID, score int32, meta any) (sqlc.GuiltScore, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	for i, sc := range r.s.scores {
+		if sc.EntryID.Valid && sc.EntryID.UUID == entryID {
+			sc.AggregateScore = score
+			sc.Meta = rawMessage(meta)
+			sc.UpdatedAt = now
+			r.s.scores[i] = sc
+			return sc, nil
+		}
+	}
+	if _, ok := r.s.sessions[sessionID]; !ok {
+		return sqlc.GuiltScore{}, sql.ErrNoRows
+	}
+	sc := sqlc.GuiltScore{
+		ID:             uuid.New(),
+		SessionID:      sessionID,
+		EntryID:        uuid.NullUUID{UUID: entryID, Valid: true},
+		AggregateScore: score,
+		Meta:           rawMessage(meta),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	r.s.scores = append(r.s.scores, sc)
+	return sc, nil
+}
+
+// ScoresForEntry returns every score row of an entry
+func (r *ScoresRepo) ScoresForEntry(entryID uuid.UUID) []sqlc.GuiltScore {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.GuiltScore
+	for _, sc := range r.s.scores {
+		if sc.EntryID.Valid && sc.EntryID.UUID == entryID {
+			out = append(out, sc)
+		}
+	}
+	return out
+}
+
+// PREFERENCES
+
+type PreferencesRepo struct{ s *store }
+
+func (r *PreferencesRepo) UpsertPreferences(ctx context.Context, pref sqlc.UserPreference) (sqlc.UserPreference, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.save(pref), nil
+}
+
+func (r *PreferencesRepo) UpdatePreferences(ctx context.Context, userID uuid.UUID, update func(*sqlc.UserPreference) error) (sqlc.UserPreference, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	p, ok := r.s.prefs[userID]
+	if !ok {
+		// the column defaults of user_preferences
+		p = sqlc.UserPreference{
+			UserID:               userID,
+			NotificationsEnabled: true,
+			Persona:              "roast",
+			HumorIntensity:       3,
+			Timezone:             "UTC",
+			Language:             "en",
+			NudgeChannels:        []string{},
+		}
+	}
+	p.NudgeChannels = append([]string(nil), p.NudgeChannels...)
+	if err := update(&p); err != nil {
+		return sqlc.UserPreference{}, err
+	}
+	p.UserID = userID
+	return r.save(p), nil
+}
+
+// save stores the settable fields of pref; callers hold the lock
+func (r *PreferencesRepo) save(pref sqlc.UserPreference) sqlc.UserPreference {
+	now := time.Now()
+	p, ok := r.s.prefs[pref.UserID]
+	if !ok {
+		p = sqlc.UserPreference{ID: uuid.New(), UserID: pref.UserID, CreatedAt: now}
+	}
+	p.Theme = pref.Theme
+	p.NotificationsEnabled = pref.NotificationsEnabled
+	p.Metadata = pref.Metadata
+	p.Persona = pref.Persona
+	p.HumorIntensity = pref.HumorIntensity
+	p.Timezone = pref.Timezone
+	p.Language = pref.Language
+	p.WorkStartMinute = pref.WorkStartMinute
+	p.WorkEndMinute = pref.WorkEndMinute
+	p.QuietStartMinute = pref.QuietStartMinute
+	p.QuietEndMinute = pref.QuietEndMinute
+	p.NudgeChannels = append([]string{}, pref.NudgeChannels...)
+	p.UpdatedAt = now
+	r.s.prefs[pref.UserID] = p
+	return p
+}
+
+func (r *PreferencesRepo) GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) (sqlc.UserPreference, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	p, ok := r.s.prefs[userID]
+	if !ok {
+		return sqlc.UserPreference{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+// TASKS
+
+type TasksRepo struct{ s *store }
+
+func (r *TasksRepo) CreateTask(ctx context.Context, task sqlc.Task, event sqlc.TaskEvent) (sqlc.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.users[task.UserID]; !ok {
+		return sqlc.Task{}, sql.ErrNoRows
+	}
+	now := time.Now()
+	t := sqlc.Task{
+		ID:               uuid.New(),
+		UserID:           task.UserID,
+		Title:            task.Title,
+		Description:      task.Description,
+		Status:           "pending",
+		DueAt:            task.DueAt,
+		Category:         task.Category,
+		Priority:         task.Priority,
+		EstimatedMinutes: task.EstimatedMinutes,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	r.s.tasks[t.ID] = t
+	r.s.appendTaskEvent(t, event)
+	return t, nil
+}
+
+func (r *TasksRepo) GetTask(ctx context.Context, id uuid.UUID) (sqlc.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	t, ok := r.s.tasks[id]
+	if !ok || t.DeletedAt.Valid {
+		return sqlc.Task{}, sql.ErrNoRows
+	}
+	return t, nil
+}
+
+func (r *TasksRepo) ListTasksByUser(ctx context.Context, userID uuid.UUID, status string, limit int32, offset int32) ([]sqlc.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.Task
+	for _, t := range r.s.tasks {
+		if t.UserID == userID && !t.DeletedAt.Valid && (status == "" || t.Status == status) {
+			out = append(out, t)
+		}
+	}
+	// due_at ASC NULLS LAST, created_at DESC
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.DueAt.Valid != b.DueAt.Valid {
+			return a.DueAt.Valid
+		}
+		if a.DueAt.Valid && !a.DueAt.Time.Equal(b.DueAt.Time) {
+			return a.DueAt.Time.Before(b.DueAt.Time)
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return page(out, limit, offset), nil
+}
+
+func (r *TasksRepo) UpdateTask(ctx context.Context, id uuid.UUID, update func(*sqlc.Task) (*sqlc.TaskEvent, error)) (sqlc.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	cur, ok := r.s.tasks[id]
+	if !ok || cur.DeletedAt.Valid {
+		return sqlc.Task{}, sql.ErrNoRows
+	}
+	t := cur
+	event, err := update(&t)
+	if err != nil {
+		return sqlc.Task{}, err
+	}
+	cur.Title = t.Title
+	cur.Description = t.Description
+	cur.Status = t.Status
+	cur.DueAt = t.DueAt
+	cur.Category = t.Category
+	cur.Priority = t.Priority
+	cur.EstimatedMinutes = t.EstimatedMinutes
+	cur.SnoozeCount = t.SnoozeCount
+	cur.UpdatedAt = time.Now()
+	r.s.tasks[id] = cur
+	if event != nil {
+		r.s.appendTaskEvent(cur, *event)
+	}
+	return cur, nil
+}
+
+func (r *TasksRepo) DeleteTask(ctx context.Context, id uuid.UUID, event sqlc.TaskEvent) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	t, ok := r.s.tasks[id]
+	if !ok || t.DeletedAt.Valid {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	t.Status = "deleted"
+	t.DeletedAt = sql.NullTime{Time: now, Valid: true}
+	t.UpdatedAt = now
+	r.s.tasks[id] = t
+	r.s.appendTaskEvent(t, event)
+	return nil
+}
+
+// appendTaskEvent records event for task; callers hold the lock
+func (s *store) appendTaskEvent(task sqlc.Task, event sqlc.TaskEvent) {
+	payload := event.EventPayload
+	if len(payload) == 0 {
+		payload = json.RawMessage("{}")
+	}
+	s.events = append(s.events, sqlc.TaskEvent{
+		ID:           uuid.New(),
+		TaskID:       task.ID,
+		UserID:       task.UserID,
+		EventType:    event.EventType,
+		EventPayload: payload,
+		CreatedAt:    time.Now(),
+	})
+}
+
+// TASK EVENTS
+
+type TaskEventsRepo struct{ s *store }
+
+func (r *TaskEventsRepo) ListTaskEventsByUser(ctx context.Context, userID uuid.UUID, types []string, since, until time.Time, limit int32, offset int32) ([]sqlc.TaskEvent, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.TaskEvent
+	for _, e := range r.s.events {
+		if e.UserID != userID {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, e.EventType) {
+			continue
+		}
+		if (!since.IsZero() && e.CreatedAt.Before(since)) || (!until.IsZero() && !e.CreatedAt.Before(until)) {
+			continue
+		}
+		out = append(out, e)
+	}
+	// events are appended in time order already
+	return page(out, limit, offset), nil
+}
+
+// helpers
+
+func rawMessage(v any) pqtype.NullRawMessage {
+	if v == nil {
+		return pqtype.NullRawMessage{}
+	}
+	b, _ := json.Marshal(v)
+	return pqtype.NullRawMessage{RawMessage: b, Valid: true}
+}
+
+func page[T any](items []T, limit, offset int32) []T {
+	if offset > 0 {
+		if int(offset) >= len(items) {
+			return nil
+		}
+		items = items[offset:]
+	}
+	if limit > 0 && int(limit) < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// FEATURE SNAPSHOTS
+
+type FeatureSnapshotsRepo struct{ s *store }
+
+func (r *FeatureSnapshotsRepo) ListActiveUsers(ctx context.Context, since time.Time, after uuid.UUID, limit int32) ([]uuid.UUID, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	active := map[uuid.UUID]bool{}
+	for _, sess := range r.s.sessions {
+		if !sess.StartTime.Before(since) {
+			active[sess.UserID] = true
+		}
+	}
+	for _, e := range r.s.entries {
+		if !e.CreatedAt.Before(since) {
+			active[r.s.sessions[e.SessionID].UserID] = true
+		}
+	}
+	for _, e := range r.s.events {
+		if !e.CreatedAt.Before(since) {
+			active[e.UserID] = true
+		}
+	}
+
+	var out []uuid.UUID
+	for id := range active {
+		// postgres orders uuids by their bytes
+		if bytes.Compare(id[:], after[:]) > 0 {
+			out = append(out, id)
+		}
+	}
+	slices.SortFunc(out, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	return page(out, limit, 0), nil
+}
+
+func (r *FeatureSnapshotsRepo) GetUserSignals(ctx context.Context, userID uuid.UUID, since, until time.Time) (repository.UserSignals, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	in := func(t time.Time) bool { return !t.Before(since) && t.Before(until) }
+
+	var signals repository.UserSignals
+	for _, sess := range r.s.sessions {
+		if sess.UserID == userID && in(sess.StartTime) {
+			signals.SessionStarts = append(signals.SessionStarts, sess.StartTime)
+		}
+	}
+	for _, e := range r.s.entries {
+		if r.s.sessions[e.SessionID].UserID != userID || !in(e.CreatedAt) {
+			continue
+		}
+		row := sqlc.ListEntrySignalsByUserRow{CreatedAt: e.CreatedAt, GuiltLevel: e.GuiltLevel}
+		for _, sc := range r.s.scores {
+			if sc.EntryID.Valid && sc.EntryID.UUID == e.ID {
+				row.AggregateScore = sql.NullInt32{Int32: sc.AggregateScore, Valid: true}
+			}
+		}
+		signals.Entries = append(signals.Entries, row)
+	}
+	for _, e := range r.s.events {
+		if e.UserID == userID && in(e.CreatedAt) {
+			signals.TaskEvents = append(signals.TaskEvents, e)
+		}
+	}
+	slices.SortFunc(signals.SessionStarts, func(a, b time.Time) int { return a.Compare(b) })
+	slices.SortFunc(signals.Entries, func(a, b sqlc.ListEntrySignalsByUserRow) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return signals, nil
+}
+
+func (r *FeatureSnapshotsRepo) CreateFeatureSnapshot(ctx context.Context, snap sqlc.FeatureSnapshot) (sqlc.FeatureSnapshot, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.users[snap.UserID]; !ok {
+		return sqlc.FeatureSnapshot{}, sql.ErrNoRows
+	}
+	snap.ID = uuid.New()
+	snap.CreatedAt = time.Now()
+	r.s.snapshots = append(r.s.snapshots, snap)
+	return snap, nil
+}
+
+func (r *FeatureSnapshotsRepo) GetLatestFeatureSnapshot(ctx context.Context, userID uuid.UUID) (sqlc.FeatureSnapshot, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := len(r.s.snapshots) - 1; i >= 0; i-- {
+		if r.s.snapshots[i].UserID == userID {
+			return r.s.snapshots[i], nil
+		}
+	}
+	return sqlc.FeatureSnapshot{}, sql.ErrNoRows
+}
+
+func (r *FeatureSnapshotsRepo) ListFeatureSnapshotsByUser(ctx context.Context, userID uuid.UUID, limit int32, offset int32) ([]sqlc.FeatureSnapshot, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var out []sqlc.FeatureSnapshot
+	for i := len(r.s.snapshots) - 1; i >= 0; i-- {
+		if r.s.snapshots[i].UserID == userID {
+			out = append(out, r.s.snapshots[i])
+		}
+	}
+	return page(out, limit, offset), nil
+}
+
+func (r *FeatureSnapshotsRepo) CreateClusterModel(ctx context.Context, centroids json.RawMessage, sampleSize int32) (sqlc.ClusterModel, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	m := sqlc.ClusterModel{
+		Version:    int32(len(r.s.clusters) + 1),
+		Centroids:  append(json.RawMessage{}, centroids...),
+		SampleSize: sampleSize,
+		CreatedAt:  time.Now(),
+	}
+	r.s.clusters = append(r.s.clusters, m)
+	return m, nil
+}
+
+func (r *FeatureSnapshotsRepo) GetLatestClusterModel(ctx context.Context) (sqlc.ClusterModel, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if len(r.s.clusters) == 0 {
+		return sqlc.ClusterModel{}, sql.ErrNoRows
+	}
+	return r.s.clusters[len(r.s.clusters)-1], nil
+}
@@ -11,9 +11,12 @@ import (
 	cacheDomain "guiltmachine/internal/cache/domain"
 	cacheRedis "guiltmachine/internal/cache/redis"
 	"guiltmachine/internal/ml"
+	"guiltmachine/internal/outbox"
 	"guiltmachine/internal/queue"
+	"guiltmachine/internal/repository"
 	reposqlc "guiltmachine/internal/repository/sqlc"
 	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
 
 	"github.com/redis/go-redis/v9"
 
@@ -27,9 +30,44 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-// TestFullPipeline validates the complete end-to-end flow:
-// User → Session+JWT → Entry(pending) → Worker(ML) → Entry(completed) + Score + Roast
+// pipeline is everything the end-to-end flow needs; only storage differs between runs
+type pipeline struct {
+	users       repository.UsersRepository
+	sessions    repository.SessionsRepository
+	entries     repository.EntriesRepository
+	scores      repository.ScoresRepository
+	preferences repository.PreferencesRepository
+	outbox      repository.OutboxRepository
+
+	sessionCache *cacheDomain.SessionCache
+	prefsCache   *cacheDomain.PreferencesCache
+	backend      queue.Backend
+}
+
+// TestFullPipelineInProcess runs the pipeline on in-memory repositories and
+// the in-memory queue backend, so it needs neither docker nor a database
+func TestFullPipelineInProcess(t *testing.T) {
+	repos := fakes.NewRepos()
+	runPipeline(t, pipeline{
+		users:       repos.Users,
+		sessions:    repos.Sessions,
+		entries:     repos.Entries,
+		scores:      repos.Scores,
+		preferences: repos.Preferences,
+		outbox:      repos.Outbox,
+		backend:     queue.NewMemoryBackend(time.Minute),
+	})
+}
+
+// TestFullPipeline runs the pipeline against Postgres and Redis
 func TestFullPipeline(t *testing.T) {
+	runPipeline(t, integrationPipeline(t))
+}
+
+// integrationPipeline connects to Postgres and Redis, skipping the test
+// unless INTEGRATION_TEST=true
+func integrationPipeline(t *testing.T) pipeline {
+	t.Helper()
 	ctx := context.Background()
 
 	// Skip if not in integration test mode
@@ -46,7 +84,7 @@ func TestFullPipeline(t *testing.T) {
 	if err != nil {
 		t.Fatalf("db open failed: %v", err)
 	}
-	defer db.Close()
+	t.Cleanup(func() { db.Close() })
 
 	if err := db.PingContext(ctx); err != nil {
 		t.Fatalf("db ping failed: %v", err)
@@ -63,30 +101,47 @@ func TestFullPipeline(t *testing.T) {
 	}
 	redisCache := cacheRedis.NewRedisCache(redisClient)
 
-	sessionCache := cacheDomain.NewSessionCache(redisCache)
-	prefsCache := cacheDomain.NewPreferencesCache(redisCache)
+	// Setup queue
+	queueRedis := redis.NewClient(&redis.Options{Addr: redisAddr})
+	stream := queue.NewStreams(queueRedis, "ml:entries:test")
+	_ = stream.EnsureGroup(ctx, "ml-workers-test")
+
+	return pipeline{
+		users:        repos.Users,
+		sessions:     repos.Sessions,
+		entries:      repos.Entries,
+		scores:       repos.Scores,
+		preferences:  repos.Preferences,
+		outbox:       repos.Outbox,
+		sessionCache: cacheDomain.NewSessionCache(redisCache),
+		prefsCache:   cacheDomain.NewPreferencesCache(redisCache),
+		backend:      queue.NewRedisBackend(stream, "ml-workers-test", time.Second, time.Minute),
+	}
+}
+
+// runPipeline validates the complete end-to-end flow:
+// User → Session+JWT → Entry(pending)+Outbox → Relay → Worker(ML) → Entry(completed) + Score + Roast
+func runPipeline(t *testing.T, p pipeline) {
+	ctx := context.Background()
 
 	// Setup JWT
 	jwtManager := auth.NewJWTManager("test-secret-key", time.Hour)
 
 	// Setup services
-	userService := services.NewUserService(repos.Users)
-	sessionService := services.NewSessionServiceWithJWT(repos.Sessions, sessionCache, jwtManager)
-	prefsService := services.NewPreferencesService(repos.Preferences, prefsCache)
+	userService := services.NewUserService(p.users)
+	sessionService := services.NewSessionServiceWithJWT(p.sessions, p.sessionCache, jwtManager)
+	prefsService := services.NewPreferencesService(p.preferences, p.prefsCache)
 
-	// Setup queue
-	queueRedis := redis.NewClient(&redis.Options{Addr: redisAddr})
-	stream := queue.NewStreams(queueRedis, "ml:entries:test")
-	_ = stream.EnsureGroup(ctx, "ml-workers-test")
-	producer := queue.NewProducer(stream)
-
-	// Entry service with queue (async mode)
-	entryService := services.NewEntryServiceWithQueue(repos.Entries, repos.Scores, nil, prefsService, producer)
+	// Entry service with outbox (async mode)
+	entryService := services.NewEntryServiceWithOutbox(p.entries, p.scores, nil, prefsService)
+	entryService.SetSessions(p.sessions)
+	relay := outbox.NewRelay(p.outbox, p.backend, 10*time.Millisecond)
 
 	// ML service for worker
 	infer := ml.NewInferenceStub()
 	orchestrator := ml.NewHybridOrchestrator(infer)
-	workerEntryService := services.NewEntryServiceWithHybrid(repos.Entries, repos.Scores, orchestrator, prefsService)
+	workerEntryService := services.NewEntryServiceWithHybrid(p.entries, p.scores, orchestrator, prefsService)
+	workerEntryService.SetSessions(p.sessions)
 
 	// =========================================
 	// STEP 1: Create User
@@ -158,13 +213,33 @@ func TestFullPipeline(t *testing.T) {
 	}
 
 	// =========================================
-	// STEP 4: Simulate Worker Processing
+	// STEP 4: Relay publishes the job, worker drains the queue
 	// =========================================
-	t.Log("Step 4: Processing ML job (simulating worker)...")
-	err = workerEntryService.ProcessMLJob(ctx, entry.ID.String())
-	if err != nil {
-		t.Fatalf("ProcessMLJob failed: %v", err)
+	t.Log("Step 4: Processing ML job through the outbox relay and worker pool...")
+	workerCtx, stopWorker := context.WithCancel(ctx)
+	go relay.Run(workerCtx)
+	pool := queue.NewPool(p.backend.Source("full-pipeline-test"), func(ctx context.Context, job queue.EntryMLJob) error {
+		return workerEntryService.ProcessMLJob(ctx, job.EntryID, job.Key())
+	}, queue.PoolConfig{Workers: 2, JobTimeout: 10 * time.Second})
+	workerDone := make(chan struct{})
+	go func() {
+		pool.Run(workerCtx)
+		close(workerDone)
+	}()
+
+	deadline := time.Now().Add(15 * time.Second)
+	for {
+		e, err := entryService.GetEntry(ctx, entry.ID.String())
+		if err == nil && e.Status.Valid && e.Status.String == "completed" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for worker, entry status '%v'", e.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
 	}
+	stopWorker()
+	<-workerDone
 	t.Log("✓ ML job processed")
 
 	// =========================================
@@ -0,0 +1,120 @@
+package full_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cacheDomain "guiltmachine/internal/cache/domain"
+	"guiltmachine/internal/cache/memory"
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/outbox"
+	"guiltmachine/internal/queue"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+// TestPersonalizationInProcess checks on in-memory storage that roasts follow
+// the preferences of the user owning the entry
+func TestPersonalizationInProcess(t *testing.T) {
+	repos := fakes.NewRepos()
+	runPersonalization(t, pipeline{
+		users:       repos.Users,
+		sessions:    repos.Sessions,
+		entries:     repos.Entries,
+		scores:      repos.Scores,
+		preferences: repos.Preferences,
+		outbox:      repos.Outbox,
+		prefsCache:  cacheDomain.NewPreferencesCache(memory.NewMemoryCache()),
+		backend:     queue.NewMemoryBackend(time.Minute),
+	})
+}
+
+// TestPersonalization runs the same checks against Postgres and Redis
+func TestPersonalization(t *testing.T) {
+	runPersonalization(t, integrationPipeline(t))
+}
+
+// runPersonalization changes a user's persona between two entries and
+// expects the second roast to use the new one, through the outbox, the queue
+// and the preferences cache shared by api and worker
+func runPersonalization(t *testing.T, p pipeline) {
+	ctx := context.Background()
+
+	// api and worker each have their own services, sharing only storage
+	apiPrefs := services.NewPreferencesService(p.preferences, p.prefsCache)
+	apiEntries := services.NewEntryServiceWithOutbox(p.entries, p.scores, nil, apiPrefs)
+	apiEntries.SetSessions(p.sessions)
+
+	workerPrefs := services.NewPreferencesService(p.preferences, p.prefsCache)
+	workerEntries := services.NewEntryServiceWithHybrid(p.entries, p.scores, ml.NewHybridOrchestrator(ml.NewInferenceStub()), workerPrefs)
+	workerEntries.SetSessions(p.sessions)
+
+	email := "persona-" + time.Now().Format("20060102150405.000000") + "@test.com"
+	user, err := p.users.CreateUser(ctx, email, "hash")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	session, err := p.sessions.CreateSession(ctx, user.ID, nil)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	jobs := make(chan queue.EntryMLJob, 4)
+	workerCtx, stopWorker := context.WithCancel(ctx)
+	defer stopWorker()
+	go outbox.NewRelay(p.outbox, p.backend, 10*time.Millisecond).Run(workerCtx)
+	go queue.NewPool(p.backend.Source("personalization-test"), func(ctx context.Context, job queue.EntryMLJob) error {
+		jobs <- job
+		return workerEntries.ProcessMLJob(ctx, job.EntryID, job.Key())
+	}, queue.PoolConfig{Workers: 1, JobTimeout: 10 * time.Second}).Run(workerCtx)
+
+	// roast creates an entry and waits for the worker to complete it
+	roast := func(t *testing.T, text string) (queue.EntryMLJob, string) {
+		t.Helper()
+		entry, err := apiEntries.CreateEntry(ctx, session.ID.String(), text, 5)
+		if err != nil {
+			t.Fatalf("CreateEntry failed: %v", err)
+		}
+		var job queue.EntryMLJob
+		select {
+		case job = <-jobs:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for the ml job")
+		}
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			e, err := apiEntries.GetEntry(ctx, entry.ID.String())
+			if err == nil && e.Status.String == "completed" {
+				return job, e.RoastText.String
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for the roast, status %q", e.Status.String)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	setPrefs := func(t *testing.T, metadata string) {
+		t.Helper()
+		if _, err := apiPrefs.UpsertPreferences(ctx, user.ID.String(), nil, true, metadata); err != nil {
+			t.Fatalf("UpsertPreferences failed: %v", err)
+		}
+	}
+
+	setPrefs(t, `{"persona":"coach","humor_intensity":8}`)
+	job, text := roast(t, "skipped my run")
+	if job.UserID != user.ID.String() || job.Persona != "coach" || job.Intensity != 8 {
+		t.Fatalf("expected the job to carry the user's preferences, got user %q persona %q intensity %d", job.UserID, job.Persona, job.Intensity)
+	}
+	if !strings.HasPrefix(text, "Coach: ") {
+		t.Fatalf("expected a coach roast, got %q", text)
+	}
+
+	// the worker has the coach persona cached now; the upsert must evict it
+	setPrefs(t, `{"persona":"chill","humor_intensity":2}`)
+	if _, text := roast(t, "skipped my run again"); !strings.HasPrefix(text, "Chill: ") {
+		t.Fatalf("expected the persona change to apply to the next roast, got %q", text)
+	}
+}
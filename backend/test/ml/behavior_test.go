@@ -0,0 +1,79 @@
+package ml
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	ml "guiltmachine/internal/ml"
+)
+
+func TestBurstiness(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	var regular []time.Time
+	for i := 0; i < 10; i++ {
+		regular = append(regular, start.Add(time.Duration(i)*time.Hour))
+	}
+	if b := ml.Burstiness(regular); math.Abs(b+1) > 1e-9 {
+		t.Fatalf("expected -1 for evenly spaced activity, got %f", b)
+	}
+
+	// three bursts of a few minutes, days apart, in no particular order
+	var bursty []time.Time
+	for day := 2; day >= 0; day-- {
+		for i := 0; i < 4; i++ {
+			bursty = append(bursty, start.Add(time.Duration(day)*72*time.Hour+time.Duration(i)*time.Minute))
+		}
+	}
+	if b := ml.Burstiness(bursty); b <= 0.3 || b > 1 {
+		t.Fatalf("expected a clearly bursty index, got %f", b)
+	}
+
+	if b := ml.Burstiness(regular[:2]); b != 0 {
+		t.Fatalf("expected 0 for fewer than three events, got %f", b)
+	}
+	if b := ml.Burstiness([]time.Time{start, start, start}); b != 0 {
+		t.Fatalf("expected 0 for simultaneous events, got %f", b)
+	}
+}
+
+func TestComputeBehaviorFeatures(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	var steady []time.Time
+	for i := 0; i < 6; i++ {
+		steady = append(steady, start.Add(time.Duration(i)*24*time.Hour))
+	}
+
+	if f := ml.ComputeBehaviorFeatures(ml.BehaviorSignals{}); f != (ml.BehaviorFeatures{ModeCluster: ml.ModeIdle}) {
+		t.Fatalf("expected zero features for no signals, got %+v", f)
+	}
+
+	f := ml.ComputeBehaviorFeatures(ml.BehaviorSignals{
+		Activity:           steady,
+		Snoozes:            1,
+		CompletionMinutes:  []float64{30, 90, 60},
+		DatedCompletions:   2,
+		OverdueCompletions: 0,
+	})
+	// (0.4*0.25 + 0.35*0) / 0.75, guilt left out without scores
+	if f.SnoozeRate != 0.25 || math.Abs(f.ProcrastinationScore-0.1/0.75) > 1e-9 {
+		t.Fatalf("unexpected rates %+v", f)
+	}
+	if f.CompletionLatencyAvg != 60 || f.BurstinessIndex != -1 || f.ModeCluster != ml.ModeIdle {
+		t.Fatalf("expected regular activity averaging an hour and no mode yet, got %+v", f)
+	}
+
+	f = ml.ComputeBehaviorFeatures(ml.BehaviorSignals{
+		Activity:           steady,
+		GuiltScores:        []float64{0.8, 1},
+		Snoozes:            3,
+		CompletionMinutes:  []float64{600},
+		DatedCompletions:   1,
+		OverdueCompletions: 1,
+	})
+	// (0.4*0.75 + 0.35*1 + 0.25*0.9) / 1
+	if math.Abs(f.ProcrastinationScore-0.875) > 1e-9 {
+		t.Fatalf("expected a weighted score of 0.875, got %+v", f)
+	}
+}
@@ -0,0 +1,143 @@
+package ml
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	ml "guiltmachine/internal/ml"
+)
+
+func TestDefaultClusterModel(t *testing.T) {
+	m := ml.DefaultClusterModel()
+	if m.Version != 0 || len(m.Centroids) != 3 {
+		t.Fatalf("unexpected built-in model %+v", m)
+	}
+
+	cases := []struct {
+		name string
+		f    ml.BehaviorFeatures
+		want int
+	}{
+		{"steady", ml.BehaviorFeatures{ProcrastinationScore: 0.1, SnoozeRate: 0.1, CompletionLatencyAvg: 60, BurstinessIndex: -0.6}, ml.ModeSteady},
+		{"bursty", ml.BehaviorFeatures{ProcrastinationScore: 0.2, SnoozeRate: 0.1, CompletionLatencyAvg: 60, BurstinessIndex: 0.7}, ml.ModeBursty},
+		{"procrastinating", ml.BehaviorFeatures{ProcrastinationScore: 0.8, SnoozeRate: 0.75, CompletionLatencyAvg: 3000, BurstinessIndex: 0}, ml.ModeProcrastinating},
+	}
+	for _, tc := range cases {
+		if got := m.Mode(tc.f); got != tc.want {
+			t.Errorf("%s: expected mode %s, got %s", tc.name, ml.ModeName(tc.want), ml.ModeName(got))
+		}
+	}
+}
+
+func TestFeatureVector(t *testing.T) {
+	v := ml.BehaviorFeatures{ProcrastinationScore: 0.5, SnoozeRate: 0.25, CompletionLatencyAvg: 7 * 24 * 60, BurstinessIndex: -1}.Vector()
+	if len(v) != ml.ClusterDims || v[0] != 0.5 || v[1] != 0.25 || math.Abs(v[2]-1) > 1e-9 || v[3] != 0 {
+		t.Fatalf("unexpected vector %v", v)
+	}
+	if v := (ml.BehaviorFeatures{CompletionLatencyAvg: 1e9, BurstinessIndex: 1}).Vector(); v[2] != 1 || v[3] != 1 {
+		t.Fatalf("expected long latencies capped at 1, got %v", v)
+	}
+}
+
+func TestKMeans(t *testing.T) {
+	points := [][]float64{
+		{0, 0}, {0.1, 0}, {0, 0.1},
+		{1, 1}, {0.9, 1}, {1, 0.9},
+	}
+	// both centers start in the first blob; one has to move over
+	centers := ml.KMeans(points, [][]float64{{0, 0}, {0.05, 0.05}}, ml.ClusterIterations)
+	lo, hi := centers[0], centers[1]
+	if lo[0] > hi[0] {
+		lo, hi = hi, lo
+	}
+	if math.Abs(lo[0]-1.0/30) > 1e-9 || math.Abs(hi[0]-29.0/30) > 1e-9 {
+		t.Fatalf("expected a center per blob, got %v", centers)
+	}
+}
+
+func TestTrainClusterModel(t *testing.T) {
+	var vectors [][]float64
+	for i := 0; i < 5; i++ {
+		d := float64(i) / 100
+		vectors = append(vectors,
+			[]float64{0.1 + d, 0.1, 0.4, 0.1},  // steady
+			[]float64{0.15 + d, 0.2, 0.4, 0.9}, // bursty
+			[]float64{0.8 + d, 0.7, 0.8, 0.5},  // procrastinating
+		)
+	}
+
+	// a cold start ignores a model of the wrong shape
+	for _, prev := range []*ml.ClusterModel{ml.DefaultClusterModel(), {}} {
+		m, err := ml.TrainClusterModel(prev, vectors)
+		if err != nil {
+			t.Fatalf("TrainClusterModel failed: %v", err)
+		}
+		got := map[int][]float64{}
+		for _, c := range m.Centroids {
+			got[c.Mode] = c.Center
+		}
+		if len(got) != 3 || got[ml.ModeProcrastinating][0] < 0.8 || got[ml.ModeBursty][3] < 0.9 || got[ml.ModeSteady][3] > 0.1 {
+			t.Fatalf("expected one labelled centroid per blob, got %+v", m.Centroids)
+		}
+		if mode := m.Mode(ml.BehaviorFeatures{ProcrastinationScore: 0.85, SnoozeRate: 0.7, CompletionLatencyAvg: 2000}); mode != ml.ModeProcrastinating {
+			t.Fatalf("expected a procrastinating assignment, got %s", ml.ModeName(mode))
+		}
+	}
+
+	if _, err := ml.TrainClusterModel(ml.DefaultClusterModel(), vectors[:2]); err == nil {
+		t.Fatalf("expected an error for fewer vectors than clusters")
+	}
+
+	// two blobs nearest the built-in steady centroid still get a mode each
+	vectors = nil
+	for i := 0; i < 5; i++ {
+		d := float64(i) / 100
+		vectors = append(vectors,
+			[]float64{0.1 + d, 0.1, 0.4, 0.1},
+			[]float64{0.1 + d, 0.1, 0.4, 0.35},
+			[]float64{0.2 + d, 0.2, 0.45, 0.9},
+		)
+	}
+	m, err := ml.TrainClusterModel(&ml.ClusterModel{}, vectors)
+	if err != nil {
+		t.Fatalf("TrainClusterModel failed: %v", err)
+	}
+	got := map[int][]float64{}
+	for _, c := range m.Centroids {
+		got[c.Mode] = c.Center
+	}
+	if len(got) != 3 || got[ml.ModeSteady][3] < 0.3 || got[ml.ModeBursty][3] < 0.9 || got[ml.ModeProcrastinating][3] > 0.2 {
+		t.Fatalf("expected the modes assigned one-to-one, got %+v", m.Centroids)
+	}
+}
+
+func TestParseClusterModel(t *testing.T) {
+	m := ml.DefaultClusterModel()
+	data, err := json.Marshal(m.Centroids)
+	if err != nil {
+		t.Fatalf("marshal centroids: %v", err)
+	}
+	back, err := ml.ParseCentroids(7, data)
+	if err != nil || back.Version != 7 || len(back.Centroids) != 3 || back.Centroids[1].Mode != m.Centroids[1].Mode {
+		t.Fatalf("expected centroids to round trip, got %+v (%v)", back, err)
+	}
+
+	invalid := []string{
+		`{"version": 1}`,
+		`{"version": 1, "centroids": [{"mode": "lazy", "center": [0, 0, 0, 0]}]}`,
+		`{"version": 1, "centroids": [{"mode": "idle", "center": [0, 0, 0, 0]}]}`,
+		`{"version": 1, "centroids": [{"mode": "steady", "center": [0, 0]}]}`,
+	}
+	for _, data := range invalid {
+		if _, err := ml.ParseClusterModel([]byte(data)); err == nil {
+			t.Errorf("expected %s to be rejected", data)
+		}
+	}
+
+	for _, mode := range []int{ml.ModeIdle, ml.ModeSteady, ml.ModeBursty, ml.ModeProcrastinating} {
+		if got, ok := ml.ParseMode(ml.ModeName(mode)); !ok || got != mode {
+			t.Errorf("expected %s to round trip", ml.ModeName(mode))
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package ml
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ml "guiltmachine/internal/ml"
+)
+
+// flakyLLM fails while down is set and counts its calls
+type flakyLLM struct {
+	down  bool
+	calls int
+}
+
+func (f *flakyLLM) Generate(ctx context.Context, in ml.HybridInput) (string, error) {
+	f.calls++
+	if f.down {
+		return "", errors.New("connection refused")
+	}
+	return "llm roast", nil
+}
+
+func TestTemplateRender(t *testing.T) {
+	set, err := ml.ParseTemplates([]byte(`{
+		"version": 3,
+		"personas": {
+			"coach": {"high": ["Push harder than \"{{.Snippet}}\"."]},
+			"neutral": {"mid": ["Noted {{.Intensity}}."]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if set.Version != 3 {
+		t.Fatalf("expected version 3, got %d", set.Version)
+	}
+
+	got, err := set.Render(ml.HybridInput{Text: "skipped   leg day", Intensity: 9, Persona: ml.PersonaCoach})
+	if err != nil || got != `Push harder than "skipped leg day".` {
+		t.Fatalf("unexpected render %q, %v", got, err)
+	}
+
+	// no low coach template: another band of the persona is used
+	if got, _ := set.Render(ml.HybridInput{Text: "x", Intensity: 1, Persona: ml.PersonaCoach}); !strings.HasPrefix(got, "Push harder") {
+		t.Fatalf("expected a coach template, got %q", got)
+	}
+	// no chill templates at all: neutral
+	if got, _ := set.Render(ml.HybridInput{Text: "x", Intensity: 12, Persona: ml.PersonaChill}); got != "Noted 10." {
+		t.Fatalf("expected neutral fallback, got %q", got)
+	}
+
+	long := strings.Repeat("procrastinated ", 10)
+	got, _ = set.Render(ml.HybridInput{Text: long, Intensity: 9, Persona: ml.PersonaCoach})
+	if len(got) > 100 || !strings.Contains(got, "…") {
+		t.Fatalf("expected the snippet to be shortened, got %q", got)
+	}
+}
+
+func TestParseTemplatesRejectsBadFiles(t *testing.T) {
+	for name, data := range map[string]string{
+		"no neutral":      `{"personas": {"roast": {"low": ["hi"]}}}`,
+		"unknown persona": `{"personas": {"pirate": {"low": ["arr"]}, "neutral": {"low": ["ok"]}}}`,
+		"unknown band":    `{"personas": {"neutral": {"extreme": ["ok"]}}}`,
+		"bad template":    `{"personas": {"neutral": {"low": ["{{.Snippet"]}}}`,
+	} {
+		if _, err := ml.ParseTemplates([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	if err := os.WriteFile(path, []byte(`{"version": 2, "personas": {"neutral": {"low": ["from file"]}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	set, err := ml.LoadTemplates(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if got, _ := set.Render(ml.HybridInput{Text: "x", Persona: ml.PersonaRoast}); got != "from file" {
+		t.Fatalf("unexpected render %q", got)
+	}
+
+	// every persona and band of the built-in file renders
+	defaults := ml.DefaultTemplates()
+	for _, p := range []ml.Persona{ml.PersonaNeutral, ml.PersonaRoast, ml.PersonaCoach, ml.PersonaChill} {
+		for _, i := range []int{0, 5, 10} {
+			if got, err := defaults.Render(ml.HybridInput{Text: "ate the last cookie", Intensity: i, Persona: p}); err != nil || !strings.Contains(got, "ate the last cookie") {
+				t.Fatalf("persona %d intensity %d: %q, %v", p, i, got, err)
+			}
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := ml.NewCircuitBreakerWithClock(2, time.Minute, func() time.Time { return now })
+
+	b.Failure()
+	if b.Allow() != nil {
+		t.Fatalf("expected one failure to keep the breaker closed")
+	}
+	b.Failure()
+	if !errors.Is(b.Allow(), ml.ErrCircuitOpen) || b.State() != ml.BreakerOpen {
+		t.Fatalf("expected the breaker to open, got %v", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	if b.Allow() != nil || b.State() != ml.BreakerHalfOpen {
+		t.Fatalf("expected half-open after cooldown, got %v", b.State())
+	}
+	b.Failure()
+	if b.State() != ml.BreakerOpen {
+		t.Fatalf("expected a half-open failure to reopen, got %v", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	b.Success()
+	if b.State() != ml.BreakerClosed {
+		t.Fatalf("expected success to close, got %v", b.State())
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := ml.NewCircuitBreakerWithClock(1, time.Minute, func() time.Time { return now })
+	b.Failure()
+	now = now.Add(time.Minute)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected the first half-open call through as the probe, got %v", err)
+	}
+	if !errors.Is(b.Allow(), ml.ErrCircuitOpen) {
+		t.Fatalf("expected calls to be rejected while the probe is in flight")
+	}
+
+	// an abandoned probe frees the slot without changing the state
+	b.Abandon()
+	if b.State() != ml.BreakerHalfOpen || b.Allow() != nil {
+		t.Fatalf("expected a new probe after an abandoned one, got %v", b.State())
+	}
+	b.Success()
+	if b.State() != ml.BreakerClosed || b.Allow() != nil || b.Allow() != nil {
+		t.Fatalf("expected a closed breaker to allow every call, got %v", b.State())
+	}
+}
+
+func TestHybridFallsBackToTemplates(t *testing.T) {
+	ctx := context.Background()
+	in := ml.HybridInput{Text: "skipped the gym again", Intensity: 8, Persona: ml.PersonaRoast}
+	llm := &flakyLLM{down: true}
+	breaker := ml.NewCircuitBreaker(2, time.Hour)
+	o := ml.NewHybridOrchestratorWithFallback(llm, ml.DefaultTemplates(), breaker)
+
+	for i := 0; i < 3; i++ {
+		out, err := o.Run(ctx, in)
+		if err != nil {
+			t.Fatalf("run %d failed: %v", i, err)
+		}
+		if out.Source != ml.SourceTemplate || !contains(out.Tags, "source=template") {
+			t.Fatalf("expected a template roast, got %+v", out)
+		}
+		if !strings.HasPrefix(out.RoastText, "🔥 ") || !strings.Contains(out.RoastText, in.Text) {
+			t.Fatalf("unexpected template roast %q", out.RoastText)
+		}
+	}
+	if llm.calls != 2 {
+		t.Fatalf("expected the open breaker to skip the llm, got %d calls", llm.calls)
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		got, out := collect(t, o, in)
+		if strings.Join(got, "") != out.RoastText || out.Source != ml.SourceTemplate {
+			t.Fatalf("streamed %q, final %+v", got, out)
+		}
+	})
+
+	t.Run("without templates the error is returned", func(t *testing.T) {
+		if _, err := ml.NewHybridOrchestrator(&flakyLLM{down: true}).Run(ctx, in); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+
+	t.Run("canceled requests do not fall back", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		o := ml.NewHybridOrchestratorWithFallback(&flakyLLM{down: true}, ml.DefaultTemplates(), nil)
+		if _, err := o.Run(canceled, in); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+
+	t.Run("healthy llm", func(t *testing.T) {
+		out, err := ml.NewHybridOrchestratorWithFallback(&flakyLLM{}, ml.DefaultTemplates(), nil).Run(ctx, in)
+		if err != nil || out.Source != ml.SourceLLM || out.RoastText != "🔥 llm roast" {
+			t.Fatalf("unexpected output %+v, %v", out, err)
+		}
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package ml
+
+import (
+	"strings"
+	"testing"
+
+	ml "guiltmachine/internal/ml"
+)
+
+func TestFormatHistory(t *testing.T) {
+	items := []ml.HistoryItem{
+		{Text: "ate cake for breakfast", Roast: "Bold."},
+		{Text: "skipped the gym"},
+		{Text: "ignored my inbox all week", Roast: "Inbox zero, inbox hero."},
+	}
+
+	all := ml.FormatHistory(items, 0)
+	if len(all) != 3 {
+		t.Fatalf("expected all entries without a budget, got %q", all)
+	}
+	if all[0] != `"ignored my inbox all week" (you answered: "Inbox zero, inbox hero.")` || all[2] != `"ate cake for breakfast" (you answered: "Bold.")` {
+		t.Fatalf("expected oldest first with roasts, got %q", all)
+	}
+	if all[1] != `"skipped the gym"` {
+		t.Fatalf("unexpected line for an entry without roast: %q", all[1])
+	}
+
+	budget := len(all[2]) + len(all[1])
+	trimmed := ml.FormatHistory(items, budget)
+	if len(trimmed) != 2 || trimmed[1] != all[2] {
+		t.Fatalf("expected the two newest entries within %d chars, got %q", budget, trimmed)
+	}
+
+	short := ml.FormatHistory(items, 20)
+	if len(short) != 1 || len(short[0]) > 20 || !strings.HasPrefix(short[0], `"ate cake`) {
+		t.Fatalf("expected the newest entry shortened to 20 chars, got %q", short)
+	}
+
+	if got := ml.FormatHistory(items, 2); len(got) != 0 {
+		t.Fatalf("expected nothing to fit, got %q", got)
+	}
+}
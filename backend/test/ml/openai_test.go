@@ -0,0 +1,236 @@
+package ml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ml "guiltmachine/internal/ml"
+)
+
+type fakeChatRequest struct {
+	Model     string           `json:"model"`
+	Messages  []ml.ChatMessage `json:"messages"`
+	MaxTokens int              `json:"max_tokens"`
+}
+
+// newFakeOpenAI serves /chat/completions with handle and records the last request
+func newFakeOpenAI(t *testing.T, handle func(w http.ResponseWriter, req fakeChatRequest)) (*httptest.Server, *http.Request) {
+	t.Helper()
+	var last http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		last = *r
+		var req fakeChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		handle(w, req)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &last
+}
+
+func completion(text string) map[string]any {
+	return map[string]any{
+		"choices": []map[string]any{
+			{"message": map[string]string{"role": "assistant", "content": text}},
+		},
+	}
+}
+
+func TestOpenAIClientGenerate(t *testing.T) {
+	var got fakeChatRequest
+	srv, last := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+		got = req
+		_ = json.NewEncoder(w).Encode(completion("  Three snooze buttons? Ambitious.  "))
+	})
+
+	cfg := ml.DefaultOpenAIConfig()
+	cfg.BaseURL = srv.URL + "/v1/"
+	cfg.APIKey = "sk-test"
+	cfg.Model = "local-model"
+	client := ml.NewOpenAIClient(cfg)
+
+	text, err := client.Generate(context.Background(), ml.HybridInput{
+		Text:      "I hit snooze three times",
+		Intensity: 8,
+		Persona:   ml.PersonaCoach,
+		History:   []string{"skipped the gym", "ate cake for breakfast"},
+	})
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if text != "Three snooze buttons? Ambitious." {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	if auth := last.Header.Get("Authorization"); auth != "Bearer sk-test" {
+		t.Fatalf("expected bearer auth, got %q", auth)
+	}
+	if got.Model != "local-model" || got.MaxTokens != cfg.MaxTokens {
+		t.Fatalf("unexpected request %+v", got)
+	}
+	if len(got.Messages) != 3 {
+		t.Fatalf("expected system, history and entry messages, got %+v", got.Messages)
+	}
+	if got.Messages[0].Role != "system" || !strings.Contains(got.Messages[0].Content, "coach") || !strings.Contains(got.Messages[0].Content, "8 out of 10") {
+		t.Fatalf("system prompt misses persona or intensity: %q", got.Messages[0].Content)
+	}
+	if !strings.Contains(got.Messages[1].Content, "ate cake for breakfast") {
+		t.Fatalf("history missing from prompt: %q", got.Messages[1].Content)
+	}
+	if got.Messages[2].Content != "I hit snooze three times" {
+		t.Fatalf("entry text not last: %q", got.Messages[2].Content)
+	}
+}
+
+func TestOpenAIClientWithoutHistoryOrKey(t *testing.T) {
+	srv, last := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+		if len(req.Messages) != 2 {
+			http.Error(w, "unexpected messages", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(completion("fine"))
+	})
+
+	client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Model: "m", Timeout: time.Second})
+	if _, err := client.Generate(context.Background(), ml.HybridInput{Text: "nothing much"}); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if auth := last.Header.Get("Authorization"); auth != "" {
+		t.Fatalf("expected no auth header, got %q", auth)
+	}
+}
+
+func TestOpenAIClientErrors(t *testing.T) {
+	ctx := context.Background()
+	in := ml.HybridInput{Text: "x", Persona: ml.PersonaRoast}
+
+	t.Run("api error", func(t *testing.T) {
+		srv, _ := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+		})
+		client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Timeout: time.Second})
+
+		_, err := client.Generate(ctx, in)
+		var apiErr *ml.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Message != "rate limited" {
+			t.Fatalf("expected 429 APIError, got %v", err)
+		}
+	})
+
+	t.Run("empty completion", func(t *testing.T) {
+		srv, _ := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+			_, _ = w.Write([]byte(`{"choices":[]}`))
+		})
+		client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Timeout: time.Second})
+
+		if _, err := client.Generate(ctx, in); !errors.Is(err, ml.ErrEmptyCompletion) {
+			t.Fatalf("expected ErrEmptyCompletion, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		srv, _ := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+			time.Sleep(200 * time.Millisecond)
+			_ = json.NewEncoder(w).Encode(completion("too late"))
+		})
+		client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Timeout: 50 * time.Millisecond})
+
+		if _, err := client.Generate(ctx, in); err == nil {
+			t.Fatalf("expected timeout error")
+		}
+	})
+}
+
+func TestOpenAIClientGenerateStream(t *testing.T) {
+	var got fakeChatRequest
+	srv, _ := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+		got = req
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range []string{
+			`{"choices":[{"delta":{"role":"assistant"}}]}`,
+			`{"choices":[{"delta":{"content":"Snooze"}}]}`,
+			`{"choices":[{"delta":{"content":" champion."}}]}`,
+		} {
+			_, _ = w.Write([]byte(": keep-alive\n\ndata: " + chunk + "\n\n"))
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	})
+
+	client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Model: "m", Timeout: time.Second})
+
+	var deltas []string
+	text, err := client.GenerateStream(context.Background(), ml.HybridInput{Text: "snoozed"}, func(d string) error {
+		deltas = append(deltas, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if text != "Snooze champion." || strings.Join(deltas, "|") != "Snooze| champion." {
+		t.Fatalf("unexpected stream %q -> %q", deltas, text)
+	}
+	if len(got.Messages) != 2 {
+		t.Fatalf("unexpected request %+v", got)
+	}
+}
+
+func TestOpenAIClientStreamTimeouts(t *testing.T) {
+	in := ml.HybridInput{Text: "snoozed"}
+	ignore := func(string) error { return nil }
+	chunk := func(w http.ResponseWriter, content string) {
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"` + content + `"}}]}` + "\n\n"))
+		w.(http.Flusher).Flush()
+	}
+
+	t.Run("outlasts the timeout while sending", func(t *testing.T) {
+		srv, _ := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+			for i := 0; i < 6; i++ {
+				chunk(w, "z")
+				time.Sleep(40 * time.Millisecond)
+			}
+			_, _ = w.Write([]byte("data: [DONE]\n\n"))
+		})
+		client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Timeout: 100 * time.Millisecond})
+
+		if text, err := client.GenerateStream(context.Background(), in, ignore); err != nil || text != "zzzzzz" {
+			t.Fatalf("expected the full stream, got %q (%v)", text, err)
+		}
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		srv, _ := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+			chunk(w, "z")
+			time.Sleep(300 * time.Millisecond)
+		})
+		client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Timeout: 50 * time.Millisecond})
+
+		if _, err := client.GenerateStream(context.Background(), in, ignore); !errors.Is(err, ml.ErrStreamStalled) {
+			t.Fatalf("expected ErrStreamStalled, got %v", err)
+		}
+	})
+
+	t.Run("ends without done", func(t *testing.T) {
+		srv, _ := newFakeOpenAI(t, func(w http.ResponseWriter, req fakeChatRequest) {
+			chunk(w, "half a roast")
+		})
+		client := ml.NewOpenAIClient(ml.OpenAIConfig{BaseURL: srv.URL + "/v1", Timeout: time.Second})
+
+		if _, err := client.GenerateStream(context.Background(), in, ignore); !errors.Is(err, ml.ErrStreamTruncated) {
+			t.Fatalf("expected ErrStreamTruncated, got %v", err)
+		}
+	})
+}
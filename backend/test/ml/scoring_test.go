@@ -0,0 +1,115 @@
+package ml
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ml "guiltmachine/internal/ml"
+	gen "guiltmachine/internal/proto/gen/ml"
+)
+
+func TestScoringModelFeatures(t *testing.T) {
+	m := ml.DefaultScoringModel()
+	noon := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	base := ml.ScoreInput{Text: "watched youtube instead of studying", At: noon}
+
+	f := m.Features(base)
+	if f.Procrastination == 0 || f.LateNight != 0 || f.GuiltLevel != 0 {
+		t.Fatalf("unexpected features %+v", f)
+	}
+	if f := m.Features(ml.ScoreInput{Text: "did nothing productive"}); f.Sentiment != 1 {
+		t.Fatalf("expected negated positive word to count as negative, got %+v", f)
+	}
+
+	score := m.Score(base)
+	if score <= 0 || score >= 1 {
+		t.Fatalf("score out of range: %f", score)
+	}
+
+	rated := base
+	rated.GuiltLevel = 9
+	if m.Score(rated) <= score {
+		t.Fatalf("self-reported guilt should raise the score")
+	}
+	late := base
+	late.At = time.Date(2026, 3, 4, 2, 30, 0, 0, time.UTC)
+	if m.Score(late) <= score {
+		t.Fatalf("late night should raise the score")
+	}
+	proud := ml.ScoreInput{Text: "finished the report early, proud of it", At: noon}
+	if m.Score(proud) >= score {
+		t.Fatalf("a positive entry should score lower than procrastination")
+	}
+}
+
+func TestScoringConsistentAcrossPaths(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2026, 3, 4, 23, 15, 0, 0, time.UTC)
+	text := "skipped the gym again and binged netflix"
+
+	out, err := ml.NewHybridOrchestrator(&MockLLM{responseText: "ok"}).Run(ctx, ml.HybridInput{
+		Text: text, Persona: ml.PersonaRoast, Intensity: 5, GuiltLevel: 7, At: at,
+	})
+	if err != nil {
+		t.Fatalf("hybrid run failed: %v", err)
+	}
+	resp, err := ml.NewInferenceStub().Roast(ctx, &gen.RoastRequest{
+		EntryText: text, HumorIntensity: 5, GuiltLevel: 7, CreatedAt: timestamppb.New(at),
+	})
+	if err != nil {
+		t.Fatalf("roast failed: %v", err)
+	}
+
+	if math.Abs(out.GuiltScore-resp.GuiltScore) > 1e-9 {
+		t.Fatalf("paths disagree: hybrid %f, stub %f", out.GuiltScore, resp.GuiltScore)
+	}
+	version := ml.DefaultScoringModel().Version
+	if out.ModelVersion != version || resp.ModelVersion != version {
+		t.Fatalf("expected model version %q, got %q and %q", version, out.ModelVersion, resp.ModelVersion)
+	}
+}
+
+func TestLoadScoringModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+	model := `{
+		"version": "test-v2",
+		"bias": 0,
+		"weights": {"guilt_level": 4},
+		"length_saturation": 10,
+		"late_night_hours": [22, 6]
+	}`
+	if err := os.WriteFile(path, []byte(model), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := ml.LoadScoringModel(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if got := m.Score(ml.ScoreInput{Text: "anything"}); got != 0.5 {
+		t.Fatalf("expected only the bias to count, got %f", got)
+	}
+
+	o := ml.NewHybridOrchestrator(&MockLLM{responseText: "ok"})
+	o.SetScoringModel(m)
+	out, _ := o.Run(context.Background(), ml.HybridInput{Text: "anything", GuiltLevel: 10})
+	if out.ModelVersion != "test-v2" || out.GuiltScore < 0.98 {
+		t.Fatalf("expected the loaded model to score, got %s %f", out.ModelVersion, out.GuiltScore)
+	}
+
+	for name, data := range map[string]string{
+		"no version":    `{"length_saturation": 10}`,
+		"no saturation": `{"version": "v"}`,
+		"bad hours":     `{"version": "v", "length_saturation": 10, "late_night_hours": [25, 3]}`,
+		"overlap":       `{"version": "v", "length_saturation": 10, "lexicon": {"negative": ["wasted"], "procrastination": ["wasted time"]}}`,
+	} {
+		if _, err := ml.ParseScoringModel([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ml
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	ml "guiltmachine/internal/ml"
+)
+
+// chunkedLLM streams fixed deltas, splitting words the way tokenizers do
+type chunkedLLM struct {
+	deltas []string
+}
+
+func (c *chunkedLLM) Generate(ctx context.Context, in ml.HybridInput) (string, error) {
+	return strings.Join(c.deltas, ""), nil
+}
+
+func (c *chunkedLLM) GenerateStream(ctx context.Context, in ml.HybridInput, onDelta func(string) error) (string, error) {
+	for _, d := range c.deltas {
+		if err := onDelta(d); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(c.deltas, ""), nil
+}
+
+func collect(t *testing.T, o *ml.HybridOrchestrator, in ml.HybridInput) ([]string, *ml.HybridOutput) {
+	t.Helper()
+	var got []string
+	out, err := o.RunStream(context.Background(), in, func(d string) error {
+		got = append(got, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RunStream failed: %v", err)
+	}
+	return got, out
+}
+
+func TestHybridRunStreamMatchesFinalText(t *testing.T) {
+	llm := &chunkedLLM{deltas: []string{" You", " could ki", "ll time", " better", ".\n"}}
+	in := ml.HybridInput{Text: "wasted the afternoon", Intensity: 8, Persona: ml.PersonaRoast}
+
+	got, out := collect(t, ml.NewHybridOrchestrator(llm), in)
+
+	streamed := strings.Join(got, "")
+	if streamed != out.RoastText {
+		t.Fatalf("streamed %q, final %q", streamed, out.RoastText)
+	}
+	if out.RoastText != "🔥 You could avoid time better." {
+		t.Fatalf("unexpected roast %q", out.RoastText)
+	}
+	for _, d := range got {
+		if strings.HasSuffix(d, "ki") || strings.Contains(d, "kill") {
+			t.Fatalf("unfiltered text was emitted: %q", d)
+		}
+	}
+	if len(got) < 3 {
+		t.Fatalf("expected incremental deltas, got %q", got)
+	}
+
+	sync, err := ml.NewHybridOrchestrator(llm).Run(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if sync.GuiltScore != out.GuiltScore {
+		t.Fatalf("streaming changed the score: %f vs %f", out.GuiltScore, sync.GuiltScore)
+	}
+}
+
+func TestHybridRunStreamWithoutStreamingLLM(t *testing.T) {
+	llm := &MockLLM{responseText: "one shot"}
+	got, out := collect(t, ml.NewHybridOrchestrator(llm), ml.HybridInput{Text: "x", Persona: ml.PersonaCoach})
+
+	if strings.Join(got, "") != out.RoastText || out.RoastText != "Coach: one shot" {
+		t.Fatalf("streamed %q, final %q", got, out.RoastText)
+	}
+}
+
+func TestHybridRunStreamAbortsOnEmitError(t *testing.T) {
+	llm := &chunkedLLM{deltas: []string{"a ", "b ", "c"}}
+	stop := errors.New("client gone")
+
+	_, err := ml.NewHybridOrchestrator(llm).RunStream(context.Background(), ml.HybridInput{Text: "x"}, func(string) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected emit error, got %v", err)
+	}
+
+	// a client that went away says nothing about the LLM
+	breaker := ml.NewCircuitBreaker(1, time.Hour)
+	o := ml.NewHybridOrchestratorWithFallback(llm, nil, breaker)
+	if _, err := o.RunStream(context.Background(), ml.HybridInput{Text: "x"}, func(string) error { return stop }); !errors.Is(err, stop) {
+		t.Fatalf("expected emit error, got %v", err)
+	}
+	if breaker.State() != ml.BreakerClosed {
+		t.Fatalf("expected the breaker to stay closed, got %v", breaker.State())
+	}
+}
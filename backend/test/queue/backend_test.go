@@ -0,0 +1,122 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/queue"
+)
+
+// testBackend exercises the contract every queue.Backend must honour
+func testBackend(t *testing.T, backend queue.Backend, dlq queue.DeadLetterStore, lease time.Duration) {
+	ctx := context.Background()
+
+	t.Run("ack removes the job", func(t *testing.T) {
+		src := backend.Source("worker-a")
+		if err := backend.Publish(ctx, queue.EntryMLJob{EntryID: "ack-me"}); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+		d := pollUntil(t, src)
+		if d.Job.EntryID != "ack-me" || d.Job.Attempts != 0 {
+			t.Fatalf("unexpected delivery %+v", d.Job)
+		}
+		if err := src.Ack(ctx, d); err != nil {
+			t.Fatalf("ack failed: %v", err)
+		}
+		if again, _ := src.PollN(ctx, 1); len(again) != 0 {
+			t.Fatalf("expected acked job to be gone, got %+v", again)
+		}
+	})
+
+	t.Run("failures retry then dead-letter", func(t *testing.T) {
+		src := backend.Source("worker-a")
+		if err := backend.Publish(ctx, queue.EntryMLJob{EntryID: "always-fails"}); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+
+		cause := errors.New("llm unavailable")
+		for attempt := 0; attempt < 3; attempt++ {
+			d := pollUntil(t, src)
+			if d.Job.Attempts != attempt {
+				t.Fatalf("expected %d prior attempts, got %d", attempt, d.Job.Attempts)
+			}
+			dead, err := src.Fail(ctx, d, cause)
+			if err != nil {
+				t.Fatalf("fail failed: %v", err)
+			}
+			if dead != (attempt == 2) {
+				t.Fatalf("attempt %d: unexpected dead=%v", attempt, dead)
+			}
+		}
+
+		letters, err := dlq.DeadLetters(ctx, 0)
+		if err != nil {
+			t.Fatalf("list dlq failed: %v", err)
+		}
+		if len(letters) != 1 || letters[0].Job.EntryID != "always-fails" || letters[0].Error != cause.Error() {
+			t.Fatalf("unexpected dead letters %+v", letters)
+		}
+
+		if err := dlq.Replay(ctx, letters[0].ID); err != nil {
+			t.Fatalf("replay failed: %v", err)
+		}
+		d := pollUntil(t, src)
+		if d.Job.EntryID != "always-fails" || d.Job.Attempts != 0 {
+			t.Fatalf("unexpected replayed job %+v", d.Job)
+		}
+		if err := src.Ack(ctx, d); err != nil {
+			t.Fatalf("ack failed: %v", err)
+		}
+		if _, err := dlq.DeadLetter(ctx, letters[0].ID); !errors.Is(err, queue.ErrDeadLetterNotFound) {
+			t.Fatalf("expected replayed job to leave the dlq, got %v", err)
+		}
+	})
+
+	t.Run("expired lease is taken over", func(t *testing.T) {
+		crashed := backend.Source("worker-crashed")
+		live := backend.Source("worker-live")
+		if err := backend.Publish(ctx, queue.EntryMLJob{EntryID: "orphan"}); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+
+		orphan := pollUntil(t, crashed)
+		time.Sleep(lease + 50*time.Millisecond)
+
+		d := pollUntil(t, live)
+		if d.Job.EntryID != "orphan" {
+			t.Fatalf("expected live worker to take over orphan, got %+v", d.Job)
+		}
+		if err := live.Ack(ctx, d); err != nil {
+			t.Fatalf("ack failed: %v", err)
+		}
+		// the crashed worker coming back must not clobber the new owner
+		if err := crashed.Ack(ctx, orphan); !errors.Is(err, queue.ErrLeaseLost) {
+			t.Fatalf("expected ErrLeaseLost, got %v", err)
+		}
+	})
+}
+
+func pollUntil(t *testing.T, src queue.JobSource) queue.Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries, err := src.PollN(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("poll failed: %v", err)
+		}
+		if len(deliveries) > 0 {
+			return deliveries[0]
+		}
+	}
+	t.Fatal("timed out waiting for delivery")
+	return queue.Delivery{}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	policy := queue.RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	lease := 100 * time.Millisecond
+	backend := queue.NewMemoryBackendWithPolicy(lease, policy)
+	testBackend(t, backend, backend, lease)
+}
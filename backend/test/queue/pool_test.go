@@ -0,0 +1,216 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/queue"
+)
+
+// fakeSource hands out a fixed set of jobs and records acks and failures
+type fakeSource struct {
+	mu      sync.Mutex
+	pending []queue.Delivery
+	acked   []string
+	failed  []string
+	maxPoll int64
+}
+
+func newFakeSource(n int) *fakeSource {
+	src := &fakeSource{}
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("%d-0", i)
+		src.pending = append(src.pending, queue.Delivery{ID: id, Job: queue.EntryMLJob{EntryID: id}})
+	}
+	return src
+}
+
+func (f *fakeSource) PollN(ctx context.Context, n int64) ([]queue.Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if n > f.maxPoll {
+		f.maxPoll = n
+	}
+	if len(f.pending) == 0 {
+		f.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		f.mu.Lock()
+		return nil, nil
+	}
+	if int(n) > len(f.pending) {
+		n = int64(len(f.pending))
+	}
+	out := f.pending[:n]
+	f.pending = f.pending[n:]
+	return out, nil
+}
+
+func (f *fakeSource) Ack(ctx context.Context, d queue.Delivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.acked = append(f.acked, d.ID)
+	return nil
+}
+
+func (f *fakeSource) Fail(ctx context.Context, d queue.Delivery, cause error) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = append(f.failed, d.ID)
+	return false, nil
+}
+
+func (f *fakeSource) counts() (acked, failed int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.acked), len(f.failed)
+}
+
+func TestPoolProcessesConcurrentlyWithinLimit(t *testing.T) {
+	src := newFakeSource(20)
+
+	var running, peak int32
+	handler := func(ctx context.Context, job queue.EntryMLJob) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	pool := queue.NewPool(src, handler, queue.PoolConfig{Workers: 4, MaxInFlight: 4, BatchSize: 8, JobTimeout: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pool.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if acked, _ := src.counts(); acked == 20 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for jobs")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if peak > 4 {
+		t.Errorf("expected at most 4 concurrent jobs, saw %d", peak)
+	}
+	if peak < 2 {
+		t.Errorf("expected jobs to run concurrently, peak was %d", peak)
+	}
+	if src.maxPoll > 4 {
+		t.Errorf("expected polls bounded by in-flight limit, got %d", src.maxPoll)
+	}
+}
+
+func TestPoolJobTimeout(t *testing.T) {
+	src := newFakeSource(1)
+
+	handler := func(ctx context.Context, job queue.EntryMLJob) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	pool := queue.NewPool(src, handler, queue.PoolConfig{Workers: 1, JobTimeout: 20 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pool.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, failed := src.counts(); failed == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected slow job to time out and be failed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
+
+func TestPoolDrainsInFlightJobsOnShutdown(t *testing.T) {
+	src := newFakeSource(2)
+
+	started := make(chan struct{}, 2)
+	handler := func(ctx context.Context, job queue.EntryMLJob) error {
+		started <- struct{}{}
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return errors.New("cancelled during drain")
+		}
+	}
+	pool := queue.NewPool(src, handler, queue.PoolConfig{Workers: 2, BatchSize: 2, JobTimeout: time.Second, DrainTimeout: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pool.Run(ctx)
+		close(done)
+	}()
+
+	<-started
+	<-started
+	cancel()
+	<-done
+
+	acked, failed := src.counts()
+	if acked != 2 || failed != 0 {
+		t.Fatalf("expected both in-flight jobs to finish, acked=%d failed=%d", acked, failed)
+	}
+}
+
+func TestPoolAbandonsJobsAfterDrainTimeout(t *testing.T) {
+	src := newFakeSource(1)
+
+	started := make(chan struct{})
+	handler := func(ctx context.Context, job queue.EntryMLJob) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	pool := queue.NewPool(src, handler, queue.PoolConfig{Workers: 1, JobTimeout: time.Minute, DrainTimeout: 20 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pool.Run(ctx)
+		close(done)
+	}()
+
+	<-started
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("pool did not stop after drain timeout")
+	}
+
+	// neither acked nor failed: the message stays pending and is reclaimed later
+	if acked, failed := src.counts(); acked != 0 || failed != 0 {
+		t.Fatalf("expected abandoned job to stay pending, acked=%d failed=%d", acked, failed)
+	}
+}
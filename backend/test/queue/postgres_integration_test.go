@@ -0,0 +1,51 @@
+package queue_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/queue"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DB_URL")
+	if url == "" {
+		t.Fatalf("TEST_DB_URL not set (expected from test/test_main.go)")
+	}
+
+	cfg, err := pgx.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("failed to parse db config: %v", err)
+	}
+
+	db, err := sql.Open("pgx", stdlib.RegisterConnConfig(cfg))
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	if err := db.PingContext(context.Background()); err != nil {
+		t.Fatalf("failed to ping test db: %v", err)
+	}
+	return db
+}
+
+func TestPostgresBackend(t *testing.T) {
+	db := openTestDB(t)
+	defer db.Close()
+
+	name := "ml:entries:test:" + uuid.NewString()
+	defer db.Exec(`DELETE FROM jobs WHERE queue = $1`, name)
+
+	policy := queue.RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	lease := time.Second
+	backend := queue.NewPostgresBackendWithPolicy(db, name, lease, policy)
+	testBackend(t, backend, backend, lease)
+}
@@ -0,0 +1,38 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"guiltmachine/internal/queue"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := queue.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	cases := map[int]time.Duration{
+		0: time.Second,
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second,
+		9: 10 * time.Second,
+	}
+	for attempt, want := range cases {
+		if got := p.Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	p := queue.DefaultRetryPolicy()
+
+	if p.Exhausted(p.MaxAttempts - 1) {
+		t.Fatal("expected retries left before MaxAttempts")
+	}
+	if !p.Exhausted(p.MaxAttempts) {
+		t.Fatal("expected policy to be exhausted at MaxAttempts")
+	}
+}
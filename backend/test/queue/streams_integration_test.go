@@ -0,0 +1,205 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/queue"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestStreams(t *testing.T, policy queue.RetryPolicy) (*queue.Streams, *queue.Consumer) {
+	t.Helper()
+	url := os.Getenv("TEST_REDIS_URL")
+	if url == "" {
+		t.Fatal("TEST_REDIS_URL not set")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("invalid TEST_REDIS_URL: %v", err)
+	}
+	client := redis.NewClient(opts)
+
+	name := "ml:entries:test:" + uuid.NewString()
+	t.Cleanup(func() {
+		ctx := context.Background()
+		client.Del(ctx, name, name+":dlq", name+":retry")
+		client.Close()
+	})
+
+	s := queue.NewStreamsWithPolicy(client, name, policy)
+	if err := s.EnsureGroup(context.Background(), "workers"); err != nil {
+		t.Fatalf("ensure group failed: %v", err)
+	}
+	return s, queue.NewConsumer(s, "workers", "consumer-1", 100*time.Millisecond)
+}
+
+func pollOne(t *testing.T, c *queue.Consumer) queue.Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries, err := c.Poll(context.Background())
+		if err != nil {
+			t.Fatalf("poll failed: %v", err)
+		}
+		if len(deliveries) > 0 {
+			return deliveries[0]
+		}
+	}
+	t.Fatal("timed out waiting for delivery")
+	return queue.Delivery{}
+}
+
+func TestStreamsRetryThenDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStreams(t, queue.RetryPolicy{MaxAttempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second})
+
+	if err := s.Publish(ctx, queue.EntryMLJob{EntryID: "entry-1"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	cause := errors.New("llm unavailable")
+	for attempt := 1; attempt <= 3; attempt++ {
+		d := pollOne(t, c)
+		if d.Job.Attempts != attempt-1 {
+			t.Fatalf("attempt %d: expected %d prior attempts, got %d", attempt, attempt-1, d.Job.Attempts)
+		}
+		dead, err := c.Fail(ctx, d, cause)
+		if err != nil {
+			t.Fatalf("fail failed: %v", err)
+		}
+		if dead != (attempt == 3) {
+			t.Fatalf("attempt %d: unexpected dead=%v", attempt, dead)
+		}
+	}
+
+	letters, err := s.DeadLetters(ctx, 10)
+	if err != nil {
+		t.Fatalf("list dlq failed: %v", err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	if letters[0].Job.EntryID != "entry-1" || letters[0].Job.Attempts != 3 || letters[0].Error != cause.Error() {
+		t.Fatalf("unexpected dead letter: %+v", letters[0])
+	}
+
+	// Replay resets the attempt counter and empties the DLQ
+	if err := s.Replay(ctx, letters[0].ID); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	d := pollOne(t, c)
+	if d.Job.EntryID != "entry-1" || d.Job.Attempts != 0 {
+		t.Fatalf("unexpected replayed job: %+v", d.Job)
+	}
+	if err := c.Ack(ctx, d); err != nil {
+		t.Fatalf("ack failed: %v", err)
+	}
+	if letters, _ := s.DeadLetters(ctx, 10); len(letters) != 0 {
+		t.Fatalf("expected empty dlq after replay, got %d", len(letters))
+	}
+}
+
+func TestStreamsUnackedJobStaysPending(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStreams(t, queue.DefaultRetryPolicy())
+
+	if err := s.Publish(ctx, queue.EntryMLJob{EntryID: "entry-2"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	d := pollOne(t, c)
+
+	// Nothing acked yet: the message must still be pending in the group
+	pending, err := s.Pending(ctx, "workers")
+	if err != nil {
+		t.Fatalf("pending failed: %v", err)
+	}
+	if pending != 1 {
+		t.Fatalf("expected 1 pending message, got %d", pending)
+	}
+
+	if err := c.Ack(ctx, d); err != nil {
+		t.Fatalf("ack failed: %v", err)
+	}
+	if pending, _ := s.Pending(ctx, "workers"); pending != 0 {
+		t.Fatalf("expected no pending messages after ack, got %d", pending)
+	}
+}
+
+func TestStreamsReplayUnknownID(t *testing.T) {
+	s, _ := newTestStreams(t, queue.DefaultRetryPolicy())
+
+	if err := s.Replay(context.Background(), "0-1"); !errors.Is(err, queue.ErrDeadLetterNotFound) {
+		t.Fatalf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+}
+
+func TestConsumerReclaimsAbandonedMessages(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStreams(t, queue.DefaultRetryPolicy())
+
+	dead := queue.NewConsumer(s, "workers", "dead-worker", 100*time.Millisecond)
+	live := queue.NewConsumerWithReclaim(s, "workers", "live-worker", 100*time.Millisecond, 200*time.Millisecond)
+
+	if err := s.Publish(ctx, queue.EntryMLJob{EntryID: "entry-3"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	// dead-worker receives the job and never acks it
+	if d := pollOne(t, dead); d.Job.EntryID != "entry-3" {
+		t.Fatalf("unexpected job: %+v", d.Job)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	d := pollOne(t, live)
+	if d.Job.EntryID != "entry-3" {
+		t.Fatalf("expected live-worker to reclaim entry-3, got %+v", d.Job)
+	}
+	if err := live.Ack(ctx, d); err != nil {
+		t.Fatalf("ack failed: %v", err)
+	}
+	if pending, _ := s.Pending(ctx, "workers"); pending != 0 {
+		t.Fatalf("expected no pending messages, got %d", pending)
+	}
+}
+
+func TestReclaimDeadLettersPoisonMessages(t *testing.T) {
+	ctx := context.Background()
+	s, c := newTestStreams(t, queue.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	if err := s.Publish(ctx, queue.EntryMLJob{EntryID: "entry-4"}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	pollOne(t, c)
+
+	// Each reclaim is a redelivery; a job that keeps crashing its worker ends up in the DLQ
+	for i := 0; i < 3; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if _, err := s.Reclaim(ctx, "workers", "consumer-2", 10*time.Millisecond, 10); err != nil {
+			t.Fatalf("reclaim failed: %v", err)
+		}
+	}
+
+	letters, err := s.DeadLetters(ctx, 10)
+	if err != nil {
+		t.Fatalf("list dlq failed: %v", err)
+	}
+	if len(letters) != 1 || letters[0].Job.EntryID != "entry-4" {
+		t.Fatalf("expected entry-4 to be dead-lettered, got %+v", letters)
+	}
+}
+
+func TestConsumerName(t *testing.T) {
+	a := queue.ConsumerName("ml-consumer")
+	host, _ := os.Hostname()
+	if !strings.HasPrefix(a, "ml-consumer-"+host+"-") {
+		t.Fatalf("unexpected consumer name %q", a)
+	}
+}
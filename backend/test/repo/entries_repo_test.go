@@ -66,6 +66,70 @@ func TestEntriesRepo(t *testing.T) {
 		}
 	})
 
+	t.Run("entries before an entry", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "entryhistory@test.com", "hashedpassword")
+		other, _ := repo.Users.CreateUser(ctx, "entryhistory-other@test.com", "hashedpassword")
+		old, _ := repo.Sessions.CreateSession(ctx, u.ID, nil)
+		s, _ := repo.Sessions.CreateSession(ctx, u.ID, nil)
+		foreign, _ := repo.Sessions.CreateSession(ctx, other.ID, nil)
+
+		_, _ = repo.Entries.CreateEntry(ctx, old.ID, "older session", 2)
+		_, _ = repo.Entries.CreateEntry(ctx, foreign.ID, "other user", 2)
+		e1, _ := repo.Entries.CreateEntry(ctx, s.ID, "first", 3)
+		e2, _ := repo.Entries.CreateEntry(ctx, s.ID, "second", 4)
+		cur, _ := repo.Entries.CreateEntry(ctx, s.ID, "current", 5)
+		_, _ = repo.Entries.CreateEntry(ctx, s.ID, "later", 6)
+
+		list, err := repo.Entries.ListEntriesBefore(ctx, cur.ID, false, 10)
+		if err != nil || len(list) != 2 {
+			t.Fatalf("expected two earlier entries, got %d (%v)", len(list), err)
+		}
+		if list[0].ID != e2.ID || list[1].ID != e1.ID {
+			t.Fatalf("expected newest first")
+		}
+
+		list, err = repo.Entries.ListEntriesBefore(ctx, cur.ID, true, 10)
+		if err != nil || len(list) != 3 || list[2].EntryText != "older session" {
+			t.Fatalf("expected the user's older session too, got %d (%v)", len(list), err)
+		}
+
+		list, _ = repo.Entries.ListEntriesBefore(ctx, cur.ID, true, 1)
+		if len(list) != 1 || list[0].ID != e2.ID {
+			t.Fatalf("expected limit to keep the newest entry")
+		}
+	})
+
+	t.Run("status transitions are guarded", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "entrystatus@test.com", "hashedpassword")
+		s, _ := repo.Sessions.CreateSession(ctx, u.ID, nil)
+		e, _ := repo.Entries.CreateEntry(ctx, s.ID, "status entry", 5)
+
+		if ok, err := repo.Entries.FinishEntry(ctx, e.ID, "job-a", "completed"); err != nil || ok {
+			t.Fatalf("expected pending entry not to finish (ok=%v, err=%v)", ok, err)
+		}
+		if ok, err := repo.Entries.ClaimEntry(ctx, e.ID, "job-a"); err != nil || !ok {
+			t.Fatalf("expected claim of pending entry (ok=%v, err=%v)", ok, err)
+		}
+		if ok, _ := repo.Entries.ClaimEntry(ctx, e.ID, "job-b"); ok {
+			t.Fatalf("expected claim by another job to fail")
+		}
+		if ok, _ := repo.Entries.ClaimEntry(ctx, e.ID, "job-a"); !ok {
+			t.Fatalf("expected redelivered job to reclaim")
+		}
+		if ok, _ := repo.Entries.FinishEntry(ctx, e.ID, "job-b", "completed"); ok {
+			t.Fatalf("expected finish by another job to fail")
+		}
+		if ok, _ := repo.Entries.FinishEntry(ctx, e.ID, "job-a", "completed"); !ok {
+			t.Fatalf("expected finish by claiming job")
+		}
+		if ok, _ := repo.Entries.ClaimEntry(ctx, e.ID, "job-a"); ok {
+			t.Fatalf("expected completed entry not to be claimable")
+		}
+		if err := repo.Entries.UpdateEntryStatus(ctx, e.ID, "bogus"); err == nil {
+			t.Fatalf("expected check violation for unknown status")
+		}
+	})
+
 	t.Run("fk session constraint", func(t *testing.T) {
 		// Try to create entry with non-existent session
 		fakeSessionID := [16]byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF, 0x00}
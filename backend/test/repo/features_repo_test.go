@@ -0,0 +1,128 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	sqlcrepo "guiltmachine/internal/repository/sqlc"
+
+	"github.com/google/uuid"
+)
+
+func TestFeatureSnapshotsRepo(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := sqlcrepo.New(db)
+
+	t.Run("active users and their signals", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "features@test.com", "hashedpassword")
+		idle, _ := repo.Users.CreateUser(ctx, "features-idle@test.com", "hashedpassword")
+		s, _ := repo.Sessions.CreateSession(ctx, u.ID, nil)
+		e, _ := repo.Entries.CreateEntry(ctx, s.ID, "signals test", 6)
+		_, _ = repo.Scores.UpsertEntryScore(ctx, s.ID, e.ID, 70, nil)
+		_, _ = repo.Tasks.CreateTask(ctx, sqlc.Task{UserID: u.ID, Title: "signals", Priority: 1}, sqlc.TaskEvent{EventType: "create"})
+
+		since := time.Now().Add(-time.Hour)
+		var active []uuid.UUID
+		for after := uuid.Nil; ; {
+			page, err := repo.Features.ListActiveUsers(ctx, since, after, 2)
+			if err != nil {
+				t.Fatalf("list active users failed: %v", err)
+			}
+			active = append(active, page...)
+			if len(page) < 2 {
+				break
+			}
+			after = page[len(page)-1]
+		}
+		if !slices.Contains(active, u.ID) || slices.Contains(active, idle.ID) {
+			t.Fatalf("expected only the active user listed, got %v", active)
+		}
+		if !slices.IsSortedFunc(active, func(a, b uuid.UUID) int { return slices.Compare(a[:], b[:]) }) {
+			t.Fatalf("expected active users in id order, got %v", active)
+		}
+
+		signals, err := repo.Features.GetUserSignals(ctx, u.ID, since, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("get user signals failed: %v", err)
+		}
+		if len(signals.SessionStarts) != 1 || len(signals.Entries) != 1 || len(signals.TaskEvents) != 1 {
+			t.Fatalf("unexpected signals %+v", signals)
+		}
+		if row := signals.Entries[0]; row.AggregateScore.Int32 != 70 || row.GuiltLevel.Int32 != 6 {
+			t.Fatalf("expected the entry with its score, got %+v", row)
+		}
+	})
+
+	t.Run("store and read snapshots", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "features-snap@test.com", "hashedpassword")
+		end := time.Now().UTC().Truncate(time.Second)
+
+		for i, score := range []float64{0.2, 0.6} {
+			_, err := repo.Features.CreateFeatureSnapshot(ctx, sqlc.FeatureSnapshot{
+				UserID:               u.ID,
+				ProcrastinationScore: score,
+				SnoozeRate:           0.5,
+				CompletionLatencyAvg: 42,
+				BurstinessIndex:      -0.25,
+				ModeCluster:          1,
+				ClusterVersion:       int32(i),
+				WindowStart:          end.Add(-24 * time.Hour),
+				WindowEnd:            end.Add(time.Duration(i) * time.Hour),
+			})
+			if err != nil {
+				t.Fatalf("create snapshot failed: %v", err)
+			}
+		}
+
+		latest, err := repo.Features.GetLatestFeatureSnapshot(ctx, u.ID)
+		if err != nil || latest.ProcrastinationScore != 0.6 || latest.CompletionLatencyAvg != 42 || latest.ClusterVersion != 1 {
+			t.Fatalf("expected the second snapshot as latest, got %+v (%v)", latest, err)
+		}
+		list, err := repo.Features.ListFeatureSnapshotsByUser(ctx, u.ID, 10, 0)
+		if err != nil || len(list) != 2 || list[0].ID != latest.ID {
+			t.Fatalf("expected two snapshots newest first, got %+v (%v)", list, err)
+		}
+
+		if _, err := repo.Features.CreateFeatureSnapshot(ctx, sqlc.FeatureSnapshot{
+			UserID:               u.ID,
+			ProcrastinationScore: 1.5,
+			WindowStart:          end.Add(-time.Hour),
+			WindowEnd:            end,
+		}); err == nil {
+			t.Fatalf("expected a check violation for a score above 1")
+		}
+
+		other, _ := repo.Users.CreateUser(ctx, "features-none@test.com", "hashedpassword")
+		if _, err := repo.Features.GetLatestFeatureSnapshot(ctx, other.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected sql.ErrNoRows without snapshots, got %v", err)
+		}
+	})
+	t.Run("cluster model versions", func(t *testing.T) {
+		first, err := repo.Features.CreateClusterModel(ctx, json.RawMessage(`[{"mode":"steady","center":[0,0,0,0]}]`), 12)
+		if err != nil {
+			t.Fatalf("create cluster model failed: %v", err)
+		}
+		second, err := repo.Features.CreateClusterModel(ctx, json.RawMessage(`[{"mode":"bursty","center":[1,1,1,1]}]`), 40)
+		if err != nil {
+			t.Fatalf("create cluster model failed: %v", err)
+		}
+		if second.Version <= first.Version {
+			t.Fatalf("expected increasing versions, got %d then %d", first.Version, second.Version)
+		}
+
+		latest, err := repo.Features.GetLatestClusterModel(ctx)
+		if err != nil || latest.Version != second.Version || latest.SampleSize != 40 {
+			t.Fatalf("expected the second model as latest, got %+v (%v)", latest, err)
+		}
+		if _, err := repo.Features.CreateClusterModel(ctx, json.RawMessage(`[]`), 0); err == nil {
+			t.Fatalf("expected a check violation for an empty sample")
+		}
+	})
+}
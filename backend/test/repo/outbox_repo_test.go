@@ -0,0 +1,182 @@
+package repo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	sqlc "guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/repository"
+	sqlcrepo "guiltmachine/internal/repository/sqlc"
+)
+
+func TestOutboxRepo(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := sqlcrepo.New(db)
+
+	u, _ := repo.Users.CreateUser(ctx, "outbox@test.com", "hashedpassword")
+	s, _ := repo.Sessions.CreateSession(ctx, u.ID, nil)
+
+	t.Run("entry and outbox row commit together", func(t *testing.T) {
+		e, err := repo.Entries.CreateEntryWithOutbox(ctx, s.ID, "outbox entry", 4, "test.topic", func(e sqlc.GuiltEntry) ([]byte, error) {
+			return json.Marshal(map[string]string{"entry_id": e.ID.String()})
+		})
+		if err != nil {
+			t.Fatalf("create entry with outbox failed: %v", err)
+		}
+
+		got, err := repo.Entries.GetEntry(ctx, e.ID)
+		if err != nil {
+			t.Fatalf("get entry failed: %v", err)
+		}
+		if got.Status.String != "pending" {
+			t.Fatalf("expected pending entry, got %q", got.Status.String)
+		}
+
+		var relayed []sqlc.Outbox
+		if _, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+			relayed = append(relayed, m)
+			return nil
+		}); err != nil {
+			t.Fatalf("relay failed: %v", err)
+		}
+		found := false
+		for _, m := range relayed {
+			var body map[string]string
+			_ = json.Unmarshal(m.Payload, &body)
+			found = found || body["entry_id"] == e.ID.String()
+		}
+		if !found {
+			t.Fatal("expected outbox message for the entry")
+		}
+	})
+
+	t.Run("payload failure rolls back the entry", func(t *testing.T) {
+		before, _ := repo.Entries.ListEntriesBySession(ctx, s.ID)
+		_, err := repo.Entries.CreateEntryWithOutbox(ctx, s.ID, "never stored", 4, "test.topic", func(sqlc.GuiltEntry) ([]byte, error) {
+			return nil, errors.New("boom")
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		after, _ := repo.Entries.ListEntriesBySession(ctx, s.ID)
+		if len(after) != len(before) {
+			t.Fatalf("expected entry insert to be rolled back")
+		}
+	})
+
+	t.Run("failed publish stays unsent", func(t *testing.T) {
+		_, _ = repo.Entries.CreateEntryWithOutbox(ctx, s.ID, "retry me", 4, "test.topic", func(e sqlc.GuiltEntry) ([]byte, error) {
+			return []byte(`{}`), nil
+		})
+
+		sent, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+			return errors.New("queue down")
+		})
+		if err != nil || sent != 0 {
+			t.Fatalf("expected nothing sent, got %d (%v)", sent, err)
+		}
+
+		var retried bool
+		if _, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+			retried = retried || (m.Attempts == 1 && m.LastError.String == "queue down")
+			return nil
+		}); err != nil {
+			t.Fatalf("relay failed: %v", err)
+		}
+		if !retried {
+			t.Fatal("expected the failed message to be relayed again with its failure recorded")
+		}
+
+		if _, err := repo.Outbox.DeleteSent(ctx, time.Now().Add(time.Minute)); err != nil {
+			t.Fatalf("delete sent failed: %v", err)
+		}
+	})
+
+	t.Run("undeliverable message does not block later ones", func(t *testing.T) {
+		for _, topic := range []string{"test.poison", "test.topic"} {
+			_, _ = repo.Entries.CreateEntryWithOutbox(ctx, s.ID, topic, 4, topic, func(e sqlc.GuiltEntry) ([]byte, error) {
+				return []byte(`{}`), nil
+			})
+		}
+
+		var relayed []string
+		sent, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+			relayed = append(relayed, m.Topic)
+			if m.Topic == "test.poison" {
+				return fmt.Errorf("%w: unknown topic", repository.ErrUndeliverable)
+			}
+			return nil
+		})
+		if err != nil || sent != 1 || len(relayed) != 2 {
+			t.Fatalf("expected the later message sent, got %d of %v (%v)", sent, relayed, err)
+		}
+
+		var again bool
+		if _, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+			again = again || m.Topic == "test.poison"
+			return nil
+		}); err != nil {
+			t.Fatalf("relay failed: %v", err)
+		}
+		if again {
+			t.Fatal("expected the dead message not to be relayed again")
+		}
+	})
+	t.Run("dead message fails its entry", func(t *testing.T) {
+		e, _ := repo.Entries.CreateEntryWithOutbox(ctx, s.ID, "poisoned", 4, "test.poison", func(e sqlc.GuiltEntry) ([]byte, error) {
+			return []byte(`{}`), nil
+		})
+		if _, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+			if m.Topic == "test.poison" {
+				return fmt.Errorf("%w: unknown topic", repository.ErrUndeliverable)
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("relay failed: %v", err)
+		}
+		if got, _ := repo.Entries.GetEntry(ctx, e.ID); got.Status.String != "failed" {
+			t.Fatalf("expected the entry failed, got %q", got.Status.String)
+		}
+	})
+
+	t.Run("a publishing relay holds no locks", func(t *testing.T) {
+		_, _ = repo.Entries.CreateEntryWithOutbox(ctx, s.ID, "slow queue", 4, "test.topic", func(e sqlc.GuiltEntry) ([]byte, error) {
+			return []byte(`{}`), nil
+		})
+
+		publishing, release := make(chan struct{}), make(chan struct{})
+		var once sync.Once
+		done := make(chan error, 1)
+		go func() {
+			_, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+				once.Do(func() { close(publishing) })
+				<-release
+				return nil
+			})
+			done <- err
+		}()
+		<-publishing
+
+		// a second relay neither waits for the first nor gets its messages
+		var doubled bool
+		if _, err := repo.Outbox.RelayBatch(ctx, 100, func(ctx context.Context, m sqlc.Outbox) error {
+			doubled = true
+			return nil
+		}); err != nil {
+			t.Fatalf("relay failed: %v", err)
+		}
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("relay failed: %v", err)
+		}
+		if doubled {
+			t.Fatal("expected claimed messages not to be handed to another relay")
+		}
+	})
+}
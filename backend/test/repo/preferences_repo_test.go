@@ -2,9 +2,14 @@ package repo_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
 
+	"guiltmachine/internal/db/sqlc"
 	sqlcrepo "guiltmachine/internal/repository/sqlc"
+
+	"github.com/google/uuid"
 )
 
 func TestPreferencesRepo(t *testing.T) {
@@ -19,8 +24,15 @@ func TestPreferencesRepo(t *testing.T) {
 		}
 
 		// Upsert prefs
-		theme := "dark"
-		pref, err := repo.Preferences.UpsertPreferences(ctx, u.ID, &theme, true, nil)
+		pref, err := repo.Preferences.UpsertPreferences(ctx, sqlc.UserPreference{
+			UserID:               u.ID,
+			Theme:                sql.NullString{String: "dark", Valid: true},
+			NotificationsEnabled: true,
+			Persona:              "roast",
+			HumorIntensity:       3,
+			Timezone:             "UTC",
+			Language:             "en",
+		})
 		if err != nil {
 			t.Fatalf("upsert preferences failed: %v", err)
 		}
@@ -39,8 +51,7 @@ func TestPreferencesRepo(t *testing.T) {
 		u, _ := repo.Users.CreateUser(ctx, "prefsupsert@test.com", "hashedpassword")
 
 		// First upsert
-		theme1 := "light"
-		pref1, err := repo.Preferences.UpsertPreferences(ctx, u.ID, &theme1, false, nil)
+		pref1, err := repo.Preferences.UpsertPreferences(ctx, prefsWithTheme(u.ID, "light"))
 		if err != nil {
 			t.Fatalf("first upsert failed: %v", err)
 		}
@@ -49,8 +60,7 @@ func TestPreferencesRepo(t *testing.T) {
 		}
 
 		// Second upsert with different theme
-		theme2 := "dark"
-		pref2, err := repo.Preferences.UpsertPreferences(ctx, u.ID, &theme2, true, nil)
+		pref2, err := repo.Preferences.UpsertPreferences(ctx, prefsWithTheme(u.ID, "dark"))
 		if err != nil {
 			t.Fatalf("second upsert failed: %v", err)
 		}
@@ -65,13 +75,80 @@ func TestPreferencesRepo(t *testing.T) {
 		}
 	})
 
+	t.Run("update starts from defaults and keeps other fields", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "prefsupdate@test.com", "hashedpassword")
+
+		pref, err := repo.Preferences.UpdatePreferences(ctx, u.ID, func(p *sqlc.UserPreference) error {
+			if p.Persona != "roast" || p.HumorIntensity != 3 || p.Timezone != "UTC" || !p.NotificationsEnabled {
+				t.Errorf("expected column defaults, got %+v", p)
+			}
+			p.Persona = "coach"
+			p.WorkStartMinute = sql.NullInt32{Int32: 9 * 60, Valid: true}
+			p.WorkEndMinute = sql.NullInt32{Int32: 17 * 60, Valid: true}
+			p.NudgeChannels = []string{"push", "email"}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("update preferences failed: %v", err)
+		}
+		if pref.Persona != "coach" || len(pref.NudgeChannels) != 2 || pref.WorkEndMinute.Int32 != 17*60 {
+			t.Fatalf("update not saved: %+v", pref)
+		}
+
+		pref, _ = repo.Preferences.UpdatePreferences(ctx, u.ID, func(p *sqlc.UserPreference) error {
+			p.HumorIntensity = 9
+			return nil
+		})
+		if pref.Persona != "coach" || pref.HumorIntensity != 9 || pref.NudgeChannels[1] != "email" {
+			t.Fatalf("expected earlier fields to survive, got %+v", pref)
+		}
+	})
+
+	t.Run("failed update saves nothing", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "prefsabort@test.com", "hashedpassword")
+		_, err := repo.Preferences.UpdatePreferences(ctx, u.ID, func(p *sqlc.UserPreference) error {
+			p.Persona = "coach"
+			return errors.New("rejected")
+		})
+		if err == nil {
+			t.Fatalf("expected the update error")
+		}
+		if _, err := repo.Preferences.GetPreferencesByUserID(ctx, u.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected no preferences row, got %v", err)
+		}
+	})
+
+	t.Run("check constraints", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "prefscheck@test.com", "hashedpassword")
+		bad := prefsWithTheme(u.ID, "dark")
+		bad.HumorIntensity = 11
+		if _, err := repo.Preferences.UpsertPreferences(ctx, bad); err == nil {
+			t.Fatalf("expected check violation for humor_intensity")
+		}
+		bad = prefsWithTheme(u.ID, "dark")
+		bad.NudgeChannels = []string{"pigeon"}
+		if _, err := repo.Preferences.UpsertPreferences(ctx, bad); err == nil {
+			t.Fatalf("expected check violation for nudge_channels")
+		}
+	})
+
 	t.Run("fk user constraint", func(t *testing.T) {
 		// Try to upsert preferences for non-existent user
 		fakeUserID := [16]byte{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99}
 		uuid := [16]byte(fakeUserID)
-		_, err := repo.Preferences.UpsertPreferences(ctx, uuid, nil, false, nil)
+		_, err := repo.Preferences.UpsertPreferences(ctx, prefsWithTheme(uuid, "dark"))
 		if err == nil {
 			t.Fatalf("expected FK violation for non-existent user")
 		}
 	})
 }
+
+func prefsWithTheme(userID uuid.UUID, theme string) sqlc.UserPreference {
+	return sqlc.UserPreference{
+		UserID:   userID,
+		Theme:    sql.NullString{String: theme, Valid: true},
+		Persona:  "roast",
+		Timezone: "UTC",
+		Language: "en",
+	}
+}
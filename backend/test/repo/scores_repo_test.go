@@ -31,6 +31,28 @@ func TestScoresRepo(t *testing.T) {
 		}
 	})
 
+	t.Run("upsert keeps one score per entry", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "scoreupsert@test.com", "hashedpassword")
+		s, _ := repo.Sessions.CreateSession(ctx, u.ID, nil)
+		e, _ := repo.Entries.CreateEntry(ctx, s.ID, "upsert test", 5)
+
+		first, err := repo.Scores.UpsertEntryScore(ctx, s.ID, e.ID, 40, nil)
+		if err != nil {
+			t.Fatalf("first upsert failed: %v", err)
+		}
+		second, err := repo.Scores.UpsertEntryScore(ctx, s.ID, e.ID, 90, nil)
+		if err != nil {
+			t.Fatalf("second upsert failed: %v", err)
+		}
+		if second.ID != first.ID || second.AggregateScore != 90 {
+			t.Fatalf("expected row %s updated to 90, got %s = %d", first.ID, second.ID, second.AggregateScore)
+		}
+
+		if _, err := repo.Scores.CreateScore(ctx, s.ID, &e.ID, 10, nil); err == nil {
+			t.Fatalf("expected unique violation for a second entry score")
+		}
+	})
+
 	t.Run("fk session constraint", func(t *testing.T) {
 		// Try to create score with non-existent session
 		fakeSessionID := [16]byte{0xFF, 0xEE, 0xDD, 0xCC, 0xBB, 0xAA, 0x99, 0x88, 0x77, 0x66, 0x55, 0x44, 0x33, 0x22, 0x11, 0x00}
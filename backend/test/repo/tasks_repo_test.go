@@ -0,0 +1,148 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	sqlcrepo "guiltmachine/internal/repository/sqlc"
+)
+
+func TestTasksRepo(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := sqlcrepo.New(db)
+	created := sqlc.TaskEvent{EventType: "create"}
+
+	t.Run("create, update and soft delete", func(t *testing.T) {
+		u, err := repo.Users.CreateUser(ctx, "tasks@test.com", "hashedpassword")
+		if err != nil {
+			t.Fatalf("create user failed: %v", err)
+		}
+
+		task, err := repo.Tasks.CreateTask(ctx, sqlc.Task{
+			UserID:           u.ID,
+			Title:            "file taxes",
+			Category:         sql.NullString{String: "admin", Valid: true},
+			Priority:         2,
+			EstimatedMinutes: sql.NullInt32{Int32: 90, Valid: true},
+		}, created)
+		if err != nil {
+			t.Fatalf("create task failed: %v", err)
+		}
+		if task.Status != "pending" || task.DeletedAt.Valid || task.SnoozeCount != 0 {
+			t.Fatalf("expected a live pending task, got %+v", task)
+		}
+
+		updated, err := repo.Tasks.UpdateTask(ctx, task.ID, func(cur *sqlc.Task) (*sqlc.TaskEvent, error) {
+			cur.Status = "completed"
+			cur.Priority = 3
+			cur.SnoozeCount = 2
+			return &sqlc.TaskEvent{EventType: "complete", EventPayload: json.RawMessage(`{"previous_status":"pending"}`)}, nil
+		})
+		if err != nil {
+			t.Fatalf("update task failed: %v", err)
+		}
+		if updated.Status != "completed" || updated.Priority != 3 || updated.SnoozeCount != 2 || updated.Title != "file taxes" {
+			t.Fatalf("unexpected updated task %+v", updated)
+		}
+
+		// an error from update saves nothing
+		_, err = repo.Tasks.UpdateTask(ctx, task.ID, func(cur *sqlc.Task) (*sqlc.TaskEvent, error) {
+			cur.Title = "changed"
+			return nil, errors.New("abort")
+		})
+		if err == nil {
+			t.Fatalf("expected the update error")
+		}
+		got, _ := repo.Tasks.GetTask(ctx, task.ID)
+		if got.Title != "file taxes" {
+			t.Fatalf("expected an aborted update to save nothing, got %q", got.Title)
+		}
+
+		if err := repo.Tasks.DeleteTask(ctx, task.ID, sqlc.TaskEvent{EventType: "delete"}); err != nil {
+			t.Fatalf("delete task failed: %v", err)
+		}
+		if _, err := repo.Tasks.GetTask(ctx, task.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected deleted task to be gone, got %v", err)
+		}
+		if err := repo.Tasks.DeleteTask(ctx, task.ID, sqlc.TaskEvent{EventType: "delete"}); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected second delete to find nothing, got %v", err)
+		}
+		if _, err := repo.Tasks.UpdateTask(ctx, task.ID, func(*sqlc.Task) (*sqlc.TaskEvent, error) { return nil, nil }); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected deleted task to be immutable, got %v", err)
+		}
+
+		// the aborted update and the failed second delete recorded nothing
+		events, err := repo.TaskEvents.ListTaskEventsByUser(ctx, u.ID, nil, time.Time{}, time.Time{}, 10, 0)
+		if err != nil {
+			t.Fatalf("list task events failed: %v", err)
+		}
+		var types []string
+		for _, e := range events {
+			if e.TaskID != task.ID {
+				t.Fatalf("event of another task %+v", e)
+			}
+			types = append(types, e.EventType)
+		}
+		if strings.Join(types, ",") != "create,complete,delete" {
+			t.Fatalf("expected create, complete, delete; got %v", types)
+		}
+		if string(events[0].EventPayload) != "{}" {
+			t.Fatalf("expected an empty payload to be stored as {}, got %s", events[0].EventPayload)
+		}
+
+		completes, _ := repo.TaskEvents.ListTaskEventsByUser(ctx, u.ID, []string{"complete"}, time.Time{}, time.Time{}, 10, 0)
+		if len(completes) != 1 || completes[0].ID != events[1].ID {
+			t.Fatalf("expected only the complete event, got %+v", completes)
+		}
+		after, _ := repo.TaskEvents.ListTaskEventsByUser(ctx, u.ID, nil, events[1].CreatedAt, time.Time{}, 10, 0)
+		before, _ := repo.TaskEvents.ListTaskEventsByUser(ctx, u.ID, nil, time.Time{}, events[1].CreatedAt, 10, 0)
+		if len(after)+len(before) != 3 || len(before) == 0 || len(after) == 0 {
+			t.Fatalf("expected since and until to split the events, got %d and %d", len(before), len(after))
+		}
+
+		if _, err := db.ExecContext(ctx, `UPDATE task_events SET event_type = 'modify' WHERE id = $1`, events[0].ID); err == nil {
+			t.Fatalf("expected task_events to reject updates")
+		}
+	})
+
+	t.Run("list by due date and status", func(t *testing.T) {
+		u, _ := repo.Users.CreateUser(ctx, "tasks-list@test.com", "hashedpassword")
+		now := time.Now()
+		due := func(d time.Duration) sql.NullTime { return sql.NullTime{Time: now.Add(d), Valid: true} }
+
+		undated, _ := repo.Tasks.CreateTask(ctx, sqlc.Task{UserID: u.ID, Title: "someday"}, created)
+		later, _ := repo.Tasks.CreateTask(ctx, sqlc.Task{UserID: u.ID, Title: "later", DueAt: due(48 * time.Hour)}, created)
+		soon, _ := repo.Tasks.CreateTask(ctx, sqlc.Task{UserID: u.ID, Title: "soon", DueAt: due(time.Hour)}, created)
+		gone, _ := repo.Tasks.CreateTask(ctx, sqlc.Task{UserID: u.ID, Title: "gone", DueAt: due(time.Minute)}, created)
+		_ = repo.Tasks.DeleteTask(ctx, gone.ID, sqlc.TaskEvent{EventType: "delete"})
+		_, _ = repo.Tasks.UpdateTask(ctx, later.ID, func(cur *sqlc.Task) (*sqlc.TaskEvent, error) {
+			cur.Status = "completed"
+			return nil, nil
+		})
+
+		list, err := repo.Tasks.ListTasksByUser(ctx, u.ID, "", 10, 0)
+		if err != nil {
+			t.Fatalf("list tasks failed: %v", err)
+		}
+		if len(list) != 3 || list[0].ID != soon.ID || list[1].ID != later.ID || list[2].ID != undated.ID {
+			t.Fatalf("expected soon, later, someday; got %+v", list)
+		}
+
+		pending, err := repo.Tasks.ListTasksByUser(ctx, u.ID, "pending", 10, 0)
+		if err != nil || len(pending) != 2 {
+			t.Fatalf("expected 2 pending tasks, got %d (%v)", len(pending), err)
+		}
+
+		page, _ := repo.Tasks.ListTasksByUser(ctx, u.ID, "", 1, 1)
+		if len(page) != 1 || page[0].ID != later.ID {
+			t.Fatalf("expected the second task on page two, got %+v", page)
+		}
+	})
+}
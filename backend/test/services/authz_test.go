@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"guiltmachine/internal/auth"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthorizer(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	authz := services.NewAuthorizer(repos.Sessions, repos.Entries)
+
+	alice, _ := repos.Users.CreateUser(ctx, "alice@test.com", "hash")
+	bob, _ := repos.Users.CreateUser(ctx, "bob@test.com", "hash")
+	aliceSession, _ := repos.Sessions.CreateSession(ctx, alice.ID, nil)
+	aliceEntry, _ := repos.Entries.CreateEntry(ctx, aliceSession.ID, "skipped the gym", 4)
+
+	asAlice := auth.ContextWithUserID(ctx, alice.ID.String())
+	asBob := auth.ContextWithUserID(ctx, bob.ID.String())
+
+	t.Run("owner can access own resources", func(t *testing.T) {
+		if err := authz.CanAccessUser(asAlice, alice.ID.String()); err != nil {
+			t.Fatalf("user: %v", err)
+		}
+		if err := authz.CanAccessSession(asAlice, aliceSession.ID.String()); err != nil {
+			t.Fatalf("session: %v", err)
+		}
+		if err := authz.CanAccessEntry(asAlice, aliceEntry.ID.String()); err != nil {
+			t.Fatalf("entry: %v", err)
+		}
+	})
+
+	t.Run("other user is denied", func(t *testing.T) {
+		if err := authz.CanAccessUser(asBob, alice.ID.String()); !errors.Is(err, services.ErrPermissionDenied) {
+			t.Fatalf("user: expected ErrPermissionDenied, got %v", err)
+		}
+		if err := authz.CanAccessSession(asBob, aliceSession.ID.String()); !errors.Is(err, services.ErrPermissionDenied) {
+			t.Fatalf("session: expected ErrPermissionDenied, got %v", err)
+		}
+		if err := authz.CanAccessEntry(asBob, aliceEntry.ID.String()); !errors.Is(err, services.ErrPermissionDenied) {
+			t.Fatalf("entry: expected ErrPermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("unknown resources are denied", func(t *testing.T) {
+		if err := authz.CanAccessSession(asAlice, uuid.NewString()); !errors.Is(err, services.ErrPermissionDenied) {
+			t.Fatalf("session: expected ErrPermissionDenied, got %v", err)
+		}
+		if err := authz.CanAccessEntry(asAlice, uuid.NewString()); !errors.Is(err, services.ErrPermissionDenied) {
+			t.Fatalf("entry: expected ErrPermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("unauthenticated context is denied", func(t *testing.T) {
+		if err := authz.CanAccessSession(ctx, aliceSession.ID.String()); !errors.Is(err, services.ErrPermissionDenied) {
+			t.Fatalf("expected ErrPermissionDenied, got %v", err)
+		}
+	})
+
+	t.Run("malformed ids are rejected", func(t *testing.T) {
+		if err := authz.CanAccessSession(asAlice, "not-a-uuid"); !errors.Is(err, services.ErrInvalidResourceID) {
+			t.Fatalf("expected ErrInvalidResourceID, got %v", err)
+		}
+	})
+}
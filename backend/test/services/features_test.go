@@ -0,0 +1,215 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+
+	"github.com/google/uuid"
+)
+
+func TestSnapshotUser(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	features := services.NewFeatureService(repos.Features, services.DefaultFeatureConfig)
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	user, _ := repos.Users.CreateUser(ctx, "features@test.com", "hash")
+	uid := user.ID.String()
+
+	session, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+	entry, _ := repos.Entries.CreateEntry(ctx, session.ID, "skipped the gym", 7)
+	_, _ = repos.Scores.UpsertEntryScore(ctx, session.ID, entry.ID, 80, nil)
+	_, _ = repos.Entries.CreateEntry(ctx, session.ID, "not scored yet", 3)
+
+	overdue, _ := tasks.CreateTask(ctx, uid, sqlc.Task{Title: "taxes", DueAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}})
+	_, _ = tasks.CompleteTask(ctx, overdue.ID.String())
+	snoozed, _ := tasks.CreateTask(ctx, uid, sqlc.Task{Title: "dentist"})
+	for range 3 {
+		if _, err := tasks.SnoozeTask(ctx, snoozed.ID.String(), services.Snooze{For: time.Hour}); err != nil {
+			t.Fatalf("SnoozeTask failed: %v", err)
+		}
+	}
+
+	// before the batch job reaches the user the mode is assigned online
+	if mode, err := features.BehaviorMode(ctx, user.ID); err != nil || mode != ml.ModeProcrastinating {
+		t.Fatalf("expected the mode of an online snapshot, got %d (%v)", mode, err)
+	}
+
+	now := time.Now().Add(time.Minute)
+	snap, err := features.SnapshotUser(ctx, user.ID, now)
+	if err != nil {
+		t.Fatalf("SnapshotUser failed: %v", err)
+	}
+	if snap.UserID != user.ID || !snap.WindowEnd.Equal(now) || !snap.WindowStart.Equal(now.Add(-services.DefaultFeatureConfig.Window)) {
+		t.Fatalf("unexpected snapshot window %+v", snap)
+	}
+	// 3 snoozes against 1 completion, the one dated task late, one entry at 0.8
+	want := (0.4*0.75 + 0.35*1 + 0.25*0.8) / (0.4 + 0.35 + 0.25)
+	if snap.SnoozeRate != 0.75 || !approx(snap.ProcrastinationScore, want) {
+		t.Fatalf("expected snooze rate 0.75 and score %f, got %+v", want, snap)
+	}
+	if snap.ModeCluster != ml.ModeProcrastinating || snap.ClusterVersion != 0 {
+		t.Fatalf("expected the built-in model to call it procrastinating, got %+v", snap)
+	}
+	if mode, err := features.BehaviorMode(ctx, user.ID); err != nil || mode != ml.ModeProcrastinating {
+		t.Fatalf("expected the mode of the snapshot, got %d (%v)", mode, err)
+	}
+
+	latest, err := features.GetLatestFeatureSnapshot(ctx, uid)
+	if err != nil || latest.ID != snap.ID {
+		t.Fatalf("expected the snapshot back, got %+v (%v)", latest, err)
+	}
+}
+
+func TestSnapshotAll(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	features := services.NewFeatureService(repos.Features, services.FeatureConfig{Window: time.Hour, BatchSize: 2})
+
+	var active []string
+	for _, email := range []string{"a@test.com", "b@test.com", "c@test.com", "d@test.com", "e@test.com"} {
+		user, _ := repos.Users.CreateUser(ctx, email, "hash")
+		_, _ = repos.Sessions.CreateSession(ctx, user.ID, nil)
+		active = append(active, user.ID.String())
+	}
+	idle, _ := repos.Users.CreateUser(ctx, "idle@test.com", "hash")
+
+	now := time.Now().Add(time.Minute)
+	run, err := features.SnapshotAll(ctx, now)
+	if err != nil {
+		t.Fatalf("SnapshotAll failed: %v", err)
+	}
+	if run.Snapshots != len(active) || run.Failures != 0 || run.Trained || run.ClusterVersion != 0 {
+		t.Fatalf("expected a snapshot per active user across batches, got %+v", run)
+	}
+	for _, uid := range active {
+		if _, err := features.GetLatestFeatureSnapshot(ctx, uid); err != nil {
+			t.Fatalf("expected a snapshot for %s, got %v", uid, err)
+		}
+	}
+	if list, _ := features.ListFeatureSnapshots(ctx, idle.ID.String(), 0, 0); len(list) != 0 {
+		t.Fatalf("expected no snapshot for an idle user, got %+v", list)
+	}
+	if mode, err := features.BehaviorMode(ctx, idle.ID); err != nil || mode != ml.ModeIdle {
+		t.Fatalf("expected users without snapshots to be idle, got %d (%v)", mode, err)
+	}
+
+	// users the job has not reached get a snapshot computed on the spot,
+	// which reading leaves unstored
+	for i := 0; i < 2; i++ {
+		online, err := features.GetLatestFeatureSnapshot(ctx, idle.ID.String())
+		if err != nil || online.UserID != idle.ID || online.ModeCluster != ml.ModeIdle || online.ID != uuid.Nil {
+			t.Fatalf("expected an unstored idle snapshot on the spot, got %+v (%v)", online, err)
+		}
+	}
+	if list, _ := features.ListFeatureSnapshots(ctx, idle.ID.String(), 0, 0); len(list) != 0 {
+		t.Fatalf("expected the online snapshot not to be stored, got %+v", list)
+	}
+
+	// a later window no longer sees the sessions
+	run, err = features.SnapshotAll(ctx, now.Add(2*time.Hour))
+	if err != nil || run.Snapshots != 0 {
+		t.Fatalf("expected nobody active two hours later, got %+v (%v)", run, err)
+	}
+
+	_, _ = features.SnapshotAll(ctx, now.Add(time.Second))
+	list, err := features.ListFeatureSnapshots(ctx, active[0], 0, 0)
+	if err != nil || len(list) != 2 || !list[0].WindowEnd.After(list[1].WindowEnd) {
+		t.Fatalf("expected two snapshots newest first, got %+v (%v)", list, err)
+	}
+	if list, _ := features.ListFeatureSnapshots(ctx, active[0], 1, 1); len(list) != 1 || !list[0].WindowEnd.Equal(now) {
+		t.Fatalf("expected the older snapshot on the second page, got %+v", list)
+	}
+
+	if _, err := features.GetLatestFeatureSnapshot(ctx, "nope"); !errors.Is(err, services.ErrInvalidInsights) {
+		t.Fatalf("expected ErrInvalidInsights for a bad user id, got %v", err)
+	}
+	if _, err := features.ListFeatureSnapshots(ctx, active[0], 10, -1); !errors.Is(err, services.ErrInvalidInsights) {
+		t.Fatalf("expected ErrInvalidInsights for a negative offset, got %v", err)
+	}
+}
+
+func TestSnapshotAllTrainsClusters(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	features := services.NewFeatureService(repos.Features, services.FeatureConfig{Window: 24 * time.Hour, MinTrainUsers: 6})
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+
+	// snoozers, finishers and late finishers; two task events each keep
+	// burstiness, which would depend on timing, out of it
+	var procrastinators, others []uuid.UUID
+	for i := range 9 {
+		user, _ := repos.Users.CreateUser(ctx, fmt.Sprintf("cluster-%d@test.com", i), "hash")
+		switch i % 3 {
+		case 0:
+			task, _ := tasks.CreateTask(ctx, user.ID.String(), sqlc.Task{Title: "report"})
+			_, _ = tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: time.Hour})
+			procrastinators = append(procrastinators, user.ID)
+		case 1:
+			task, _ := tasks.CreateTask(ctx, user.ID.String(), sqlc.Task{Title: "report"})
+			_, _ = tasks.CompleteTask(ctx, task.ID.String())
+			others = append(others, user.ID)
+		case 2:
+			task, _ := tasks.CreateTask(ctx, user.ID.String(), sqlc.Task{Title: "report", DueAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}})
+			_, _ = tasks.CompleteTask(ctx, task.ID.String())
+			others = append(others, user.ID)
+		}
+	}
+
+	run, err := features.SnapshotAll(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("SnapshotAll failed: %v", err)
+	}
+	if !run.Trained || run.ClusterVersion != 1 || run.Snapshots != 9 {
+		t.Fatalf("expected a first trained model, got %+v", run)
+	}
+	for _, id := range procrastinators {
+		snap, _ := repos.Features.GetLatestFeatureSnapshot(ctx, id)
+		if snap.ModeCluster != ml.ModeProcrastinating || snap.ClusterVersion != 1 {
+			t.Fatalf("expected snoozers to cluster as procrastinating, got %+v", snap)
+		}
+	}
+	for _, id := range others {
+		if snap, _ := repos.Features.GetLatestFeatureSnapshot(ctx, id); snap.ModeCluster == ml.ModeProcrastinating {
+			t.Fatalf("expected finishers outside the procrastinating cluster, got %+v", snap)
+		}
+	}
+
+	stored, err := repos.Features.GetLatestClusterModel(ctx)
+	if err != nil || stored.SampleSize != 9 {
+		t.Fatalf("expected the model stored with its sample size, got %+v (%v)", stored, err)
+	}
+	model, err := ml.ParseCentroids(stored.Version, stored.Centroids)
+	if err != nil {
+		t.Fatalf("expected stored centroids to parse, got %v", err)
+	}
+	modes := map[int]bool{}
+	for _, c := range model.Centroids {
+		modes[c.Mode] = true
+	}
+	if len(modes) != ml.ClusterCount {
+		t.Fatalf("expected a centroid per mode, got %+v", model.Centroids)
+	}
+
+	// users assigned online use the newest version
+	late, _ := repos.Users.CreateUser(ctx, "cluster-late@test.com", "hash")
+	task, _ := tasks.CreateTask(ctx, late.ID.String(), sqlc.Task{Title: "call mom"})
+	_, _ = tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: time.Hour})
+	snap, err := features.GetLatestFeatureSnapshot(ctx, late.ID.String())
+	if err != nil || snap.ClusterVersion != 1 || snap.ModeCluster != ml.ModeProcrastinating {
+		t.Fatalf("expected an online assignment with version 1, got %+v (%v)", snap, err)
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+// recordingLLM remembers the input of its last call
+type recordingLLM struct {
+	last ml.HybridInput
+}
+
+func (l *recordingLLM) Generate(ctx context.Context, in ml.HybridInput) (string, error) {
+	l.last = in
+	return "noted", nil
+}
+
+func TestProcessMLJobHistory(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	llm := &recordingLLM{}
+	entries := services.NewEntryServiceWithHybrid(repos.Entries, repos.Scores, ml.NewHybridOrchestrator(llm), nil)
+
+	user, _ := repos.Users.CreateUser(ctx, "history@test.com", "hash")
+	other, _ := repos.Users.CreateUser(ctx, "history-other@test.com", "hash")
+	oldSession, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+	session, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+	strangerSession, _ := repos.Sessions.CreateSession(ctx, other.ID, nil)
+
+	_, _ = repos.Entries.CreateEntry(ctx, oldSession.ID, "last week: skipped the dentist", 6)
+	_, _ = repos.Entries.CreateEntry(ctx, strangerSession.ID, "someone else's secret", 9)
+	first, _ := repos.Entries.CreateEntry(ctx, session.ID, "ate cake for breakfast", 4)
+	_ = repos.Entries.UpdateRoast(ctx, first.ID, sql.NullString{String: "Bold.", Valid: true})
+	second, _ := repos.Entries.CreateEntry(ctx, session.ID, "ate cake for lunch", 5)
+	current, _ := repos.Entries.CreateEntry(ctx, session.ID, "ate cake for dinner", 7)
+	_, _ = repos.Entries.CreateEntry(ctx, session.ID, "written later", 1)
+
+	process := func(t *testing.T, key string) []string {
+		t.Helper()
+		// reset so the entry can be processed again
+		_ = repos.Entries.UpdateEntryStatus(ctx, current.ID, "pending")
+		if err := entries.ProcessMLJob(ctx, current.ID.String(), key); err != nil {
+			t.Fatalf("ProcessMLJob failed: %v", err)
+		}
+		return llm.last.History
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		if got := process(t, "job-off"); len(got) != 0 {
+			t.Fatalf("expected no history, got %q", got)
+		}
+	})
+
+	t.Run("same session, oldest first", func(t *testing.T) {
+		entries.SetHistory(services.HistoryConfig{Entries: 5})
+		got := process(t, "job-session")
+		if len(got) != 2 {
+			t.Fatalf("expected the two earlier entries of the session, got %q", got)
+		}
+		if !strings.Contains(got[0], "breakfast") || !strings.Contains(got[0], "Bold.") || !strings.Contains(got[1], second.EntryText) {
+			t.Fatalf("unexpected history %q", got)
+		}
+	})
+
+	t.Run("across sessions of the same user", func(t *testing.T) {
+		entries.SetHistory(services.HistoryConfig{Entries: 5, AcrossSessions: true})
+		got := process(t, "job-user")
+		if len(got) != 3 || !strings.Contains(got[0], "dentist") {
+			t.Fatalf("expected the user's older session too, got %q", got)
+		}
+		for _, h := range got {
+			if strings.Contains(h, "secret") {
+				t.Fatalf("history leaked another user's entry: %q", got)
+			}
+		}
+	})
+
+	t.Run("entry limit and char budget", func(t *testing.T) {
+		entries.SetHistory(services.HistoryConfig{Entries: 1, AcrossSessions: true})
+		if got := process(t, "job-limit"); len(got) != 1 || !strings.Contains(got[0], "lunch") {
+			t.Fatalf("expected only the newest entry, got %q", got)
+		}
+
+		entries.SetHistory(services.HistoryConfig{Entries: 5, MaxChars: 30})
+		got := process(t, "job-budget")
+		if len(got) != 1 || len(got[0]) > 30 {
+			t.Fatalf("expected history within 30 chars, got %q", got)
+		}
+	})
+}
+
+func TestProcessMLJobUsesUserTimezone(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	llm := &recordingLLM{}
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	entries := services.NewEntryServiceWithHybrid(repos.Entries, repos.Scores, ml.NewHybridOrchestrator(llm), prefs)
+	entries.SetSessions(repos.Sessions)
+
+	user, _ := repos.Users.CreateUser(ctx, "tz@test.com", "hash")
+	session, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+	if _, err := prefs.UpsertPreferences(ctx, user.ID.String(), nil, true, `{"timezone":"Asia/Tokyo"}`); err != nil {
+		t.Fatalf("UpsertPreferences failed: %v", err)
+	}
+	entry, _ := repos.Entries.CreateEntry(ctx, session.ID, "scrolled until dawn", 6)
+
+	if err := entries.ProcessMLJob(ctx, entry.ID.String(), "job-tz"); err != nil {
+		t.Fatalf("ProcessMLJob failed: %v", err)
+	}
+	if got := llm.last.At.Location().String(); got != "Asia/Tokyo" {
+		t.Fatalf("expected the entry time in the user's timezone, got %s", got)
+	}
+	if !llm.last.At.Equal(entry.CreatedAt) {
+		t.Fatalf("expected the same instant %v, got %v", entry.CreatedAt, llm.last.At)
+	}
+}
@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/queue"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+
+	"github.com/google/uuid"
+)
+
+// failingLLM errors until fail is cleared
+type failingLLM struct {
+	fail  bool
+	calls int
+}
+
+func (l *failingLLM) Generate(ctx context.Context, in ml.HybridInput) (string, error) {
+	l.calls++
+	if l.fail {
+		return "", errors.New("llm unavailable")
+	}
+	return "roasted", nil
+}
+
+func TestProcessMLJobIdempotent(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	llm := &failingLLM{}
+	entries := services.NewEntryServiceWithHybrid(repos.Entries, repos.Scores, ml.NewHybridOrchestrator(llm), nil)
+
+	user, _ := repos.Users.CreateUser(ctx, "idempotent@test.com", "hash")
+	sess, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+
+	newEntry := func(t *testing.T) string {
+		t.Helper()
+		e, err := repos.Entries.CreateEntry(ctx, sess.ID, "skipped leg day again", 4)
+		if err != nil {
+			t.Fatalf("CreateEntry failed: %v", err)
+		}
+		return e.ID.String()
+	}
+
+	t.Run("redelivery after completion is a no-op", func(t *testing.T) {
+		id := newEntry(t)
+		if err := entries.ProcessMLJob(ctx, id, "job-1"); err != nil {
+			t.Fatalf("first run failed: %v", err)
+		}
+		calls := llm.calls
+		for _, key := range []string{"job-1", "job-2"} {
+			if err := entries.ProcessMLJob(ctx, id, key); err != nil {
+				t.Fatalf("rerun with %s failed: %v", key, err)
+			}
+		}
+		if llm.calls != calls {
+			t.Fatalf("expected completed entry to be skipped, llm called %d more times", llm.calls-calls)
+		}
+
+		e, _ := entries.GetEntry(ctx, id)
+		if e.Status.String != "completed" || e.MlAttempts != 1 {
+			t.Fatalf("expected completed after one attempt, got %q after %d", e.Status.String, e.MlAttempts)
+		}
+		if scores := repos.Scores.ScoresForEntry(e.ID); len(scores) != 1 {
+			t.Fatalf("expected one score, got %d", len(scores))
+		}
+	})
+
+	t.Run("same job reclaims after a crash", func(t *testing.T) {
+		id := newEntry(t)
+		e, _ := entries.GetEntry(ctx, id)
+		// a worker claimed the entry and died before finishing
+		if ok, _ := repos.Entries.ClaimEntry(ctx, e.ID, "job-crash"); !ok {
+			t.Fatalf("expected pending entry to be claimable")
+		}
+
+		if err := entries.ProcessMLJob(ctx, id, "other-job"); !errors.Is(err, services.ErrEntryBusy) {
+			t.Fatalf("expected ErrEntryBusy for another job, got %v", err)
+		}
+		if err := entries.ProcessMLJob(ctx, id, "job-crash"); err != nil {
+			t.Fatalf("redelivery failed: %v", err)
+		}
+
+		e, _ = entries.GetEntry(ctx, id)
+		if e.Status.String != "completed" || e.MlAttempts != 2 {
+			t.Fatalf("expected completed after two attempts, got %q after %d", e.Status.String, e.MlAttempts)
+		}
+	})
+
+	t.Run("failed entry is retried", func(t *testing.T) {
+		id := newEntry(t)
+		llm.fail = true
+		if err := entries.ProcessMLJob(ctx, id, "job-retry"); err == nil {
+			t.Fatalf("expected llm failure")
+		}
+		e, _ := entries.GetEntry(ctx, id)
+		if e.Status.String != "failed" {
+			t.Fatalf("expected failed, got %q", e.Status.String)
+		}
+
+		llm.fail = false
+		if err := entries.ProcessMLJob(ctx, id, "job-retry"); err != nil {
+			t.Fatalf("retry failed: %v", err)
+		}
+		e, _ = entries.GetEntry(ctx, id)
+		if e.Status.String != "completed" {
+			t.Fatalf("expected completed, got %q", e.Status.String)
+		}
+		if scores := repos.Scores.ScoresForEntry(e.ID); len(scores) != 1 {
+			t.Fatalf("expected one score, got %d", len(scores))
+		}
+	})
+}
+
+func TestProcessMLJobDegradedMode(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	orchestrator := ml.NewHybridOrchestratorWithFallback(&failingLLM{fail: true}, ml.DefaultTemplates(), nil)
+	entries := services.NewEntryServiceWithHybrid(repos.Entries, repos.Scores, orchestrator, nil)
+
+	user, _ := repos.Users.CreateUser(ctx, "degraded@test.com", "hash")
+	sess, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+	e, _ := repos.Entries.CreateEntry(ctx, sess.ID, "skipped leg day again", 4)
+
+	if err := entries.ProcessMLJob(ctx, e.ID.String(), "job-degraded"); err != nil {
+		t.Fatalf("expected a template roast instead of a failure, got %v", err)
+	}
+
+	e, _ = repos.Entries.GetEntry(ctx, e.ID)
+	if e.Status.String != "completed" || e.RoastText.String == "" {
+		t.Fatalf("expected completed entry with roast, got %q %q", e.Status.String, e.RoastText.String)
+	}
+	scores := repos.Scores.ScoresForEntry(e.ID)
+	if len(scores) != 1 {
+		t.Fatalf("expected one score, got %d", len(scores))
+	}
+	var meta struct {
+		ModelVersion string   `json:"model_version"`
+		Source       string   `json:"source"`
+		Tags         []string `json:"tags"`
+	}
+	if err := json.Unmarshal(scores[0].Meta.RawMessage, &meta); err != nil || meta.Source != ml.SourceTemplate {
+		t.Fatalf("expected source=template in score meta, got %s", scores[0].Meta.RawMessage)
+	}
+	if meta.ModelVersion != ml.DefaultScoringModel().Version {
+		t.Fatalf("expected the scoring model version in score meta, got %s", scores[0].Meta.RawMessage)
+	}
+}
+
+// eagerWorker finishes every job the moment it is published
+type eagerWorker struct {
+	entries *fakes.EntriesRepo
+}
+
+func (w *eagerWorker) Publish(ctx context.Context, job queue.EntryMLJob) error {
+	return w.entries.UpdateEntryStatus(ctx, uuid.MustParse(job.EntryID), "completed")
+}
+
+func TestCreateEntryMarksPendingBeforeEnqueue(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	entries := services.NewEntryServiceWithQueue(repos.Entries, repos.Scores, nil, nil, queue.NewProducer(&eagerWorker{entries: repos.Entries}))
+
+	user, _ := repos.Users.CreateUser(ctx, "pending@test.com", "hash")
+	session, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+	e, err := entries.CreateEntry(ctx, session.ID.String(), "ate the last slice", 4)
+	if err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+	if got, _ := repos.Entries.GetEntry(ctx, e.ID); got.Status.String != "completed" {
+		t.Fatalf("expected a fast worker's status to stick, got %q", got.Status.String)
+	}
+}
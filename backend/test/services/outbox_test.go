@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlc "guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/outbox"
+	"guiltmachine/internal/queue"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+
+	"github.com/google/uuid"
+)
+
+// flakyPublisher fails while down is set, otherwise forwards to the queue
+type flakyPublisher struct {
+	down    bool
+	backend *queue.MemoryBackend
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, job queue.EntryMLJob) error {
+	if p.down {
+		return errors.New("queue unavailable")
+	}
+	return p.backend.Publish(ctx, job)
+}
+
+func TestEntryOutbox(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	entries := services.NewEntryServiceWithOutbox(repos.Entries, repos.Scores, nil, nil)
+
+	user, _ := repos.Users.CreateUser(ctx, "outbox@test.com", "hash")
+	sess, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+
+	backend := queue.NewMemoryBackend(time.Minute)
+	publisher := &flakyPublisher{down: true, backend: backend}
+	relay := outbox.NewRelay(repos.Outbox, publisher, time.Second)
+
+	e, err := entries.CreateEntry(ctx, sess.ID.String(), "ate the last slice", 5)
+	if err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+	if e.Status.String != "pending" {
+		t.Fatalf("expected pending entry, got %q", e.Status.String)
+	}
+
+	pending := repos.Outbox.Pending()
+	if len(pending) != 1 || pending[0].Topic != outbox.TopicEntryMLJob {
+		t.Fatalf("expected one outbox message, got %+v", pending)
+	}
+
+	t.Run("queue outage keeps the message", func(t *testing.T) {
+		sent, err := relay.RelayOnce(ctx)
+		if err != nil || sent != 0 {
+			t.Fatalf("expected nothing sent, got %d (%v)", sent, err)
+		}
+		pending := repos.Outbox.Pending()
+		if len(pending) != 1 || pending[0].Attempts != 1 || !pending[0].LastError.Valid {
+			t.Fatalf("expected failed attempt to be recorded, got %+v", pending)
+		}
+		if backend.Len() != 0 {
+			t.Fatal("expected nothing in the queue")
+		}
+	})
+
+	t.Run("recovery publishes the job", func(t *testing.T) {
+		publisher.down = false
+		sent, err := relay.RelayOnce(ctx)
+		if err != nil || sent != 1 {
+			t.Fatalf("expected one message sent, got %d (%v)", sent, err)
+		}
+		if len(repos.Outbox.Pending()) != 0 {
+			t.Fatal("expected outbox to be drained")
+		}
+
+		deliveries, err := backend.Source("test").PollN(ctx, 1)
+		if err != nil || len(deliveries) != 1 {
+			t.Fatalf("expected queued job, got %v (%v)", deliveries, err)
+		}
+		if deliveries[0].Job.EntryID != e.ID.String() || deliveries[0].Job.Text != "ate the last slice" {
+			t.Fatalf("unexpected job %+v", deliveries[0].Job)
+		}
+	})
+}
+
+func TestOutboxSetsAsideUndeliverableMessages(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	entries := services.NewEntryServiceWithOutbox(repos.Entries, repos.Scores, nil, nil)
+
+	user, _ := repos.Users.CreateUser(ctx, "outbox-poison@test.com", "hash")
+	sess, _ := repos.Sessions.CreateSession(ctx, user.ID, nil)
+
+	// written before the good entry, so they come first in the batch
+	unknown, _ := repos.Entries.CreateEntryWithOutbox(ctx, sess.ID, "unknown topic", 1, "entry.unknown", func(sqlc.GuiltEntry) ([]byte, error) {
+		return []byte(`{}`), nil
+	})
+	undecodable, _ := repos.Entries.CreateEntryWithOutbox(ctx, sess.ID, "bad payload", 1, outbox.TopicEntryMLJob, func(sqlc.GuiltEntry) ([]byte, error) {
+		return []byte(`{"entry_id":`), nil
+	})
+	e, err := entries.CreateEntry(ctx, sess.ID.String(), "ate the last slice", 5)
+	if err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+
+	backend := queue.NewMemoryBackend(time.Minute)
+	relay := outbox.NewRelay(repos.Outbox, backend, time.Second)
+	sent, err := relay.RelayOnce(ctx)
+	if err != nil || sent != 1 {
+		t.Fatalf("expected the good message sent past the bad ones, got %d (%v)", sent, err)
+	}
+	if pending := repos.Outbox.Pending(); len(pending) != 0 {
+		t.Fatalf("expected nothing left to send, got %+v", pending)
+	}
+	dead := repos.Outbox.Dead()
+	if len(dead) != 2 || dead[0].Attempts != 1 || !dead[0].LastError.Valid {
+		t.Fatalf("expected both bad messages set aside with their error, got %+v", dead)
+	}
+	// no job will ever finish their entries
+	for _, id := range []uuid.UUID{unknown.ID, undecodable.ID} {
+		if got, _ := repos.Entries.GetEntry(ctx, id); got.Status.String != "failed" {
+			t.Fatalf("expected the entry of a dead message failed, got %q", got.Status.String)
+		}
+	}
+	if got, _ := repos.Entries.GetEntry(ctx, e.ID); got.Status.String != "pending" {
+		t.Fatalf("expected the good entry left pending for its job, got %q", got.Status.String)
+	}
+
+	deliveries, err := backend.Source("test").PollN(ctx, 1)
+	if err != nil || len(deliveries) != 1 || deliveries[0].Job.EntryID != e.ID.String() {
+		t.Fatalf("expected the good entry's job queued, got %v (%v)", deliveries, err)
+	}
+
+	// dead messages are not handed out again
+	if sent, err := relay.RelayOnce(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected nothing more to send, got %d (%v)", sent, err)
+	}
+	if dead := repos.Outbox.Dead(); dead[0].Attempts != 1 {
+		t.Fatalf("expected no further attempts on a dead message, got %d", dead[0].Attempts)
+	}
+}
@@ -0,0 +1,353 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/cache"
+	cacheDomain "guiltmachine/internal/cache/domain"
+	"guiltmachine/internal/cache/memory"
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/repository"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+
+	"github.com/google/uuid"
+)
+
+// countingCache counts Gets so tests can tell which tier answered
+type countingCache struct {
+	cache.Cache
+	gets atomic.Int32
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.gets.Add(1)
+	return c.Cache.Get(ctx, key)
+}
+
+// countingPrefsRepo counts database reads and can hold them until released
+type countingPrefsRepo struct {
+	repository.PreferencesRepository
+	reads   atomic.Int32
+	release chan struct{}
+}
+
+func (r *countingPrefsRepo) GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) (sqlc.UserPreference, error) {
+	r.reads.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+	return r.PreferencesRepository.GetPreferencesByUserID(ctx, userID)
+}
+
+func TestGetPreferencesReadsThroughCache(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	repo := &countingPrefsRepo{PreferencesRepository: repos.Preferences}
+	now := time.Now()
+	shared := memory.NewMemoryCacheWithClock(func() time.Time { return now })
+	prefs := services.NewPreferencesService(repo, cacheDomain.NewPreferencesCache(shared))
+	user, _ := repos.Users.CreateUser(ctx, "prefs-cache@test.com", "hash")
+	stranger, _ := repos.Users.CreateUser(ctx, "prefs-cache-none@test.com", "hash")
+
+	if _, err := prefs.UpsertPreferences(ctx, user.ID.String(), nil, true, `{"persona":"chill"}`); err != nil {
+		t.Fatalf("UpsertPreferences failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		pref, err := prefs.GetPreferences(ctx, user.ID.String())
+		if err != nil || pref.Persona != "chill" || pref.UserID != user.ID {
+			t.Fatalf("unexpected preferences %+v (%v)", pref, err)
+		}
+	}
+	if n := repo.reads.Load(); n != 1 {
+		t.Fatalf("expected one database read after the upsert, got %d", n)
+	}
+
+	// users without preferences are cached as such
+	for i := 0; i < 3; i++ {
+		if _, err := prefs.GetPreferences(ctx, stranger.ID.String()); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected sql.ErrNoRows, got %v", err)
+		}
+	}
+	if n := repo.reads.Load(); n != 2 {
+		t.Fatalf("expected one more database read for the missing preferences, got %d", n)
+	}
+
+	// cached records expire
+	now = now.Add(13 * time.Hour)
+	if pref, err := prefs.GetPreferences(ctx, user.ID.String()); err != nil || pref.Persona != "chill" {
+		t.Fatalf("unexpected preferences %+v (%v)", pref, err)
+	}
+	if n := repo.reads.Load(); n != 3 {
+		t.Fatalf("expected the expired record to be read again, got %d database reads", n)
+	}
+}
+
+func TestGetPreferencesCollapsesConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	repo := &countingPrefsRepo{PreferencesRepository: repos.Preferences, release: make(chan struct{})}
+	prefs := services.NewPreferencesService(repo, cacheDomain.NewPreferencesCache(memory.NewMemoryCache()))
+	user, _ := repos.Users.CreateUser(ctx, "prefs-stampede@test.com", "hash")
+	_, _ = repos.Preferences.UpdatePreferences(ctx, user.ID, func(p *sqlc.UserPreference) error {
+		p.Persona = "coach"
+		return nil
+	})
+
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pref, err := prefs.GetPreferences(ctx, user.ID.String())
+			if err == nil && pref.Persona != "coach" {
+				err = errors.New("wrong persona " + pref.Persona)
+			}
+			errs <- err
+		}()
+	}
+	// let every caller reach the cache miss before the read completes
+	deadline := time.Now().Add(5 * time.Second)
+	for repo.reads.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetPreferences failed: %v", err)
+		}
+	}
+	if n := repo.reads.Load(); n != 1 {
+		t.Fatalf("expected concurrent misses to share one database read, got %d", n)
+	}
+}
+
+// staleReadPrefsRepo returns what it read first only after release is
+// closed, so a write can land in between
+type staleReadPrefsRepo struct {
+	repository.PreferencesRepository
+	once    sync.Once
+	read    chan struct{}
+	release chan struct{}
+}
+
+func (r *staleReadPrefsRepo) GetPreferencesByUserID(ctx context.Context, userID uuid.UUID) (sqlc.UserPreference, error) {
+	pref, err := r.PreferencesRepository.GetPreferencesByUserID(ctx, userID)
+	r.once.Do(func() {
+		close(r.read)
+		<-r.release
+	})
+	return pref, err
+}
+
+func TestGetPreferencesFillDoesNotReplaceNewerWrite(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	repo := &staleReadPrefsRepo{PreferencesRepository: repos.Preferences, read: make(chan struct{}), release: make(chan struct{})}
+	prefs := services.NewPreferencesService(repo, cacheDomain.NewPreferencesCache(memory.NewMemoryCache()))
+	user, _ := repos.Users.CreateUser(ctx, "prefs-race@test.com", "hash")
+	uid := user.ID.String()
+	_, _ = repos.Preferences.UpdatePreferences(ctx, user.ID, func(p *sqlc.UserPreference) error {
+		p.Persona = "chill"
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := prefs.GetPreferences(ctx, uid)
+		done <- err
+	}()
+	<-repo.read
+	if _, err := prefs.UpdatePreferences(ctx, uid, sqlc.UserPreference{Persona: "coach"}, []string{"persona"}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	close(repo.release)
+	if err := <-done; err != nil {
+		t.Fatalf("GetPreferences failed: %v", err)
+	}
+
+	pref, err := prefs.GetPreferences(ctx, uid)
+	if err != nil || pref.Persona != "coach" {
+		t.Fatalf("expected the stale read to leave the written record cached, got %+v (%v)", pref, err)
+	}
+}
+
+func TestPreferencesWritesSeenAcrossProcesses(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	shared := memory.NewMemoryCache()
+
+	// api and worker only share the redis stand-in
+	api := services.NewPreferencesService(repos.Preferences, cacheDomain.NewPreferencesCache(shared))
+	worker := services.NewPreferencesService(repos.Preferences, cacheDomain.NewPreferencesCache(shared))
+	user, _ := repos.Users.CreateUser(ctx, "prefs-shared@test.com", "hash")
+	uid := user.ID.String()
+
+	if rec, _ := worker.Personalization(ctx, uid); rec.Persona != "roast" {
+		t.Fatalf("expected default persona, got %q", rec.Persona)
+	}
+	if _, err := api.UpdatePreferences(ctx, uid, sqlc.UserPreference{Persona: "coach"}, []string{"persona"}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	rec, err := worker.Personalization(ctx, uid)
+	if err != nil || rec.Persona != "coach" {
+		t.Fatalf("expected the worker to see the new persona, got %+v (%v)", rec, err)
+	}
+}
+
+func TestPreferencesInvalidationAcrossProcesses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repos := fakes.NewRepos()
+	shared := memory.NewMemoryCache()
+	invalidations := cache.NewMemoryInvalidations()
+
+	// api and worker share the redis stand-in and the pub/sub channel
+	newProcess := func() *services.PreferencesService {
+		prefsCache := cacheDomain.NewPreferencesCache(shared)
+		prefsCache.SetInvalidations(invalidations)
+		prefs := services.NewPreferencesService(repos.Preferences, prefsCache)
+		go prefs.ListenInvalidations(ctx)
+		return prefs
+	}
+	api, worker := newProcess(), newProcess()
+	user, _ := repos.Users.CreateUser(ctx, "prefs-pubsub@test.com", "hash")
+	uid := user.ID.String()
+
+	keys, err := invalidations.SubscribeInvalidations(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeInvalidations failed: %v", err)
+	}
+	if rec, _ := worker.Personalization(ctx, uid); rec.Persona != "roast" {
+		t.Fatalf("expected default persona, got %q", rec.Persona)
+	}
+	if _, err := api.UpdatePreferences(ctx, uid, sqlc.UserPreference{Persona: "coach"}, []string{"persona"}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+
+	select {
+	case key := <-keys:
+		if key != "PREF:"+uid {
+			t.Fatalf("expected the user's preferences key, got %q", key)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the change to be announced")
+	}
+	if rec, err := worker.Personalization(ctx, uid); err != nil || rec.Persona != "coach" {
+		t.Fatalf("expected the worker to see the new persona, got %+v (%v)", rec, err)
+	}
+}
+
+func TestPreferencesStaleFillDroppedAcrossProcesses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repos := fakes.NewRepos()
+	shared := memory.NewMemoryCache()
+	invalidations := cache.NewMemoryInvalidations()
+	newProcess := func(repo repository.PreferencesRepository) *services.PreferencesService {
+		prefsCache := cacheDomain.NewPreferencesCache(shared)
+		prefsCache.SetInvalidations(invalidations)
+		prefs := services.NewPreferencesService(repo, prefsCache)
+		go prefs.ListenInvalidations(ctx)
+		return prefs
+	}
+	// the worker reads the old persona, then the api changes it before the
+	// worker gets to fill the shared cache
+	slow := &staleReadPrefsRepo{PreferencesRepository: repos.Preferences, read: make(chan struct{}), release: make(chan struct{})}
+	api, worker := newProcess(repos.Preferences), newProcess(slow)
+	user, _ := repos.Users.CreateUser(ctx, "prefs-stale-fill@test.com", "hash")
+	uid := user.ID.String()
+	_, _ = repos.Preferences.UpdatePreferences(ctx, user.ID, func(p *sqlc.UserPreference) error {
+		p.Persona = "chill"
+		return nil
+	})
+	time.Sleep(10 * time.Millisecond) // subscriptions are set up asynchronously
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := worker.GetPreferences(ctx, uid)
+		done <- err
+	}()
+	<-slow.read
+	if _, err := api.UpdatePreferences(ctx, uid, sqlc.UserPreference{Persona: "coach"}, []string{"persona"}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	close(slow.release)
+	if err := <-done; err != nil {
+		t.Fatalf("GetPreferences failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		pref, err := api.GetPreferences(ctx, uid)
+		if err == nil && pref.Persona == "coach" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the stale fill to be dropped, got %+v (%v)", pref, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPreferencesLocalCopiesEvictedAcrossProcesses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repos := fakes.NewRepos()
+	shared := &countingCache{Cache: memory.NewMemoryCache()}
+	invalidations := cache.NewMemoryInvalidations()
+
+	// api and worker share the redis stand-in but keep their own local copies
+	newProcess := func() *services.PreferencesService {
+		tiers := cache.NewTieredCache(memory.NewMemoryCache(), shared, time.Hour)
+		go tiers.ListenInvalidations(ctx, invalidations)
+		prefsCache := cacheDomain.NewPreferencesCache(tiers)
+		prefsCache.SetInvalidations(invalidations)
+		return services.NewPreferencesService(repos.Preferences, prefsCache)
+	}
+	api, worker := newProcess(), newProcess()
+	user, _ := repos.Users.CreateUser(ctx, "prefs-local@test.com", "hash")
+	uid := user.ID.String()
+	time.Sleep(10 * time.Millisecond) // subscriptions are set up asynchronously
+
+	if rec, _ := worker.Personalization(ctx, uid); rec.Persona != "roast" {
+		t.Fatalf("expected default persona, got %q", rec.Persona)
+	}
+	if _, err := api.UpdatePreferences(ctx, uid, sqlc.UserPreference{Persona: "coach"}, []string{"persona"}); err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rec, err := worker.Personalization(ctx, uid)
+		if err != nil {
+			t.Fatalf("Personalization failed: %v", err)
+		}
+		if rec.Persona == "coach" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("worker kept its local copy after the api changed preferences")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// the local copy serves reads without touching the shared cache
+	gets := shared.gets.Load()
+	_, _ = worker.Personalization(ctx, uid)
+	if shared.gets.Load() != gets {
+		t.Fatalf("expected the worker to answer from its local copy")
+	}
+}
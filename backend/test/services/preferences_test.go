@@ -0,0 +1,173 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	cacheDomain "guiltmachine/internal/cache/domain"
+	"guiltmachine/internal/cache/memory"
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+func TestUpsertPreferencesMovesTypedMetadata(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	user, _ := repos.Users.CreateUser(ctx, "prefs-legacy@test.com", "hash")
+
+	pref, err := prefs.UpsertPreferences(ctx, user.ID.String(), nil, true, `{"persona":"coach","humor_intensity":8,"timezone":"Europe/Berlin","color":"teal"}`)
+	if err != nil {
+		t.Fatalf("UpsertPreferences failed: %v", err)
+	}
+	if pref.Persona != "coach" || pref.HumorIntensity != 8 || pref.Timezone != "Europe/Berlin" || pref.Language != "en" {
+		t.Fatalf("expected typed fields from metadata, got %+v", pref)
+	}
+	if string(pref.Metadata.RawMessage) != `{"color":"teal"}` {
+		t.Fatalf("expected only extras to stay in metadata, got %s", pref.Metadata.RawMessage)
+	}
+
+	// a legacy upsert without typed keys keeps the typed fields
+	theme := "dark"
+	pref, err = prefs.UpsertPreferences(ctx, user.ID.String(), &theme, false, "")
+	if err != nil {
+		t.Fatalf("UpsertPreferences failed: %v", err)
+	}
+	if pref.Persona != "coach" || pref.HumorIntensity != 8 || pref.Metadata.Valid || pref.Theme.String != "dark" {
+		t.Fatalf("expected typed fields to survive, got %+v", pref)
+	}
+}
+
+func TestUpsertPreferencesRejectsInvalidMetadata(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	user, _ := repos.Users.CreateUser(ctx, "prefs-invalid@test.com", "hash")
+
+	for _, metadata := range []string{
+		`{"persona":`,
+		`{"persona":"pirate"}`,
+		`{"persona":3}`,
+		`{"humor_intensity":11}`,
+		`{"humor_intensity":2.5}`,
+		`{"timezone":"Mars/Olympus"}`,
+	} {
+		_, err := prefs.UpsertPreferences(ctx, user.ID.String(), nil, true, metadata)
+		if !errors.Is(err, services.ErrInvalidPreferences) {
+			t.Errorf("%s: expected ErrInvalidPreferences, got %v", metadata, err)
+		}
+	}
+	if _, err := repos.Preferences.GetPreferencesByUserID(ctx, user.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected rejected writes to save nothing, got %v", err)
+	}
+}
+
+func TestUpdatePreferencesFieldMask(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	user, _ := repos.Users.CreateUser(ctx, "prefs-mask@test.com", "hash")
+	uid := user.ID.String()
+
+	_, err := prefs.UpdatePreferences(ctx, uid, sqlc.UserPreference{
+		Persona:          "chill",
+		HumorIntensity:   9,
+		WorkStartMinute:  sql.NullInt32{Int32: 9 * 60, Valid: true},
+		WorkEndMinute:    sql.NullInt32{Int32: 17 * 60, Valid: true},
+		QuietStartMinute: sql.NullInt32{Int32: 22 * 60, Valid: true},
+		QuietEndMinute:   sql.NullInt32{Int32: 7 * 60, Valid: true},
+		NudgeChannels:    []string{services.NudgePush, services.NudgeEmail, services.NudgePush},
+	}, []string{"persona", "work_hours", "quiet_hours", "nudge_channels"})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+
+	pref, err := prefs.GetPreferences(ctx, uid)
+	if err != nil {
+		t.Fatalf("GetPreferences failed: %v", err)
+	}
+	if pref.Persona != "chill" || pref.HumorIntensity != 3 {
+		t.Fatalf("expected only masked fields to change, got persona %q intensity %d", pref.Persona, pref.HumorIntensity)
+	}
+	if pref.QuietStartMinute.Int32 != 22*60 || pref.QuietEndMinute.Int32 != 7*60 {
+		t.Fatalf("expected quiet hours across midnight, got %+v", pref)
+	}
+	if len(pref.NudgeChannels) != 2 {
+		t.Fatalf("expected duplicate channels dropped, got %v", pref.NudgeChannels)
+	}
+
+	// a masked field left empty goes back to its default
+	pref, err = prefs.UpdatePreferences(ctx, uid, sqlc.UserPreference{}, []string{"persona", "work_hours"})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	if pref.Persona != "roast" || pref.WorkStartMinute.Valid || !pref.QuietStartMinute.Valid {
+		t.Fatalf("expected persona and work hours reset only, got %+v", pref)
+	}
+}
+
+func TestUpdatePreferencesValidation(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	user, _ := repos.Users.CreateUser(ctx, "prefs-validate@test.com", "hash")
+
+	cases := []struct {
+		name  string
+		prefs sqlc.UserPreference
+		paths []string
+	}{
+		{"empty mask", sqlc.UserPreference{}, nil},
+		{"unknown path", sqlc.UserPreference{}, []string{"metadata"}},
+		{"intensity", sqlc.UserPreference{HumorIntensity: -1}, []string{"humor_intensity"}},
+		{"timezone", sqlc.UserPreference{Timezone: "Local"}, []string{"timezone"}},
+		{"language", sqlc.UserPreference{Language: "English"}, []string{"language"}},
+		{"channel", sqlc.UserPreference{NudgeChannels: []string{"pigeon"}}, []string{"nudge_channels"}},
+		{"half range", sqlc.UserPreference{WorkStartMinute: sql.NullInt32{Int32: 60, Valid: true}}, []string{"work_hours"}},
+		{"empty range", sqlc.UserPreference{
+			QuietStartMinute: sql.NullInt32{Int32: 60, Valid: true},
+			QuietEndMinute:   sql.NullInt32{Int32: 60, Valid: true},
+		}, []string{"quiet_hours"}},
+		{"range past midnight", sqlc.UserPreference{
+			WorkStartMinute: sql.NullInt32{Int32: 60, Valid: true},
+			WorkEndMinute:   sql.NullInt32{Int32: 24 * 60, Valid: true},
+		}, []string{"work_hours"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := prefs.UpdatePreferences(ctx, user.ID.String(), tc.prefs, tc.paths)
+			if !errors.Is(err, services.ErrInvalidPreferences) {
+				t.Fatalf("expected ErrInvalidPreferences, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPersonalizationUsesTypedPreferences(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	prefs := services.NewPreferencesService(repos.Preferences, cacheDomain.NewPreferencesCache(memory.NewMemoryCache()))
+	user, _ := repos.Users.CreateUser(ctx, "prefs-personal@test.com", "hash")
+	uid := user.ID.String()
+
+	rec, err := prefs.Personalization(ctx, uid)
+	if err != nil || rec.Found || rec.Persona != "roast" || rec.HumorIntensity != 3 {
+		t.Fatalf("expected defaults without preferences, got %+v (%v)", rec, err)
+	}
+
+	_, err = prefs.UpdatePreferences(ctx, uid, sqlc.UserPreference{Persona: "coach", HumorIntensity: 7, Timezone: "Asia/Tokyo"},
+		[]string{"persona", "humor_intensity", "timezone"})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	rec, err = prefs.Personalization(ctx, uid)
+	if err != nil {
+		t.Fatalf("Personalization failed: %v", err)
+	}
+	if rec.Persona != "coach" || rec.HumorIntensity != 7 || rec.Timezone != "Asia/Tokyo" {
+		t.Fatalf("expected the update to evict cached defaults, got %+v", rec)
+	}
+}
@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/auth"
+	cacheDomain "guiltmachine/internal/cache/domain"
+	"guiltmachine/internal/cache/memory"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+func newSessionService(t *testing.T) (*services.SessionService, *cacheDomain.SessionCache, *fakes.Repos) {
+	t.Helper()
+	repos := fakes.NewRepos()
+	cache := cacheDomain.NewSessionCache(memory.NewMemoryCache())
+	jwt := auth.NewJWTManagerWithRefresh("session-test-secret", 15*time.Minute, time.Hour)
+	return services.NewSessionServiceWithJWT(repos.Sessions, cache, jwt), cache, repos
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	sessions, cache, repos := newSessionService(t)
+	user, _ := repos.Users.CreateUser(ctx, "refresh@test.com", "hash")
+
+	created, err := sessions.CreateSessionWithJWT(ctx, user.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("CreateSessionWithJWT failed: %v", err)
+	}
+	sid := created.Session.ID.String()
+
+	pair, err := sessions.RefreshToken(ctx, created.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if pair.AccessToken == "" || pair.RefreshToken == "" || pair.RefreshToken == created.RefreshToken {
+		t.Fatalf("expected a new token pair, got %+v", pair)
+	}
+
+	// the rotated refresh token works once more
+	next, err := sessions.RefreshToken(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken with the rotated token failed: %v", err)
+	}
+
+	t.Run("replay revokes the session", func(t *testing.T) {
+		if _, err := sessions.RefreshToken(ctx, created.RefreshToken); !errors.Is(err, auth.ErrRevokedToken) {
+			t.Fatalf("expected ErrRevokedToken for a replayed token, got %v", err)
+		}
+		if revoked, _ := cache.IsSessionRevoked(ctx, sid); !revoked {
+			t.Fatal("expected the replay to revoke the session")
+		}
+		// the legitimate holder's newest token is dead as well
+		if _, err := sessions.RefreshToken(ctx, next.RefreshToken); !errors.Is(err, auth.ErrRevokedToken) {
+			t.Fatalf("expected ErrRevokedToken after revocation, got %v", err)
+		}
+	})
+
+	t.Run("access tokens are rejected", func(t *testing.T) {
+		if _, err := sessions.RefreshToken(ctx, next.AccessToken); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken for an access token, got %v", err)
+		}
+	})
+}
+
+func TestEndSessionRevokesTokens(t *testing.T) {
+	ctx := context.Background()
+	sessions, cache, repos := newSessionService(t)
+	user, _ := repos.Users.CreateUser(ctx, "end-session@test.com", "hash")
+
+	created, err := sessions.CreateSessionWithJWT(ctx, user.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("CreateSessionWithJWT failed: %v", err)
+	}
+	sid := created.Session.ID.String()
+
+	ended, err := sessions.EndSession(ctx, sid)
+	if err != nil || !ended.EndTime.Valid {
+		t.Fatalf("expected an ended session, got %+v (%v)", ended, err)
+	}
+	if revoked, err := cache.IsSessionRevoked(ctx, sid); err != nil || !revoked {
+		t.Fatalf("expected the session revoked, got %v (%v)", revoked, err)
+	}
+	if _, err := sessions.RefreshToken(ctx, created.RefreshToken); !errors.Is(err, auth.ErrRevokedToken) {
+		t.Fatalf("expected ErrRevokedToken after EndSession, got %v", err)
+	}
+}
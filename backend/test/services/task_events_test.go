@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+func TestTaskMutationsRecordEvents(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	user, _ := repos.Users.CreateUser(ctx, "task-events@test.com", "hash")
+	uid := user.ID.String()
+
+	due := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	task, err := tasks.CreateTask(ctx, uid, sqlc.Task{
+		Title:    "renew passport",
+		Category: sql.NullString{String: "admin", Valid: true},
+		DueAt:    sql.NullTime{Time: due, Valid: true},
+		Priority: 2,
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	tid := task.ID.String()
+
+	// a masked update that changes nothing is not an event
+	if _, err := tasks.UpdateTask(ctx, tid, sqlc.Task{Priority: 2}, []string{"priority"}); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	newDue := due.Add(24 * time.Hour)
+	_, err = tasks.UpdateTask(ctx, tid, sqlc.Task{DueAt: sql.NullTime{Time: newDue, Valid: true}}, []string{"due_at", "category"})
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if _, err := tasks.CompleteTask(ctx, tid); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if _, err := tasks.CompleteTask(ctx, tid); err != nil {
+		t.Fatalf("CompleteTask failed: %v", err)
+	}
+	if err := tasks.DeleteTask(ctx, tid); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+
+	events, err := tasks.ListTaskEvents(ctx, uid, nil, time.Time{}, time.Time{}, 0, 0)
+	if err != nil {
+		t.Fatalf("ListTaskEvents failed: %v", err)
+	}
+	want := []string{services.TaskEventCreate, services.TaskEventModify, services.TaskEventComplete, services.TaskEventDelete}
+	if len(events) != len(want) {
+		t.Fatalf("expected %v, got %d events", want, len(events))
+	}
+	for i, e := range events {
+		if e.EventType != want[i] || e.TaskID != task.ID || e.UserID != user.ID {
+			t.Fatalf("event %d: expected %s of the task, got %+v", i, want[i], e)
+		}
+	}
+
+	var created services.TaskCreatedPayload
+	_ = json.Unmarshal(events[0].EventPayload, &created)
+	if created.Title != "renew passport" || created.Category != "admin" || created.DueAt == nil || !created.DueAt.Equal(due) {
+		t.Fatalf("unexpected create payload %s", events[0].EventPayload)
+	}
+
+	var modified struct {
+		Changes map[string]struct {
+			From any `json:"from"`
+			To   any `json:"to"`
+		} `json:"changes"`
+	}
+	_ = json.Unmarshal(events[1].EventPayload, &modified)
+	if len(modified.Changes) != 2 {
+		t.Fatalf("expected due_at and category changes, got %s", events[1].EventPayload)
+	}
+	if c := modified.Changes["category"]; c.From != "admin" || c.To != nil {
+		t.Fatalf("expected the category to be cleared, got %s", events[1].EventPayload)
+	}
+	if c := modified.Changes["due_at"]; c.To != newDue.UTC().Format(time.RFC3339) {
+		t.Fatalf("expected the new due date, got %s", events[1].EventPayload)
+	}
+
+	var completed services.TaskCompletedPayload
+	_ = json.Unmarshal(events[2].EventPayload, &completed)
+	if completed.PreviousStatus != services.TaskPending || completed.OverdueMinutes == nil || *completed.OverdueMinutes >= 0 {
+		t.Fatalf("expected a pending task finished before its moved deadline, got %s", events[2].EventPayload)
+	}
+}
+
+func TestListTaskEventsFilters(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	alice, _ := repos.Users.CreateUser(ctx, "task-events-alice@test.com", "hash")
+	bob, _ := repos.Users.CreateUser(ctx, "task-events-bob@test.com", "hash")
+
+	for _, title := range []string{"one", "two", "three"} {
+		task, _ := tasks.CreateTask(ctx, alice.ID.String(), sqlc.Task{Title: title})
+		_, _ = tasks.CompleteTask(ctx, task.ID.String())
+	}
+	_, _ = tasks.CreateTask(ctx, bob.ID.String(), sqlc.Task{Title: "not alice's"})
+
+	completes, err := tasks.ListTaskEvents(ctx, alice.ID.String(), []string{services.TaskEventComplete}, time.Time{}, time.Time{}, 2, 1)
+	if err != nil || len(completes) != 2 {
+		t.Fatalf("expected the last two of three complete events, got %d (%v)", len(completes), err)
+	}
+
+	all, _ := tasks.ListTaskEvents(ctx, alice.ID.String(), nil, time.Time{}, time.Time{}, 0, 0)
+	since := all[2].CreatedAt
+	later, _ := tasks.ListTaskEvents(ctx, alice.ID.String(), nil, since, time.Time{}, 0, 0)
+	earlier, _ := tasks.ListTaskEvents(ctx, alice.ID.String(), nil, time.Time{}, since, 0, 0)
+	if len(all) != 6 || len(later)+len(earlier) != 6 || len(later) == 0 {
+		t.Fatalf("expected since and until to split six events, got %d, %d and %d", len(all), len(earlier), len(later))
+	}
+
+	now := time.Now()
+	for _, bad := range []func() error{
+		func() error {
+			_, err := tasks.ListTaskEvents(ctx, alice.ID.String(), []string{"procrastinate"}, time.Time{}, time.Time{}, 0, 0)
+			return err
+		},
+		func() error {
+			_, err := tasks.ListTaskEvents(ctx, alice.ID.String(), nil, now, now.Add(-time.Hour), 0, 0)
+			return err
+		},
+		func() error {
+			_, err := tasks.ListTaskEvents(ctx, "nope", nil, time.Time{}, time.Time{}, 0, 0)
+			return err
+		},
+	} {
+		if err := bad(); !errors.Is(err, services.ErrInvalidTask) {
+			t.Errorf("expected ErrInvalidTask, got %v", err)
+		}
+	}
+}
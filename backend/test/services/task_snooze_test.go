@@ -0,0 +1,209 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/ml"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+func TestSnoozeTask(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	user, _ := repos.Users.CreateUser(ctx, "snooze@test.com", "hash")
+	uid := user.ID.String()
+
+	due := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	task, _ := tasks.CreateTask(ctx, uid, sqlc.Task{Title: "call the bank", DueAt: sql.NullTime{Time: due, Valid: true}})
+
+	res, err := tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: 2 * time.Hour})
+	if err != nil {
+		t.Fatalf("SnoozeTask failed: %v", err)
+	}
+	if res.Task.Status != services.TaskSnoozed || res.Task.SnoozeCount != 1 || res.Roast != nil {
+		t.Fatalf("expected a snoozed task without a roast, got %+v", res)
+	}
+	if !res.Task.DueAt.Time.Equal(due.Add(2 * time.Hour)) {
+		t.Fatalf("expected a future due date to move by the duration, got %v", res.Task.DueAt.Time)
+	}
+
+	events, _ := tasks.ListTaskEvents(ctx, uid, []string{services.TaskEventSnooze}, time.Time{}, time.Time{}, 0, 0)
+	if len(events) != 1 {
+		t.Fatalf("expected one snooze event, got %d", len(events))
+	}
+	var payload services.TaskSnoozedPayload
+	_ = json.Unmarshal(events[0].EventPayload, &payload)
+	if payload.PreviousStatus != services.TaskPending || payload.PreviousDueAt == nil || !payload.PreviousDueAt.Equal(due) ||
+		payload.SnoozedMinutes != 120 || payload.SnoozeCount != 1 || payload.NextWorkHours {
+		t.Fatalf("unexpected snooze payload %s", events[0].EventPayload)
+	}
+
+	// an undated task is snoozed from now
+	undated, _ := tasks.CreateTask(ctx, uid, sqlc.Task{Title: "someday"})
+	before := time.Now()
+	res, err = tasks.SnoozeTask(ctx, undated.ID.String(), services.Snooze{For: 30 * time.Minute})
+	if err != nil {
+		t.Fatalf("SnoozeTask failed: %v", err)
+	}
+	if got := res.Task.DueAt.Time; got.Before(before.Add(30*time.Minute)) || got.After(time.Now().Add(30*time.Minute)) {
+		t.Fatalf("expected the task due in 30 minutes, got %v", got)
+	}
+
+	invalid := []services.Snooze{
+		{},
+		{For: time.Minute},
+		{For: 31 * 24 * time.Hour},
+		{For: time.Hour, NextWorkHours: true},
+	}
+	for _, snooze := range invalid {
+		if _, err := tasks.SnoozeTask(ctx, task.ID.String(), snooze); !errors.Is(err, services.ErrInvalidTask) {
+			t.Errorf("%+v: expected ErrInvalidTask, got %v", snooze, err)
+		}
+	}
+
+	_, _ = tasks.CompleteTask(ctx, task.ID.String())
+	if _, err := tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: time.Hour}); !errors.Is(err, services.ErrInvalidTask) {
+		t.Fatalf("expected completed tasks to be unsnoozable, got %v", err)
+	}
+	if got, _ := tasks.GetTask(ctx, task.ID.String()); got.SnoozeCount != 1 {
+		t.Fatalf("expected rejected snoozes not to count, got %d", got.SnoozeCount)
+	}
+}
+
+func TestSnoozeTaskNextWorkHours(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	tasks.SetPreferences(prefs)
+	user, _ := repos.Users.CreateUser(ctx, "snooze-work@test.com", "hash")
+	uid := user.ID.String()
+
+	snoozeFrom := func(due time.Time) time.Time {
+		t.Helper()
+		task, _ := tasks.CreateTask(ctx, uid, sqlc.Task{Title: "review", DueAt: sql.NullTime{Time: due, Valid: true}})
+		res, err := tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{NextWorkHours: true})
+		if err != nil {
+			t.Fatalf("SnoozeTask failed: %v", err)
+		}
+		return res.Task.DueAt.Time
+	}
+
+	// without work hours the day starts at 9:00 UTC
+	if got := snoozeFrom(time.Date(2030, 1, 15, 10, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2030, 1, 16, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 9:00 UTC the next day, got %v", got)
+	}
+
+	start, end := int32(8*60+30), int32(17*60)
+	_, err := prefs.UpdatePreferences(ctx, uid, sqlc.UserPreference{
+		Timezone:        "America/New_York",
+		WorkStartMinute: sql.NullInt32{Int32: start, Valid: true},
+		WorkEndMinute:   sql.NullInt32{Int32: end, Valid: true},
+	}, []string{"timezone", "work_hours"})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+
+	// 7:00 in New York is before the work day, 10:00 is in it
+	if got := snoozeFrom(time.Date(2030, 1, 15, 12, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2030, 1, 15, 13, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected 8:30 in New York the same day, got %v", got)
+	}
+	if got := snoozeFrom(time.Date(2030, 1, 15, 15, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2030, 1, 16, 13, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected 8:30 in New York the next day, got %v", got)
+	}
+}
+
+func TestSnoozeRoastEscalates(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	llm := &recordingLLM{}
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	tasks.SetSnooze(services.SnoozeConfig{MaxSnoozes: 4, MinDuration: time.Minute, MaxDuration: time.Hour, RoastAfter: 2})
+	tasks.SetOrchestrator(ml.NewHybridOrchestrator(llm))
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	tasks.SetPreferences(prefs)
+	user, _ := repos.Users.CreateUser(ctx, "snooze-roast@test.com", "hash")
+	_, _ = prefs.UpdatePreferences(ctx, user.ID.String(), sqlc.UserPreference{Persona: "coach", HumorIntensity: 3}, []string{"persona", "humor_intensity"})
+	task, _ := tasks.CreateTask(ctx, user.ID.String(), sqlc.Task{Title: "do the dishes"})
+
+	for i, want := range []int{0, 3, 4, 5} {
+		llm.last = ml.HybridInput{}
+		res, err := tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: 10 * time.Minute})
+		if err != nil {
+			t.Fatalf("snooze %d failed: %v", i+1, err)
+		}
+		if want == 0 {
+			if res.Roast != nil || llm.last.Text != "" {
+				t.Fatalf("expected no roast for the first snooze, got %+v", res.Roast)
+			}
+			continue
+		}
+		if res.Roast == nil || res.Roast.RoastText != "Coach: noted" {
+			t.Fatalf("snooze %d: expected a coach roast, got %+v", i+1, res.Roast)
+		}
+		if llm.last.Intensity != want || llm.last.Persona != ml.PersonaCoach || llm.last.UserID != user.ID.String() {
+			t.Fatalf("snooze %d: expected intensity %d, got %+v", i+1, want, llm.last)
+		}
+	}
+
+	if _, err := tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: 10 * time.Minute}); !errors.Is(err, services.ErrSnoozeLimit) {
+		t.Fatalf("expected ErrSnoozeLimit after four snoozes, got %v", err)
+	}
+	if got, _ := tasks.GetTask(ctx, task.ID.String()); got.SnoozeCount != 4 {
+		t.Fatalf("expected the refused snooze not to count, got %d", got.SnoozeCount)
+	}
+
+	// a failing LLM costs the roast, not the snooze
+	failing := &failingLLM{fail: true}
+	tasks.SetOrchestrator(ml.NewHybridOrchestrator(failing))
+	tasks.SetSnooze(services.SnoozeConfig{MinDuration: time.Minute, MaxDuration: time.Hour, RoastAfter: 1})
+	res, err := tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: 10 * time.Minute})
+	if err != nil || res.Roast != nil || res.Task.SnoozeCount != 5 || failing.calls != 1 {
+		t.Fatalf("expected the snooze without a roast, got %+v (%v)", res, err)
+	}
+}
+
+func TestSnoozeRoastFollowsBehaviorMode(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	llm := &recordingLLM{}
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	tasks.SetSnooze(services.SnoozeConfig{MaxSnoozes: 4, MinDuration: time.Minute, MaxDuration: time.Hour, RoastAfter: 1})
+	tasks.SetOrchestrator(ml.NewHybridOrchestrator(llm))
+	prefs := services.NewPreferencesService(repos.Preferences, nil)
+	tasks.SetPreferences(prefs)
+	tasks.SetFeatures(services.NewFeatureService(repos.Features, services.DefaultFeatureConfig))
+
+	for _, tc := range []struct {
+		mode int32
+		want int
+	}{
+		{ml.ModeProcrastinating, 4},
+		{ml.ModeSteady, 2},
+		{ml.ModeBursty, 3},
+	} {
+		user, _ := repos.Users.CreateUser(ctx, ml.ModeName(int(tc.mode))+"@test.com", "hash")
+		_, _ = prefs.UpdatePreferences(ctx, user.ID.String(), sqlc.UserPreference{Persona: "coach", HumorIntensity: 3}, []string{"persona", "humor_intensity"})
+		if _, err := repos.Features.CreateFeatureSnapshot(ctx, sqlc.FeatureSnapshot{UserID: user.ID, ModeCluster: tc.mode}); err != nil {
+			t.Fatalf("CreateFeatureSnapshot failed: %v", err)
+		}
+		task, _ := tasks.CreateTask(ctx, user.ID.String(), sqlc.Task{Title: "call the bank"})
+
+		llm.last = ml.HybridInput{}
+		res, err := tasks.SnoozeTask(ctx, task.ID.String(), services.Snooze{For: 10 * time.Minute})
+		if err != nil || res.Roast == nil {
+			t.Fatalf("%s: expected a roast, got %+v (%v)", ml.ModeName(int(tc.mode)), res, err)
+		}
+		if llm.last.Intensity != tc.want {
+			t.Fatalf("%s: expected intensity %d, got %d", ml.ModeName(int(tc.mode)), tc.want, llm.last.Intensity)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/db/sqlc"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+func TestTaskLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	user, _ := repos.Users.CreateUser(ctx, "tasks@test.com", "hash")
+	uid := user.ID.String()
+
+	due := time.Now().Add(24 * time.Hour)
+	task, err := tasks.CreateTask(ctx, uid, sqlc.Task{
+		Title:       "  write the report ",
+		Description: sql.NullString{String: "   ", Valid: true},
+		Category:    sql.NullString{String: " Work", Valid: true},
+		DueAt:       sql.NullTime{Time: due, Valid: true},
+		Priority:    2,
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if task.Title != "write the report" || task.Description.Valid || task.Category.String != "work" {
+		t.Fatalf("expected normalized fields, got %+v", task)
+	}
+	if task.Status != services.TaskPending || task.UserID != user.ID {
+		t.Fatalf("expected a pending task of the user, got %+v", task)
+	}
+
+	// only masked fields change; a masked field left empty is cleared
+	task, err = tasks.UpdateTask(ctx, task.ID.String(), sqlc.Task{
+		Title:            "write the short report",
+		Priority:         3,
+		EstimatedMinutes: sql.NullInt32{Int32: 45, Valid: true},
+	}, []string{"title", "estimated_minutes", "due_at"})
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if task.Title != "write the short report" || task.Priority != 2 || task.EstimatedMinutes.Int32 != 45 || task.DueAt.Valid {
+		t.Fatalf("unexpected updated task %+v", task)
+	}
+
+	task, err = tasks.CompleteTask(ctx, task.ID.String())
+	if err != nil || task.Status != services.TaskCompleted {
+		t.Fatalf("expected completed task, got %+v (%v)", task, err)
+	}
+	if again, err := tasks.CompleteTask(ctx, task.ID.String()); err != nil || again.Status != services.TaskCompleted {
+		t.Fatalf("expected completing twice to succeed, got %+v (%v)", again, err)
+	}
+
+	if err := tasks.DeleteTask(ctx, task.ID.String()); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := tasks.GetTask(ctx, task.ID.String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected deleted task to be gone, got %v", err)
+	}
+	if _, err := tasks.CompleteTask(ctx, task.ID.String()); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected deleted task to stay deleted, got %v", err)
+	}
+}
+
+func TestListTasks(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	user, _ := repos.Users.CreateUser(ctx, "tasks-list@test.com", "hash")
+	uid := user.ID.String()
+
+	now := time.Now()
+	for i, title := range []string{"third", "first", "second"} {
+		due := sql.NullTime{Time: now.Add(time.Duration([]int{3, 1, 2}[i]) * time.Hour), Valid: true}
+		if _, err := tasks.CreateTask(ctx, uid, sqlc.Task{Title: title, DueAt: due}); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+	undated, _ := tasks.CreateTask(ctx, uid, sqlc.Task{Title: "someday"})
+	_, _ = tasks.CompleteTask(ctx, undated.ID.String())
+
+	list, err := tasks.ListTasks(ctx, uid, "", 0, 0)
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	var titles []string
+	for _, task := range list {
+		titles = append(titles, task.Title)
+	}
+	if strings.Join(titles, ",") != "first,second,third,someday" {
+		t.Fatalf("expected tasks by due date, undated last; got %v", titles)
+	}
+
+	pending, err := tasks.ListTasks(ctx, uid, services.TaskPending, 2, 1)
+	if err != nil || len(pending) != 2 || pending[0].Title != "second" {
+		t.Fatalf("expected the second page of pending tasks, got %+v (%v)", pending, err)
+	}
+
+	if _, err := tasks.ListTasks(ctx, uid, services.TaskDeleted, 0, 0); !errors.Is(err, services.ErrInvalidTask) {
+		t.Fatalf("expected deleted tasks to be unlistable, got %v", err)
+	}
+}
+
+func TestTaskValidation(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	tasks := services.NewTaskService(repos.Tasks, repos.TaskEvents)
+	user, _ := repos.Users.CreateUser(ctx, "tasks-validate@test.com", "hash")
+	uid := user.ID.String()
+
+	invalid := []struct {
+		name string
+		task sqlc.Task
+	}{
+		{"blank title", sqlc.Task{Title: "  "}},
+		{"long title", sqlc.Task{Title: strings.Repeat("x", 201)}},
+		{"priority", sqlc.Task{Title: "t", Priority: 4}},
+		{"negative priority", sqlc.Task{Title: "t", Priority: -1}},
+		{"estimate", sqlc.Task{Title: "t", EstimatedMinutes: sql.NullInt32{Int32: 0, Valid: true}}},
+		{"long category", sqlc.Task{Title: "t", Category: sql.NullString{String: strings.Repeat("c", 65), Valid: true}}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tasks.CreateTask(ctx, uid, tc.task); !errors.Is(err, services.ErrInvalidTask) {
+				t.Fatalf("expected ErrInvalidTask, got %v", err)
+			}
+		})
+	}
+
+	task, _ := tasks.CreateTask(ctx, uid, sqlc.Task{Title: "keep me"})
+	for _, paths := range [][]string{nil, {"status"}, {"user_id"}, {"title"}} {
+		if _, err := tasks.UpdateTask(ctx, task.ID.String(), sqlc.Task{}, paths); !errors.Is(err, services.ErrInvalidTask) {
+			t.Errorf("%v: expected ErrInvalidTask, got %v", paths, err)
+		}
+	}
+	if got, _ := tasks.GetTask(ctx, task.ID.String()); got.Title != "keep me" {
+		t.Fatalf("expected rejected updates to save nothing, got %q", got.Title)
+	}
+	if _, err := tasks.GetTask(ctx, "not-a-uuid"); !errors.Is(err, services.ErrInvalidTask) {
+		t.Fatalf("expected ErrInvalidTask for a malformed id, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"guiltmachine/internal/auth"
+	"guiltmachine/internal/services"
+	"guiltmachine/test/fakes"
+)
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	jwt := auth.NewJWTManager("login-test-secret", time.Hour)
+	users := services.NewUserServiceWithAuth(repos.Users, repos.Sessions, jwt)
+
+	user, err := users.CreateUser(ctx, "  Login@Test.com ", "correct horse battery")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if user.Email != "login@test.com" {
+		t.Fatalf("expected the email stored normalised, got %q", user.Email)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		res, err := users.Login(ctx, "login@test.com", "correct horse battery")
+		if err != nil {
+			t.Fatalf("Login failed: %v", err)
+		}
+		if res.User.ID != user.ID || res.Session.UserID != user.ID || res.RefreshToken == "" {
+			t.Fatalf("unexpected login result %+v", res)
+		}
+		userID, sessionID, err := jwt.Verify(res.JWT)
+		if err != nil || userID != user.ID.String() || sessionID != res.Session.ID.String() {
+			t.Fatalf("expected a JWT for the new session, got %q %q (%v)", userID, sessionID, err)
+		}
+	})
+
+	t.Run("email is normalised", func(t *testing.T) {
+		res, err := users.Login(ctx, " LOGIN@test.COM  ", "correct horse battery")
+		if err != nil || res.User.ID != user.ID {
+			t.Fatalf("expected a case and space insensitive email, got %v", err)
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		if _, err := users.Login(ctx, "login@test.com", "wrong horse battery"); !errors.Is(err, auth.ErrInvalidCredentials) {
+			t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+		}
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		start := time.Now()
+		_, _ = users.Login(ctx, "login@test.com", "wrong horse battery")
+		wrongPassword := time.Since(start)
+
+		start = time.Now()
+		_, err := users.Login(ctx, "nobody@test.com", "wrong horse battery")
+		unknown := time.Since(start)
+		if !errors.Is(err, auth.ErrInvalidCredentials) {
+			t.Fatalf("expected the same error as a wrong password, got %v", err)
+		}
+		// both paths run bcrypt, so an unknown email is not noticeably faster
+		if unknown < wrongPassword/4 {
+			t.Fatalf("unknown email took %v, a wrong password %v", unknown, wrongPassword)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"guiltmachine/internal/auth"
+	cacheDomain "guiltmachine/internal/cache/domain"
+	"guiltmachine/internal/cache/memory"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+)
+
+func TestAuthInterceptorRejectsRevokedSessions(t *testing.T) {
+	ctx := context.Background()
+	jwtManager := auth.NewJWTManager("interceptor-test-secret", time.Hour)
+	sessions := cacheDomain.NewSessionCache(memory.NewMemoryCache())
+	interceptor := grpchandlers.AuthInterceptor(jwtManager, sessions)
+
+	call := func(method, token string) (string, error) {
+		callCtx := ctx
+		if token != "" {
+			callCtx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+		}
+		var userID string
+		_, err := interceptor(callCtx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			userID, _ = auth.UserIDFromContext(ctx)
+			return nil, nil
+		})
+		return userID, err
+	}
+	const getEntry = "/guiltmachine.v1.EntryService/GetEntry"
+
+	token, _ := jwtManager.Issue("user-1", "session-1")
+	if userID, err := call(getEntry, token); err != nil || userID != "user-1" {
+		t.Fatalf("expected the call through as user-1, got %q (%v)", userID, err)
+	}
+
+	if _, err := call(getEntry, ""); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without a token, got %v", err)
+	}
+	if _, err := call("/guiltmachine.v1.UserService/Login", ""); err != nil {
+		t.Fatalf("expected public methods to skip auth, got %v", err)
+	}
+
+	if err := sessions.RevokeSession(ctx, "session-1", time.Hour); err != nil {
+		t.Fatalf("RevokeSession failed: %v", err)
+	}
+	if _, err := call(getEntry, token); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated for a revoked session, got %v", err)
+	}
+
+	// other sessions of the same user are unaffected
+	other, _ := jwtManager.Issue("user-1", "session-2")
+	if _, err := call(getEntry, other); err != nil {
+		t.Fatalf("expected another session to keep working, got %v", err)
+	}
+}
+
+func TestAuthStreamInterceptorRejectsRevokedSessions(t *testing.T) {
+	ctx := context.Background()
+	jwtManager := auth.NewJWTManager("interceptor-test-secret", time.Hour)
+	sessions := cacheDomain.NewSessionCache(memory.NewMemoryCache())
+	interceptor := grpchandlers.AuthStreamInterceptor(jwtManager, sessions)
+
+	token, _ := jwtManager.Issue("user-1", "session-1")
+	_ = sessions.RevokeSession(ctx, "session-1", time.Hour)
+
+	stream := &contextStream{ctx: metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))}
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/guiltmachine.v1.EntryService/WatchEntry"}, func(srv interface{}, stream grpc.ServerStream) error {
+		t.Fatal("expected the handler not to run for a revoked session")
+		return nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated for a revoked session, got %v", err)
+	}
+}
+
+// contextStream is a grpc.ServerStream that only carries a context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -42,3 +42,25 @@ func (s *testGRPCServer) stop() {
 func (s *testGRPCServer) getAddr() string {
 	return s.addr
 }
+
+func startTestGRPCWithOptions(t *testing.T, opts []grpc.ServerOption, register func(*grpc.Server)) *testGRPCServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+
+	s := grpc.NewServer(opts...)
+	register(s)
+
+	go func() {
+		_ = s.Serve(l)
+	}()
+
+	return &testGRPCServer{
+		grpcServer: s,
+		lis:        l,
+		addr:       l.Addr().String(),
+	}
+}
@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "guiltmachine/internal/proto/gen"
+	svcs "guiltmachine/internal/services"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+	"guiltmachine/test/fakes"
+)
+
+func TestInsightsHandler(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	features := svcs.NewFeatureService(repos.Features, svcs.DefaultFeatureConfig)
+	handler := grpchandlers.NewInsightsHandler(features)
+	user, _ := repos.Users.CreateUser(ctx, "insights-handler@test.com", "hash")
+	uid := user.ID.String()
+
+	// before the batch ran the user gets an unstored snapshot computed on the spot
+	first, err := handler.GetFeatureSnapshot(ctx, &v1.GetFeatureSnapshotRequest{UserId: uid})
+	if err != nil || first.Snapshot.Mode != v1.BehaviorMode_BEHAVIOR_MODE_IDLE || first.Snapshot.ClusterVersion != 0 || first.Snapshot.SnapshotId != "" {
+		t.Fatalf("expected an unstored idle snapshot for a new user, got %v (%v)", first, err)
+	}
+
+	_, _ = repos.Sessions.CreateSession(ctx, user.ID, nil)
+	now := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	if _, err := features.SnapshotAll(ctx, now); err != nil {
+		t.Fatalf("SnapshotAll failed: %v", err)
+	}
+
+	got, err := handler.GetFeatureSnapshot(ctx, &v1.GetFeatureSnapshotRequest{UserId: uid})
+	if err != nil {
+		t.Fatalf("GetFeatureSnapshot failed: %v", err)
+	}
+	snap := got.Snapshot
+	if snap.UserId != uid || snap.SnapshotId == "" || !snap.WindowEnd.AsTime().Equal(now) || snap.CreatedAt == nil {
+		t.Fatalf("unexpected snapshot %v", snap)
+	}
+	if snap.Mode != v1.BehaviorMode_BEHAVIOR_MODE_STEADY || snap.ModeCluster != int32(snap.Mode) {
+		t.Fatalf("expected a single session to read as steady, got %v", snap)
+	}
+
+	list, err := handler.ListFeatureSnapshots(ctx, &v1.ListFeatureSnapshotsRequest{UserId: uid})
+	if err != nil || len(list.Snapshots) != 1 || list.Snapshots[0].SnapshotId != snap.SnapshotId {
+		t.Fatalf("expected only the batch snapshot listed, got %v (%v)", list, err)
+	}
+
+	invalid := []func() error{
+		func() error {
+			_, err := handler.GetFeatureSnapshot(ctx, &v1.GetFeatureSnapshotRequest{})
+			return err
+		},
+		func() error {
+			_, err := handler.GetFeatureSnapshot(ctx, &v1.GetFeatureSnapshotRequest{UserId: "nope"})
+			return err
+		},
+		func() error {
+			_, err := handler.ListFeatureSnapshots(ctx, &v1.ListFeatureSnapshotsRequest{UserId: uid, Offset: -1})
+			return err
+		},
+	}
+	for i, call := range invalid {
+		if err := call(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("case %d: expected InvalidArgument, got %v", i, err)
+		}
+	}
+}
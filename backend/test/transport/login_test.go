@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"guiltmachine/internal/auth"
+	v1 "guiltmachine/internal/proto/gen"
+	svcs "guiltmachine/internal/services"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+	"guiltmachine/test/fakes"
+)
+
+func TestUserHandlerLogin(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	users := svcs.NewUserServiceWithAuth(repos.Users, repos.Sessions, auth.NewJWTManager("login-handler-secret", time.Hour))
+	handler := grpchandlers.NewUserHandler(users)
+
+	user, err := users.CreateUser(ctx, "handler@test.com", "correct horse battery")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+
+	resp, err := handler.Login(ctx, &v1.LoginRequest{Email: "Handler@Test.com ", Password: "correct horse battery"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if resp.UserId != user.ID.String() || resp.SessionId == "" || resp.Jwt == "" || resp.RefreshToken == "" {
+		t.Fatalf("unexpected login response %+v", resp)
+	}
+
+	for name, req := range map[string]*v1.LoginRequest{
+		"wrong password": {Email: "handler@test.com", Password: "wrong horse battery"},
+		"unknown email":  {Email: "nobody@test.com", Password: "correct horse battery"},
+	} {
+		if _, err := handler.Login(ctx, req); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("%s: expected Unauthenticated, got %v", name, err)
+		}
+	}
+	if _, err := handler.Login(ctx, &v1.LoginRequest{Email: "handler@test.com"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without a password, got %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"guiltmachine/internal/auth"
+	"guiltmachine/internal/db/sqlc"
+	v1 "guiltmachine/internal/proto/gen"
+	sessionv1 "guiltmachine/internal/proto/gen/v1"
+	svcs "guiltmachine/internal/services"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+	"guiltmachine/test/fakes"
+)
+
+func TestResourceOwnershipPolicy(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	jwtManager := auth.NewJWTManager("policy-test-secret", time.Hour)
+
+	alice, _ := repos.Users.CreateUser(ctx, "alice@test.com", "hash")
+	bob, _ := repos.Users.CreateUser(ctx, "bob@test.com", "hash")
+	aliceSession, _ := repos.Sessions.CreateSession(ctx, alice.ID, nil)
+	bobSession, _ := repos.Sessions.CreateSession(ctx, bob.ID, nil)
+	aliceEntry, _ := repos.Entries.CreateEntry(ctx, aliceSession.ID, "doomscrolled all night", 6)
+	aliceTask, _ := repos.Tasks.CreateTask(ctx, sqlc.Task{UserID: alice.ID, Title: "go to bed"}, sqlc.TaskEvent{EventType: "create"})
+
+	aliceToken, _ := jwtManager.Issue(alice.ID.String(), aliceSession.ID.String())
+	bobToken, _ := jwtManager.Issue(bob.ID.String(), bobSession.ID.String())
+
+	authz := svcs.NewAuthorizer(repos.Sessions, repos.Entries)
+	authz.SetTasks(repos.Tasks)
+	prefsService := svcs.NewPreferencesService(repos.Preferences, nil)
+
+	s := startTestGRPCWithOptions(t, []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			grpchandlers.AuthInterceptor(jwtManager, nil),
+			grpchandlers.PolicyInterceptor(authz),
+		),
+	}, func(gs *grpc.Server) {
+		v1.RegisterUserServiceServer(gs, grpchandlers.NewUserHandler(svcs.NewUserService(repos.Users)))
+		sessionv1.RegisterSessionServiceServer(gs, grpchandlers.NewSessionHandler(svcs.NewSessionService(repos.Sessions, nil)))
+		v1.RegisterEntryServiceServer(gs, grpchandlers.NewEntryHandler(svcs.NewEntryService(repos.Entries)))
+		v1.RegisterScoreServiceServer(gs, grpchandlers.NewScoreHandler(svcs.NewScoreService(repos.Scores)))
+		v1.RegisterPreferencesServiceServer(gs, grpchandlers.NewPreferencesHandler(prefsService))
+		v1.RegisterTaskServiceServer(gs, grpchandlers.NewTaskHandler(svcs.NewTaskService(repos.Tasks, repos.TaskEvents)))
+		v1.RegisterInsightsServiceServer(gs, grpchandlers.NewInsightsHandler(svcs.NewFeatureService(repos.Features, svcs.DefaultFeatureConfig)))
+	})
+	defer s.stop()
+
+	conn, err := grpc.NewClient(s.getAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	users := v1.NewUserServiceClient(conn)
+	sessions := sessionv1.NewSessionServiceClient(conn)
+	entries := v1.NewEntryServiceClient(conn)
+	scores := v1.NewScoreServiceClient(conn)
+	prefs := v1.NewPreferencesServiceClient(conn)
+	tasks := v1.NewTaskServiceClient(conn)
+	insights := v1.NewInsightsServiceClient(conn)
+
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	asAlice := withToken(aliceToken)
+	asBob := withToken(bobToken)
+
+	crossUser := []struct {
+		name string
+		call func() error
+	}{
+		{"GetUser", func() error {
+			_, err := users.GetUser(asBob, &v1.GetUserRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"CreateSession", func() error {
+			_, err := sessions.CreateSession(asBob, &sessionv1.CreateSessionRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"GetSession", func() error {
+			_, err := sessions.GetSession(asBob, &sessionv1.GetSessionRequest{Id: aliceSession.ID.String()})
+			return err
+		}},
+		{"EndSession", func() error {
+			_, err := sessions.EndSession(asBob, &sessionv1.EndSessionRequest{Id: aliceSession.ID.String()})
+			return err
+		}},
+		{"ListSessionsByUser", func() error {
+			_, err := sessions.ListSessionsByUser(asBob, &sessionv1.ListSessionsByUserRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"CreateEntry", func() error {
+			_, err := entries.CreateEntry(asBob, &v1.CreateEntryRequest{SessionId: aliceSession.ID.String(), Text: "not mine"})
+			return err
+		}},
+		{"ListEntries", func() error {
+			_, err := entries.ListEntries(asBob, &v1.ListEntriesRequest{SessionId: aliceSession.ID.String()})
+			return err
+		}},
+		{"GetEntry", func() error {
+			_, err := entries.GetEntry(asBob, &v1.GetEntryRequest{EntryId: aliceEntry.ID.String()})
+			return err
+		}},
+		{"CreateScore", func() error {
+			_, err := scores.CreateScore(asBob, &v1.CreateScoreRequest{SessionId: aliceSession.ID.String(), Score: 10})
+			return err
+		}},
+		{"GetScore", func() error {
+			_, err := scores.GetScore(asBob, &v1.GetScoreRequest{SessionId: aliceSession.ID.String()})
+			return err
+		}},
+		{"UpsertPreferences", func() error {
+			_, err := prefs.UpsertPreferences(asBob, &v1.UpsertPreferencesRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"GetPreferences", func() error {
+			_, err := prefs.GetPreferences(asBob, &v1.GetPreferencesRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"UpdatePreferences", func() error {
+			_, err := prefs.UpdatePreferences(asBob, &v1.UpdatePreferencesRequest{
+				UserId:      alice.ID.String(),
+				Preferences: &v1.Preferences{Persona: v1.Persona_PERSONA_CHILL},
+				UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"persona"}},
+			})
+			return err
+		}},
+		{"CreateTask", func() error {
+			_, err := tasks.CreateTask(asBob, &v1.CreateTaskRequest{UserId: alice.ID.String(), Task: &v1.Task{Title: "not mine"}})
+			return err
+		}},
+		{"ListTasks", func() error {
+			_, err := tasks.ListTasks(asBob, &v1.ListTasksRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"GetTask", func() error {
+			_, err := tasks.GetTask(asBob, &v1.GetTaskRequest{TaskId: aliceTask.ID.String()})
+			return err
+		}},
+		{"UpdateTask", func() error {
+			_, err := tasks.UpdateTask(asBob, &v1.UpdateTaskRequest{
+				TaskId:     aliceTask.ID.String(),
+				Task:       &v1.Task{Title: "stay up"},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+			})
+			return err
+		}},
+		{"CompleteTask", func() error {
+			_, err := tasks.CompleteTask(asBob, &v1.CompleteTaskRequest{TaskId: aliceTask.ID.String()})
+			return err
+		}},
+		{"DeleteTask", func() error {
+			_, err := tasks.DeleteTask(asBob, &v1.DeleteTaskRequest{TaskId: aliceTask.ID.String()})
+			return err
+		}},
+		{"SnoozeTask", func() error {
+			_, err := tasks.SnoozeTask(asBob, &v1.SnoozeTaskRequest{TaskId: aliceTask.ID.String(), NextWorkHours: true})
+			return err
+		}},
+		{"ListTaskEvents", func() error {
+			_, err := tasks.ListTaskEvents(asBob, &v1.ListTaskEventsRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"GetFeatureSnapshot", func() error {
+			_, err := insights.GetFeatureSnapshot(asBob, &v1.GetFeatureSnapshotRequest{UserId: alice.ID.String()})
+			return err
+		}},
+		{"ListFeatureSnapshots", func() error {
+			_, err := insights.ListFeatureSnapshots(asBob, &v1.ListFeatureSnapshotsRequest{UserId: alice.ID.String()})
+			return err
+		}},
+	}
+
+	for _, tc := range crossUser {
+		t.Run("cross-user "+tc.name, func(t *testing.T) {
+			if code := status.Code(tc.call()); code != codes.PermissionDenied {
+				t.Fatalf("expected PermissionDenied, got %v", code)
+			}
+		})
+	}
+
+	t.Run("owner can read own session", func(t *testing.T) {
+		resp, err := sessions.GetSession(asAlice, &sessionv1.GetSessionRequest{Id: aliceSession.ID.String()})
+		if err != nil {
+			t.Fatalf("GetSession failed: %v", err)
+		}
+		if resp.UserId != alice.ID.String() {
+			t.Fatalf("unexpected session owner %s", resp.UserId)
+		}
+	})
+
+	t.Run("owner can read own entry", func(t *testing.T) {
+		if _, err := entries.GetEntry(asAlice, &v1.GetEntryRequest{EntryId: aliceEntry.ID.String()}); err != nil {
+			t.Fatalf("GetEntry failed: %v", err)
+		}
+	})
+
+	t.Run("owner can complete own task", func(t *testing.T) {
+		resp, err := tasks.CompleteTask(asAlice, &v1.CompleteTaskRequest{TaskId: aliceTask.ID.String()})
+		if err != nil {
+			t.Fatalf("CompleteTask failed: %v", err)
+		}
+		if resp.Task.Status != v1.TaskStatus_TASK_STATUS_COMPLETED {
+			t.Fatalf("unexpected task status %v", resp.Task.Status)
+		}
+	})
+
+	t.Run("missing token is unauthenticated", func(t *testing.T) {
+		_, err := sessions.GetSession(ctx, &sessionv1.GetSessionRequest{Id: aliceSession.ID.String()})
+		if code := status.Code(err); code != codes.Unauthenticated {
+			t.Fatalf("expected Unauthenticated, got %v", code)
+		}
+	})
+
+	t.Run("malformed id is invalid argument", func(t *testing.T) {
+		_, err := sessions.GetSession(asAlice, &sessionv1.GetSessionRequest{Id: "nope"})
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", code)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package transport
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	v1 "guiltmachine/internal/proto/gen"
+	svcs "guiltmachine/internal/services"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+	"guiltmachine/test/fakes"
+)
+
+func TestPreferencesHandlerTypedFields(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	handler := grpchandlers.NewPreferencesHandler(svcs.NewPreferencesService(repos.Preferences, nil))
+	user, _ := repos.Users.CreateUser(ctx, "prefs-handler@test.com", "hash")
+	uid := user.ID.String()
+
+	_, err := handler.UpsertPreferences(ctx, &v1.UpsertPreferencesRequest{
+		UserId:       uid,
+		MetadataJson: `{"color":"teal"}`,
+		Preferences: &v1.Preferences{
+			Theme:          "dark",
+			Notifications:  true,
+			Persona:        v1.Persona_PERSONA_COACH,
+			HumorIntensity: 6,
+			Timezone:       "America/New_York",
+			WorkHours:      &v1.TimeRange{Start: "09:00", End: "17:30"},
+			Language:       "en-US",
+			NudgeChannels:  []v1.NudgeChannel{v1.NudgeChannel_NUDGE_CHANNEL_IN_APP},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpsertPreferences failed: %v", err)
+	}
+
+	res, err := handler.UpdatePreferences(ctx, &v1.UpdatePreferencesRequest{
+		UserId:      uid,
+		Preferences: &v1.Preferences{QuietHours: &v1.TimeRange{Start: "22:00", End: "07:00"}},
+		UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"quiet_hours"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	p := res.Preferences
+	if p.Persona != v1.Persona_PERSONA_COACH || p.HumorIntensity != 6 || p.Language != "en-US" || p.Theme != "dark" {
+		t.Fatalf("expected unmasked fields to survive, got %v", p)
+	}
+	if p.WorkHours.GetEnd() != "17:30" || p.QuietHours.GetStart() != "22:00" || p.QuietHours.GetEnd() != "07:00" {
+		t.Fatalf("expected HH:MM ranges to round trip, got %v / %v", p.WorkHours, p.QuietHours)
+	}
+	if len(p.NudgeChannels) != 1 || p.NudgeChannels[0] != v1.NudgeChannel_NUDGE_CHANNEL_IN_APP {
+		t.Fatalf("expected nudge channels to round trip, got %v", p.NudgeChannels)
+	}
+
+	got, err := handler.GetPreferences(ctx, &v1.GetPreferencesRequest{UserId: uid})
+	if err != nil {
+		t.Fatalf("GetPreferences failed: %v", err)
+	}
+	if got.MetadataJson != `{"color":"teal"}` || got.Theme != "dark" || got.Preferences.QuietHours == nil {
+		t.Fatalf("unexpected preferences %v", got)
+	}
+
+	invalid := []*v1.UpdatePreferencesRequest{
+		{UserId: uid, Preferences: &v1.Preferences{}},
+		{UserId: uid, Preferences: &v1.Preferences{WorkHours: &v1.TimeRange{Start: "25:00", End: "17:00"}}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"work_hours"}}},
+		{UserId: uid, Preferences: &v1.Preferences{HumorIntensity: 42}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"humor_intensity"}}},
+		{UserId: uid, Preferences: &v1.Preferences{Persona: v1.Persona(99)}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"persona"}}},
+		{UserId: uid, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"work_hours.start"}}},
+	}
+	for _, req := range invalid {
+		if _, err := handler.UpdatePreferences(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", req, err)
+		}
+	}
+}
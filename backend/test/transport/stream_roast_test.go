@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"guiltmachine/internal/auth"
+	"guiltmachine/internal/ml"
+	v1 "guiltmachine/internal/proto/gen"
+	svcs "guiltmachine/internal/services"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+	"guiltmachine/test/fakes"
+)
+
+// recvAll drains a StreamRoast call into its deltas and final message
+func recvAll(stream v1.EntryService_StreamRoastClient) ([]string, *v1.StreamRoastResponse, error) {
+	var deltas []string
+	var final *v1.StreamRoastResponse
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return deltas, final, nil
+		}
+		if err != nil {
+			return deltas, final, err
+		}
+		if msg.Done {
+			final = msg
+			continue
+		}
+		deltas = append(deltas, msg.Delta)
+	}
+}
+
+func TestStreamRoast(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	jwtManager := auth.NewJWTManager("stream-test-secret", time.Hour)
+
+	alice, _ := repos.Users.CreateUser(ctx, "alice-stream@test.com", "hash")
+	bob, _ := repos.Users.CreateUser(ctx, "bob-stream@test.com", "hash")
+	aliceSession, _ := repos.Sessions.CreateSession(ctx, alice.ID, nil)
+	bobSession, _ := repos.Sessions.CreateSession(ctx, bob.ID, nil)
+	aliceEntry, _ := repos.Entries.CreateEntry(ctx, aliceSession.ID, "ordered takeout twice today", 7)
+
+	aliceToken, _ := jwtManager.Issue(alice.ID.String(), aliceSession.ID.String())
+	bobToken, _ := jwtManager.Issue(bob.ID.String(), bobSession.ID.String())
+
+	authz := svcs.NewAuthorizer(repos.Sessions, repos.Entries)
+	orchestrator := ml.NewHybridOrchestrator(ml.NewInferenceStub())
+	entryService := svcs.NewEntryServiceWithOutbox(repos.Entries, repos.Scores, orchestrator, nil)
+
+	s := startTestGRPCWithOptions(t, []grpc.ServerOption{
+		grpc.ChainStreamInterceptor(
+			grpchandlers.AuthStreamInterceptor(jwtManager, nil),
+			grpchandlers.PolicyStreamInterceptor(authz),
+		),
+	}, func(gs *grpc.Server) {
+		v1.RegisterEntryServiceServer(gs, grpchandlers.NewEntryHandler(entryService))
+	})
+	defer s.stop()
+
+	conn, err := grpc.NewClient(s.getAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	entries := v1.NewEntryServiceClient(conn)
+
+	asAlice := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+aliceToken)
+	asBob := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+bobToken)
+	req := &v1.StreamRoastRequest{EntryId: aliceEntry.ID.String()}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		stream, err := entries.StreamRoast(ctx, req)
+		if err == nil {
+			_, _, err = recvAll(stream)
+		}
+		if code := status.Code(err); code != codes.Unauthenticated {
+			t.Fatalf("expected Unauthenticated, got %v", code)
+		}
+	})
+
+	t.Run("cross-user", func(t *testing.T) {
+		stream, err := entries.StreamRoast(asBob, req)
+		if err == nil {
+			_, _, err = recvAll(stream)
+		}
+		if code := status.Code(err); code != codes.PermissionDenied {
+			t.Fatalf("expected PermissionDenied, got %v", code)
+		}
+	})
+
+	t.Run("owner streams and persists the roast", func(t *testing.T) {
+		stream, err := entries.StreamRoast(asAlice, req)
+		if err != nil {
+			t.Fatalf("StreamRoast failed: %v", err)
+		}
+		deltas, final, err := recvAll(stream)
+		if err != nil {
+			t.Fatalf("stream failed: %v", err)
+		}
+		if len(deltas) < 2 || final == nil {
+			t.Fatalf("expected several deltas and a final message, got %q / %v", deltas, final)
+		}
+		if strings.Join(deltas, "") != final.RoastText {
+			t.Fatalf("deltas %q do not add up to %q", strings.Join(deltas, ""), final.RoastText)
+		}
+
+		e, _ := repos.Entries.GetEntry(ctx, aliceEntry.ID)
+		if e.Status.String != "completed" || e.RoastText.String != final.RoastText {
+			t.Fatalf("expected completed entry with streamed roast, got %q %q", e.Status.String, e.RoastText.String)
+		}
+		if score, _ := repos.Scores.GetScoreByEntry(ctx, aliceEntry.ID); score.AggregateScore != final.GuiltScore {
+			t.Fatalf("expected score %d, got %d", final.GuiltScore, score.AggregateScore)
+		}
+	})
+
+	t.Run("completed entry is not regenerated", func(t *testing.T) {
+		stream, err := entries.StreamRoast(asAlice, req)
+		if err != nil {
+			t.Fatalf("StreamRoast failed: %v", err)
+		}
+		deltas, final, err := recvAll(stream)
+		if err != nil {
+			t.Fatalf("stream failed: %v", err)
+		}
+		if len(deltas) != 0 || final == nil || final.RoastText == "" {
+			t.Fatalf("expected only the stored roast, got %q / %v", deltas, final)
+		}
+		if got := repos.Scores.ScoresForEntry(aliceEntry.ID); len(got) != 1 {
+			t.Fatalf("expected one score, got %d", len(got))
+		}
+	})
+
+	t.Run("entry held by a worker is aborted", func(t *testing.T) {
+		busy, _ := repos.Entries.CreateEntry(ctx, aliceSession.ID, "still processing", 3)
+		_, _ = repos.Entries.ClaimEntry(ctx, busy.ID, "worker-job")
+
+		stream, err := entries.StreamRoast(asAlice, &v1.StreamRoastRequest{EntryId: busy.ID.String()})
+		if err == nil {
+			_, _, err = recvAll(stream)
+		}
+		if code := status.Code(err); code != codes.Aborted {
+			t.Fatalf("expected Aborted, got %v", code)
+		}
+	})
+}
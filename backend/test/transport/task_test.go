@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "guiltmachine/internal/proto/gen"
+	svcs "guiltmachine/internal/services"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+	"guiltmachine/test/fakes"
+)
+
+func TestTaskHandler(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	handler := grpchandlers.NewTaskHandler(svcs.NewTaskService(repos.Tasks, repos.TaskEvents))
+	user, _ := repos.Users.CreateUser(ctx, "task-handler@test.com", "hash")
+	uid := user.ID.String()
+
+	due := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	created, err := handler.CreateTask(ctx, &v1.CreateTaskRequest{
+		UserId: uid,
+		Task: &v1.Task{
+			Title:            "ship the release",
+			Description:      "tag and announce",
+			DueAt:            timestamppb.New(due),
+			Category:         "Work",
+			Priority:         3,
+			EstimatedMinutes: 30,
+			Status:           v1.TaskStatus_TASK_STATUS_COMPLETED, // output only
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	task := created.Task
+	if task.Status != v1.TaskStatus_TASK_STATUS_PENDING || task.UserId != uid || task.Category != "work" {
+		t.Fatalf("unexpected created task %v", task)
+	}
+	if !task.DueAt.AsTime().Equal(due) || task.EstimatedMinutes != 30 || task.Description != "tag and announce" {
+		t.Fatalf("expected fields to round trip, got %v", task)
+	}
+
+	updated, err := handler.UpdateTask(ctx, &v1.UpdateTaskRequest{
+		TaskId:     task.TaskId,
+		Task:       &v1.Task{Priority: 1},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"priority", "due_at"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if updated.Task.Priority != 1 || updated.Task.DueAt != nil || updated.Task.Title != "ship the release" {
+		t.Fatalf("expected only masked fields to change, got %v", updated.Task)
+	}
+
+	list, err := handler.ListTasks(ctx, &v1.ListTasksRequest{UserId: uid, Status: v1.TaskStatus_TASK_STATUS_PENDING})
+	if err != nil || len(list.Tasks) != 1 {
+		t.Fatalf("expected one pending task, got %v (%v)", list, err)
+	}
+
+	snoozed, err := handler.SnoozeTask(ctx, &v1.SnoozeTaskRequest{TaskId: task.TaskId, Duration: durationpb.New(time.Hour)})
+	if err != nil {
+		t.Fatalf("SnoozeTask failed: %v", err)
+	}
+	if snoozed.Task.Status != v1.TaskStatus_TASK_STATUS_SNOOZED || snoozed.Task.SnoozeCount != 1 || snoozed.Task.DueAt == nil {
+		t.Fatalf("expected a snoozed task with a due date, got %v", snoozed.Task)
+	}
+
+	if _, err := handler.DeleteTask(ctx, &v1.DeleteTaskRequest{TaskId: task.TaskId}); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := handler.GetTask(ctx, &v1.GetTaskRequest{TaskId: task.TaskId}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for a deleted task, got %v", err)
+	}
+
+	events, err := handler.ListTaskEvents(ctx, &v1.ListTaskEventsRequest{
+		UserId: uid,
+		Types:  []v1.TaskEventType{v1.TaskEventType_TASK_EVENT_TYPE_MODIFY, v1.TaskEventType_TASK_EVENT_TYPE_DELETE},
+	})
+	if err != nil || len(events.Events) != 2 {
+		t.Fatalf("expected the modify and delete events, got %v (%v)", events, err)
+	}
+	if e := events.Events[0]; e.Type != v1.TaskEventType_TASK_EVENT_TYPE_MODIFY || e.TaskId != task.TaskId || e.PayloadJson == "" {
+		t.Fatalf("unexpected modify event %v", e)
+	}
+
+	invalid := []func() error{
+		func() error {
+			_, err := handler.CreateTask(ctx, &v1.CreateTaskRequest{UserId: uid})
+			return err
+		},
+		func() error {
+			_, err := handler.CreateTask(ctx, &v1.CreateTaskRequest{UserId: uid, Task: &v1.Task{Title: "t", Priority: 70000}})
+			return err
+		},
+		func() error {
+			_, err := handler.CreateTask(ctx, &v1.CreateTaskRequest{UserId: uid, Task: &v1.Task{Title: "t", EstimatedMinutes: -5}})
+			return err
+		},
+		func() error {
+			_, err := handler.UpdateTask(ctx, &v1.UpdateTaskRequest{TaskId: task.TaskId, Task: &v1.Task{Title: "t"}})
+			return err
+		},
+		func() error {
+			_, err := handler.ListTasks(ctx, &v1.ListTasksRequest{UserId: uid, Status: v1.TaskStatus(42)})
+			return err
+		},
+		func() error {
+			_, err := handler.GetTask(ctx, &v1.GetTaskRequest{TaskId: "nope"})
+			return err
+		},
+		func() error {
+			_, err := handler.SnoozeTask(ctx, &v1.SnoozeTaskRequest{TaskId: task.TaskId, Duration: durationpb.New(time.Second)})
+			return err
+		},
+		func() error {
+			_, err := handler.SnoozeTask(ctx, &v1.SnoozeTaskRequest{TaskId: task.TaskId, Duration: &durationpb.Duration{Seconds: 1, Nanos: -1}})
+			return err
+		},
+		func() error {
+			_, err := handler.ListTaskEvents(ctx, &v1.ListTaskEventsRequest{UserId: uid, Types: []v1.TaskEventType{42}})
+			return err
+		},
+		func() error {
+			_, err := handler.ListTaskEvents(ctx, &v1.ListTaskEventsRequest{
+				UserId: uid,
+				Since:  timestamppb.New(due),
+				Until:  timestamppb.New(due.Add(-time.Hour)),
+			})
+			return err
+		},
+	}
+	for i, call := range invalid {
+		if err := call(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("case %d: expected InvalidArgument, got %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"guiltmachine/internal/auth"
+	"guiltmachine/internal/events"
+	"guiltmachine/internal/ml"
+	v1 "guiltmachine/internal/proto/gen"
+	svcs "guiltmachine/internal/services"
+	grpchandlers "guiltmachine/internal/transport/grpc"
+	"guiltmachine/test/fakes"
+)
+
+func TestWatchEntryAndSession(t *testing.T) {
+	ctx := context.Background()
+	repos := fakes.NewRepos()
+	jwtManager := auth.NewJWTManager("watch-test-secret", time.Hour)
+	bus := events.NewMemoryBus()
+
+	alice, _ := repos.Users.CreateUser(ctx, "alice-watch@test.com", "hash")
+	bob, _ := repos.Users.CreateUser(ctx, "bob-watch@test.com", "hash")
+	aliceSession, _ := repos.Sessions.CreateSession(ctx, alice.ID, nil)
+	bobSession, _ := repos.Sessions.CreateSession(ctx, bob.ID, nil)
+	entry, _ := repos.Entries.CreateEntry(ctx, aliceSession.ID, "forgot mom's birthday", 9)
+
+	aliceToken, _ := jwtManager.Issue(alice.ID.String(), aliceSession.ID.String())
+	bobToken, _ := jwtManager.Issue(bob.ID.String(), bobSession.ID.String())
+
+	apiEntries := svcs.NewEntryServiceWithOutbox(repos.Entries, repos.Scores, nil, nil)
+	apiEntries.SetEventBus(bus)
+	worker := svcs.NewEntryServiceWithHybrid(repos.Entries, repos.Scores, ml.NewHybridOrchestrator(ml.NewInferenceStub()), nil)
+	worker.SetEventBus(bus)
+
+	s := startTestGRPCWithOptions(t, []grpc.ServerOption{
+		grpc.ChainStreamInterceptor(
+			grpchandlers.AuthStreamInterceptor(jwtManager, nil),
+			grpchandlers.PolicyStreamInterceptor(svcs.NewAuthorizer(repos.Sessions, repos.Entries)),
+		),
+	}, func(gs *grpc.Server) {
+		v1.RegisterEntryServiceServer(gs, grpchandlers.NewEntryHandler(apiEntries))
+	})
+	defer s.stop()
+
+	conn, err := grpc.NewClient(s.getAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	entries := v1.NewEntryServiceClient(conn)
+
+	asAlice := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+aliceToken)
+	asBob := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+bobToken)
+
+	recv := func(t *testing.T, stream grpc.ServerStreamingClient[v1.EntryEvent]) *v1.EntryEvent {
+		t.Helper()
+		ev, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("recv failed: %v", err)
+		}
+		return ev
+	}
+
+	t.Run("cross-user watch is denied", func(t *testing.T) {
+		stream, err := entries.WatchSession(asBob, &v1.WatchSessionRequest{SessionId: aliceSession.ID.String()})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if code := status.Code(err); code != codes.PermissionDenied {
+			t.Fatalf("expected PermissionDenied, got %v", code)
+		}
+	})
+
+	t.Run("changes are pushed as the worker writes them", func(t *testing.T) {
+		sessionCtx, stopSession := context.WithCancel(asAlice)
+		defer stopSession()
+		sessionStream, err := entries.WatchSession(sessionCtx, &v1.WatchSessionRequest{SessionId: aliceSession.ID.String()})
+		if err != nil {
+			t.Fatalf("WatchSession failed: %v", err)
+		}
+		entryStream, err := entries.WatchEntry(asAlice, &v1.WatchEntryRequest{EntryId: entry.ID.String()})
+		if err != nil {
+			t.Fatalf("WatchEntry failed: %v", err)
+		}
+
+		// snapshots arrive once the subscriptions are live
+		if ev := recv(t, sessionStream); ev.EntryId != entry.ID.String() || ev.Status != "pending" {
+			t.Fatalf("unexpected session snapshot %+v", ev)
+		}
+		if ev := recv(t, entryStream); ev.Status != "pending" {
+			t.Fatalf("unexpected entry snapshot %+v", ev)
+		}
+
+		if err := worker.ProcessMLJob(ctx, entry.ID.String(), "watch-job"); err != nil {
+			t.Fatalf("ProcessMLJob failed: %v", err)
+		}
+
+		for _, stream := range []grpc.ServerStreamingClient[v1.EntryEvent]{entryStream, sessionStream} {
+			if ev := recv(t, stream); ev.Status != "processing" {
+				t.Fatalf("expected processing, got %+v", ev)
+			}
+			ev := recv(t, stream)
+			if ev.Status != "completed" || ev.RoastText == "" || ev.GuiltScore == 0 {
+				t.Fatalf("expected completed with roast and score, got %+v", ev)
+			}
+		}
+
+		if _, err := entryStream.Recv(); !errors.Is(err, io.EOF) {
+			t.Fatalf("expected WatchEntry to end after completion, got %v", err)
+		}
+	})
+
+	t.Run("failed entry ends the stream", func(t *testing.T) {
+		failing, _ := repos.Entries.CreateEntry(ctx, aliceSession.ID, "ignored the dentist", 5)
+		failer := svcs.NewEntryServiceWithHybrid(repos.Entries, repos.Scores, ml.NewHybridOrchestrator(downLLM{}), nil)
+		failer.SetEventBus(bus)
+
+		stream, err := entries.WatchEntry(asAlice, &v1.WatchEntryRequest{EntryId: failing.ID.String()})
+		if err != nil {
+			t.Fatalf("WatchEntry failed: %v", err)
+		}
+		if ev := recv(t, stream); ev.Status != "pending" {
+			t.Fatalf("unexpected entry snapshot %+v", ev)
+		}
+
+		if err := failer.ProcessMLJob(ctx, failing.ID.String(), "failing-job"); err == nil {
+			t.Fatal("expected ProcessMLJob to fail")
+		}
+		if ev := recv(t, stream); ev.Status != "processing" {
+			t.Fatalf("expected processing, got %+v", ev)
+		}
+		if ev := recv(t, stream); ev.Status != "failed" {
+			t.Fatalf("expected failed, got %+v", ev)
+		}
+		if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+			t.Fatalf("expected WatchEntry to end after failure, got %v", err)
+		}
+	})
+
+	t.Run("completed entry ends after its snapshot", func(t *testing.T) {
+		stream, err := entries.WatchEntry(asAlice, &v1.WatchEntryRequest{EntryId: entry.ID.String()})
+		if err != nil {
+			t.Fatalf("WatchEntry failed: %v", err)
+		}
+		if ev := recv(t, stream); ev.Status != "completed" || ev.RoastText == "" {
+			t.Fatalf("unexpected snapshot %+v", ev)
+		}
+		if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+			t.Fatalf("expected end of stream, got %v", err)
+		}
+	})
+}
+
+// downLLM fails every roast
+type downLLM struct{}
+
+func (downLLM) Generate(ctx context.Context, in ml.HybridInput) (string, error) {
+	return "", errors.New("llm unavailable")
+}